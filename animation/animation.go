@@ -0,0 +1,123 @@
+package animation
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+var ErrUnknownFormat = errors.New("unknown animation format")
+
+// Animation paints the same tree once per frame, advancing Clock between frames
+type Animation struct {
+	Root     render_objects.RenderObject
+	Clock    *Clock
+	Size     types.Size
+	FPS      int // 30 when zero
+	Duration time.Duration
+	Loops    int // Number of times the animation plays, 0 repeats forever
+
+	PixelRatio float64 // Device pixels per logical pixel, 1 when zero
+}
+
+func (a *Animation) fps() int {
+	if a.FPS <= 0 {
+		return 30
+	}
+	return a.FPS
+}
+
+// FrameCount is the number of frames needed to cover Duration, at least one
+func (a *Animation) FrameCount() int {
+	return max(int(math.Ceil(a.Duration.Seconds()*float64(a.fps()))), 1)
+}
+
+// FrameTime returns the time shown by frame i
+func (a *Animation) FrameTime(i int) time.Duration {
+	return time.Duration(i) * time.Second / time.Duration(a.fps())
+}
+
+// Frame paints frame i onto a new canvas
+func (a *Animation) Frame(i int) *cv.Canvas {
+	if a.Clock != nil {
+		a.Clock.Set(a.FrameTime(i))
+	}
+	canvas := cv.NewScaledCanvas(a.Size, a.PixelRatio, true)
+	a.Root.Paint(canvas)
+	return canvas
+}
+
+// frame is a rendered frame shown for a number of frame intervals
+type frame struct {
+	img   *image.RGBA
+	ticks int
+}
+
+// frames renders every frame, merging runs of identical frames into one longer frame
+func (a *Animation) frames() []frame {
+	var frames []frame
+	for i := range a.FrameCount() {
+		img := a.Frame(i).Img
+		if n := len(frames); n > 0 && bytes.Equal(frames[n-1].img.Pix, img.Pix) {
+			frames[n-1].ticks++
+			continue
+		}
+		frames = append(frames, frame{img: img, ticks: 1})
+	}
+	return frames
+}
+
+// changedBounds returns the smallest rectangle containing every pixel that differs
+// between two frames, given as 4 byte per pixel buffers of an image of bounds
+func changedBounds(prev, cur []uint8, bounds image.Rectangle) image.Rectangle {
+	changed := image.Rectangle{}
+	stride := 4 * bounds.Dx()
+	for y := range bounds.Dy() {
+		row := y * stride
+		if bytes.Equal(prev[row:row+stride], cur[row:row+stride]) {
+			continue
+		}
+		for x := range bounds.Dx() {
+			i := row + 4*x
+			if !bytes.Equal(prev[i:i+4], cur[i:i+4]) {
+				p := bounds.Min.Add(image.Pt(x, y))
+				changed = changed.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+			}
+		}
+	}
+	return changed
+}
+
+// SaveFile encodes the animation into a file: .gif for GIF, .png or .apng for APNG
+func (a *Animation) SaveFile(path string) (err error) {
+	var encode func(w io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gif":
+		encode = func(w io.Writer) error { return a.EncodeGIF(w, nil) }
+	case ".png", ".apng":
+		encode = a.EncodeAPNG
+	default:
+		return ErrUnknownFormat
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return encode(file)
+}
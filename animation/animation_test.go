@@ -0,0 +1,140 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// slidingSquare moves a red square right by 10px per second and stops after 2 seconds
+func slidingSquare() *Animation {
+	clock := &Clock{}
+	x := Tween{From: 0, To: 20, Duration: 2 * time.Second}
+	root := &render_objects.Painter{Painter: func(c *cv.Canvas) {
+		c.Rectangle(int(x.At(clock.Now())), 5, 10, 10, cv.Red, true)
+	}}
+	return &Animation{Root: root, Clock: clock, Size: types.Size{Width: 40, Height: 20}, FPS: 2, Duration: 3 * time.Second}
+}
+
+func TestFrames(t *testing.T) {
+	anim := slidingSquare()
+	if anim.FrameCount() != 6 {
+		t.Fatalf("Expected 6 frames, got %d", anim.FrameCount())
+	}
+	if anim.FrameTime(3) != 1500*time.Millisecond {
+		t.Errorf("Expected frame 3 at 1.5s, got %v", anim.FrameTime(3))
+	}
+	if anim.Frame(2).Img.RGBAAt(12, 6) != cv.Red {
+		t.Error("Expected the square to have moved 10px after 1 second")
+	}
+
+	// The last frames are identical once the square stops
+	frames := anim.frames()
+	if len(frames) != 5 || frames[4].ticks != 2 {
+		t.Errorf("Expected the two still frames to merge, got %d frames", len(frames))
+	}
+}
+
+func TestEncodeGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := slidingSquare().EncodeGIF(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	out, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.Image) != 5 {
+		t.Fatalf("Expected 5 frames, got %d", len(out.Image))
+	}
+	total := 0
+	for _, delay := range out.Delay {
+		total += delay
+	}
+	if total != 300 {
+		t.Errorf("Expected the delays to add up to 3 seconds, got %d", total)
+	}
+	if out.Delay[4] != 100 {
+		t.Errorf("Expected the merged still frame to last 1 second, got %d", out.Delay[4])
+	}
+
+	// Later frames only cover the 5px the square moved plus its old and new edges
+	if bounds := out.Image[1].Bounds(); bounds.Dx() != 15 || bounds.Dy() != 10 {
+		t.Errorf("Expected the second frame to be cropped to 15x10, got %v", bounds)
+	}
+	if _, ok := out.Config.ColorModel.(color.Palette); !ok {
+		t.Error("Expected the few colors to share a global palette")
+	}
+
+	// Playing the frames back, honoring disposal, reproduces every rendered frame
+	screen := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for i, frame := range out.Image {
+		draw.Draw(screen, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		expected := slidingSquare().Frame(i).Img
+		for y := range 20 {
+			for x := range 40 {
+				_, _, _, wantA := expected.At(x, y).RGBA()
+				_, _, _, gotA := screen.At(x, y).RGBA()
+				if (wantA == 0) != (gotA == 0) {
+					t.Fatalf("Expected frame %d to match at (%d,%d)", i, x, y)
+				}
+			}
+		}
+		if out.Disposal[i] == gif.DisposalBackground {
+			draw.Draw(screen, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		}
+	}
+}
+
+func TestEncodeAPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := slidingSquare().EncodeAPNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// Viewers without APNG support see the first frame
+	first, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := first.At(2, 6).RGBA(); r>>8 != 255 {
+		t.Errorf("Expected the square in the first frame, got %v", first.At(2, 6))
+	}
+
+	// Walk the chunks, checking the sequence numbers of the animation chunks
+	var names []string
+	sequence := uint32(0)
+	for pos := 8; pos < len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		name := string(data[pos+4 : pos+8])
+		names = append(names, name)
+		if name == "fcTL" || name == "fdAT" {
+			if got := binary.BigEndian.Uint32(data[pos+8:]); got != sequence {
+				t.Errorf("Expected sequence number %d, got %d", sequence, got)
+			}
+			sequence++
+		}
+		if name == "fcTL" && sequence > 1 {
+			if bounds := image.Rect(0, 0, int(binary.BigEndian.Uint32(data[pos+12:])), int(binary.BigEndian.Uint32(data[pos+16:]))); bounds.Dx() != 15 {
+				t.Errorf("Expected later frames to be cropped to 15px, got %v", bounds)
+			}
+			break
+		}
+		pos += 12 + length
+	}
+	if names[0] != "IHDR" || names[1] != "acTL" || names[2] != "fcTL" || names[3] != "IDAT" {
+		t.Errorf("Expected IHDR, acTL, fcTL, IDAT, got %v", names)
+	}
+}
@@ -0,0 +1,127 @@
+package animation
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+)
+
+// APNG frame disposal and blending operations
+const (
+	apngDisposeNone = 0
+	apngBlendSource = 0
+)
+
+// EncodeAPNG writes the animation as an animated PNG with full alpha.
+// Viewers without APNG support show the first frame. Frames after the first only
+// store the rectangle that changed since the previous one.
+func (a *Animation) EncodeAPNG(w io.Writer) error {
+	frames := a.frames()
+	png := &apngWriter{w: w}
+
+	png.write([]byte("\x89PNG\r\n\x1a\n"))
+	header := make([]byte, 13)
+	bounds := frames[0].img.Bounds()
+	binary.BigEndian.PutUint32(header[0:], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(header[4:], uint32(bounds.Dy()))
+	header[8] = 8 // Bit depth
+	header[9] = 6 // Truecolor with alpha
+	png.chunk("IHDR", header)
+
+	control := make([]byte, 8)
+	binary.BigEndian.PutUint32(control[0:], uint32(len(frames)))
+	binary.BigEndian.PutUint32(control[4:], uint32(max(a.Loops, 0)))
+	png.chunk("acTL", control)
+
+	sequence := uint32(0)
+	for i, f := range frames {
+		bounds := f.img.Bounds()
+		if i > 0 {
+			bounds = changedBounds(frames[i-1].img.Pix, f.img.Pix, bounds)
+			if bounds.Empty() {
+				bounds = image.Rect(0, 0, 1, 1)
+			}
+		}
+
+		// Each frame shows for its number of frame intervals
+		fc := make([]byte, 26)
+		binary.BigEndian.PutUint32(fc[0:], sequence)
+		binary.BigEndian.PutUint32(fc[4:], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fc[8:], uint32(bounds.Dy()))
+		binary.BigEndian.PutUint32(fc[12:], uint32(bounds.Min.X))
+		binary.BigEndian.PutUint32(fc[16:], uint32(bounds.Min.Y))
+		binary.BigEndian.PutUint16(fc[20:], uint16(f.ticks))
+		binary.BigEndian.PutUint16(fc[22:], uint16(a.fps()))
+		fc[24] = apngDisposeNone
+		fc[25] = apngBlendSource
+		png.chunk("fcTL", fc)
+		sequence++
+
+		data, err := compressRows(f.img, bounds)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			png.chunk("IDAT", data)
+			continue
+		}
+		fd := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fd, sequence)
+		png.chunk("fdAT", append(fd, data...))
+		sequence++
+	}
+
+	png.chunk("IEND", nil)
+	return png.err
+}
+
+// compressRows returns the zlib compressed scanlines of an area of img as non-premultiplied RGBA
+func compressRows(img *image.RGBA, bounds image.Rectangle) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, 1+4*bounds.Dx())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		// Filter type 0, the row as is
+		row[0] = 0
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.RGBAAt(x, y)).(color.NRGBA)
+			copy(row[1+4*(x-bounds.Min.X):], []byte{c.R, c.G, c.B, c.A})
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// apngWriter writes PNG chunks, keeping the first error
+type apngWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *apngWriter) write(b []byte) {
+	if p.err == nil {
+		_, p.err = p.w.Write(b)
+	}
+}
+
+func (p *apngWriter) chunk(name string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], name)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	p.write(header)
+	p.write(data)
+	p.write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+}
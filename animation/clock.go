@@ -0,0 +1,45 @@
+package animation
+
+import (
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Clock holds the time of the frame being painted. An Animation advances it
+// before every frame; render objects read it while painting.
+type Clock struct {
+	t time.Duration
+}
+
+// Now returns the time of the current frame since the start of the animation
+func (c *Clock) Now() time.Duration {
+	return c.t
+}
+
+// Set moves the clock to t
+func (c *Clock) Set(t time.Duration) {
+	c.t = t
+}
+
+// Builder rebuilds its child from the clock's time every time it is laid out or painted.
+// Parents cache their layout by size, so a Builder whose child changes size over
+// time should sit at the root of the tree.
+type Builder struct {
+	Clock *Clock
+	Build func(t time.Duration) render_objects.RenderObject
+}
+
+func (b *Builder) child() render_objects.RenderObject {
+	return b.Build(b.Clock.Now())
+}
+
+func (b *Builder) Paint(canvas *cv.Canvas) {
+	render_objects.PaintChild(b.child(), canvas)
+}
+
+func (b *Builder) Size(parentSize types.Size) types.Size {
+	return b.child().Size(parentSize)
+}
@@ -0,0 +1,196 @@
+package animation
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+
+	cv "github.com/hvuhsg/render/canvas"
+)
+
+// GIFOptions tunes GIF encoding
+type GIFOptions struct {
+	Dither bool // Diffuse quantization error when a frame has more than 256 colors
+}
+
+// EncodeGIF writes the animation as an animated GIF.
+// When every frame fits in one 256 color palette it is shared by all frames, otherwise each
+// frame gets its own. Frames only store the rectangle that changed since the previous one,
+// with unchanged pixels left transparent.
+func (a *Animation) EncodeGIF(w io.Writer, options *GIFOptions) error {
+	if options == nil {
+		options = &GIFOptions{}
+	}
+
+	frames := a.frames()
+	images := make([]*image.NRGBA, len(frames))
+	for i, f := range frames {
+		images[i] = opaqueOrClear(f.img)
+	}
+	global := exactPalette(images)
+
+	out := &gif.GIF{LoopCount: gifLoopCount(a.Loops)}
+	if global != nil {
+		global, _ = withTransparent(global)
+		out.Config = image.Config{ColorModel: global, Width: images[0].Bounds().Dx(), Height: images[0].Bounds().Dy()}
+	}
+
+	// Plan the rectangle each frame covers. Pixels that turn transparent can't be drawn over the
+	// previous frame, so that frame covers them too and is cleared before the next one is drawn.
+	rects := make([]image.Rectangle, len(images))
+	disposals := make([]byte, len(images))
+	rects[0] = images[0].Bounds()
+	for i := range disposals {
+		disposals[i] = gif.DisposalNone
+		if i+1 < len(images) && !clearedBounds(images[i], images[i+1]).Empty() {
+			disposals[i] = gif.DisposalBackground
+		}
+	}
+	for i := 1; i < len(images); i++ {
+		rect := changedBounds(images[i-1].Pix, images[i].Pix, images[i].Bounds())
+		if disposals[i-1] == gif.DisposalBackground {
+			// Whatever is left inside the cleared rectangle has to be drawn again
+			rect = rect.Union(opaqueBounds(images[i], rects[i-1]))
+		}
+		if i+1 < len(images) {
+			rect = rect.Union(clearedBounds(images[i], images[i+1]))
+		}
+		if rect.Empty() {
+			rect = image.Rect(0, 0, 1, 1)
+		}
+		rects[i] = rect
+	}
+
+	elapsed := 0
+	for i, img := range images {
+		bounds := rects[i]
+		palette := global
+		if palette == nil {
+			palette = cv.Quantize(img.SubImage(bounds), 255)
+		}
+		palette, transparent := withTransparent(palette)
+
+		paletted := image.NewPaletted(bounds, palette)
+		var drawer draw.Drawer = draw.Src
+		if options.Dither && global == nil {
+			drawer = draw.FloydSteinberg
+		}
+		drawer.Draw(paletted, bounds, img, bounds.Min)
+		if i > 0 {
+			// Unchanged pixels show through from the previous frame, unless it was cleared there
+			prev := images[i-1]
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					if disposals[i-1] == gif.DisposalBackground && image.Pt(x, y).In(rects[i-1]) {
+						continue
+					}
+					if prev.NRGBAAt(x, y) == img.NRGBAAt(x, y) {
+						paletted.SetColorIndex(x, y, transparent)
+					}
+				}
+			}
+		}
+
+		// Delays are in hundredths of a second; round the running total so rounding errors don't add up
+		end := elapsed + frames[i].ticks
+		delay := int(math.Round(float64(end)*100/float64(a.fps()))) - int(math.Round(float64(elapsed)*100/float64(a.fps())))
+		elapsed = end
+
+		out.Image = append(out.Image, paletted)
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, disposals[i])
+	}
+	return gif.EncodeAll(w, out)
+}
+
+// gifLoopCount converts a number of plays to the GIF loop count, which counts repeats
+func gifLoopCount(loops int) int {
+	switch {
+	case loops <= 0:
+		return 0
+	case loops == 1:
+		return -1
+	}
+	return loops - 1
+}
+
+// opaqueOrClear converts a frame to GIF's all or nothing transparency
+func opaqueOrClear(img *image.RGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.RGBAAt(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				c = color.NRGBA{}
+			} else {
+				c.A = 255
+			}
+			out.SetNRGBA(x, y, c)
+		}
+	}
+	return out
+}
+
+// clearedBounds returns the bounds of the pixels visible in prev that are transparent in cur
+func clearedBounds(prev, cur *image.NRGBA) image.Rectangle {
+	bounds := image.Rectangle{}
+	for y := cur.Rect.Min.Y; y < cur.Rect.Max.Y; y++ {
+		for x := cur.Rect.Min.X; x < cur.Rect.Max.X; x++ {
+			if cur.NRGBAAt(x, y).A == 0 && prev.NRGBAAt(x, y).A != 0 {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
+
+// opaqueBounds returns the bounds of the visible pixels of img inside area
+func opaqueBounds(img *image.NRGBA, area image.Rectangle) image.Rectangle {
+	bounds := image.Rectangle{}
+	area = area.Intersect(img.Rect)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if img.NRGBAAt(x, y).A != 0 {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
+
+// exactPalette returns a palette holding every color of the frames, or nil when
+// there are too many colors to share one palette
+func exactPalette(images []*image.NRGBA) color.Palette {
+	seen := map[color.NRGBA]bool{}
+	var palette color.Palette
+	for _, img := range images {
+		for i := 0; i < len(img.Pix); i += 4 {
+			c := color.NRGBA{R: img.Pix[i], G: img.Pix[i+1], B: img.Pix[i+2], A: img.Pix[i+3]}
+			if seen[c] {
+				continue
+			}
+			// Leave room for the transparent entry
+			if len(palette) == 255 {
+				return nil
+			}
+			seen[c] = true
+			palette = append(palette, c)
+		}
+	}
+	return palette
+}
+
+// withTransparent returns the palette with a fully transparent entry and its index
+func withTransparent(palette color.Palette) (color.Palette, uint8) {
+	for i, c := range palette {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return palette, uint8(i)
+		}
+	}
+	palette = append(palette[:len(palette):len(palette)], color.NRGBA{})
+	return palette, uint8(len(palette) - 1)
+}
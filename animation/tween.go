@@ -0,0 +1,74 @@
+package animation
+
+import (
+	"image/color"
+	"math"
+	"time"
+)
+
+// Easing maps the linear progress of a tween, from 0 to 1, to the progress shown
+type Easing func(p float64) float64
+
+var (
+	Linear    Easing = func(p float64) float64 { return p }
+	EaseIn    Easing = func(p float64) float64 { return p * p * p }
+	EaseOut   Easing = func(p float64) float64 { return 1 - math.Pow(1-p, 3) }
+	EaseInOut Easing = func(p float64) float64 {
+		if p < 0.5 {
+			return 4 * p * p * p
+		}
+		return 1 - math.Pow(-2*p+2, 3)/2
+	}
+)
+
+// Tween animates a value from From to To over Duration, starting after Delay
+type Tween struct {
+	From, To  float64
+	Delay     time.Duration
+	Duration  time.Duration
+	Easing    Easing // Linear when nil
+	Repeat    bool   // Start over after every Duration instead of stopping at To
+	Alternate bool   // When repeating, run every other cycle backwards
+}
+
+// Progress returns the eased progress of the tween at time t, from 0 to 1
+func (tw Tween) Progress(t time.Duration) float64 {
+	t -= tw.Delay
+	if t <= 0 {
+		return tw.ease(0)
+	}
+	if tw.Duration <= 0 {
+		return tw.ease(1)
+	}
+
+	cycle := int64(t / tw.Duration)
+	p := float64(t%tw.Duration) / float64(tw.Duration)
+	if !tw.Repeat && cycle > 0 {
+		return tw.ease(1)
+	}
+	if tw.Repeat && tw.Alternate && cycle%2 == 1 {
+		p = 1 - p
+	}
+	return tw.ease(p)
+}
+
+func (tw Tween) ease(p float64) float64 {
+	if tw.Easing == nil {
+		return p
+	}
+	return tw.Easing(p)
+}
+
+// At returns the value of the tween at time t
+func (tw Tween) At(t time.Duration) float64 {
+	return tw.From + (tw.To-tw.From)*tw.Progress(t)
+}
+
+// LerpColor blends from a to b, p being 0 for a and 1 for b
+func LerpColor(a, b color.RGBA, p float64) color.RGBA {
+	p = min(max(p, 0), 1)
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*p))
+	}
+	return color.RGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: lerp(a.A, b.A)}
+}
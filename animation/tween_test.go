@@ -0,0 +1,55 @@
+package animation
+
+import (
+	"image/color"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTween(t *testing.T) {
+	tween := Tween{From: 10, To: 20, Delay: time.Second, Duration: 2 * time.Second}
+
+	cases := map[time.Duration]float64{
+		0:                       10,
+		time.Second:             10,
+		2 * time.Second:         15,
+		3 * time.Second:         20,
+		10 * time.Second:        20,
+		1500 * time.Millisecond: 12.5,
+	}
+	for at, expected := range cases {
+		if v := tween.At(at); math.Abs(v-expected) > 1e-9 {
+			t.Errorf("Expected %v at %v, got %v", expected, at, v)
+		}
+	}
+}
+
+func TestTweenRepeat(t *testing.T) {
+	tween := Tween{From: 0, To: 1, Duration: time.Second, Repeat: true, Alternate: true}
+
+	if v := tween.At(1250 * time.Millisecond); math.Abs(v-0.75) > 1e-9 {
+		t.Errorf("Expected the second cycle to run backwards, got %v", v)
+	}
+	if v := tween.At(2250 * time.Millisecond); math.Abs(v-0.25) > 1e-9 {
+		t.Errorf("Expected the third cycle to run forwards, got %v", v)
+	}
+}
+
+func TestEasing(t *testing.T) {
+	for name, easing := range map[string]Easing{"Linear": Linear, "EaseIn": EaseIn, "EaseOut": EaseOut, "EaseInOut": EaseInOut} {
+		if easing(0) != 0 || math.Abs(easing(1)-1) > 1e-9 {
+			t.Errorf("Expected %s to run from 0 to 1", name)
+		}
+	}
+	if EaseIn(0.5) >= 0.5 || EaseOut(0.5) <= 0.5 {
+		t.Error("Expected EaseIn to start slow and EaseOut to start fast")
+	}
+}
+
+func TestLerpColor(t *testing.T) {
+	mid := LerpColor(color.RGBA{0, 0, 0, 255}, color.RGBA{255, 100, 0, 255}, 0.5)
+	if mid != (color.RGBA{128, 50, 0, 255}) {
+		t.Errorf("Expected the halfway color, got %v", mid)
+	}
+}
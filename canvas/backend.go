@@ -0,0 +1,121 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hvuhsg/render/types"
+)
+
+// Backend receives the drawing operations of a vector canvas instead of them being
+// rasterized into Img. Coordinates are absolute, sub canvas offsets already applied.
+type Backend interface {
+	Rectangle(x, y, w, h int, color color.RGBA, fill bool)
+	Circle(x, y, r int, color color.RGBA, fill bool)
+	Line(x1, y1, x2, y2 int, color color.RGBA, width int)
+	Polygon(points [][2]int, color color.RGBA, fill bool)
+	// Text draws text with its top left corner at (x, y), like DrawText
+	Text(text string, x, y int, painter *TextPainter)
+	RoundedRect(x, y, w, h int, radii Radii, shader Shader)
+	RoundedBorder(x, y, w, h int, radii Radii, sides BorderSides)
+	BoxShadow(x, y, w, h int, radii Radii, shadow BoxShadow)
+	// Image draws an already rasterized image scaled to size
+	Image(x, y int, size types.Size, img image.Image)
+	// BeginGroup starts a group of operations drawn through transform and then
+	// composited with opacity and mode, until the matching EndGroup
+	BeginGroup(transform Matrix, opacity float64, mode BlendMode)
+	EndGroup()
+}
+
+// Clipper is implemented by backends that clip drawing to the canvas it was made on, as
+// raster canvases do. Clip is called before every drawing operation and group with the
+// bounds of the canvas in absolute coordinates, or an empty rectangle when the drawing
+// stays inside the canvas and needs no clipping.
+type Clipper interface {
+	Clip(bounds image.Rectangle)
+}
+
+// clip tells a Clipper backend whether drawing covering (x, y, w, h) of the canvas needs clipping
+func (c *Canvas) clip(x, y, w, h int) {
+	clipper, ok := c.backend.(Clipper)
+	if !ok {
+		return
+	}
+	if x >= 0 && y >= 0 && x+w <= c.Size.Width && y+h <= c.Size.Height {
+		clipper.Clip(image.Rectangle{})
+		return
+	}
+	clipper.Clip(c.bounds())
+}
+
+// clipTransformed is like clip for an area of size drawn through m
+func (c *Canvas) clipTransformed(m Matrix, size types.Size) {
+	x0, y0, x1, y1 := m.bounds(float64(size.Width), float64(size.Height))
+	c.clip(x0, y0, x1-x0, y1-y0)
+}
+
+// NewVectorCanvas creates a canvas that forwards every drawing operation to backend.
+// Vector canvases have no Img; effects that need pixels are rendered on raster
+// layers and handed to the backend as images.
+func NewVectorCanvas(size types.Size, backend Backend) *Canvas {
+	return &Canvas{
+		Size:             size,
+		offset:           image.Point{X: 0, Y: 0},
+		AllowOutOfBounds: true,
+		backend:          backend,
+	}
+}
+
+// IsVector reports whether the canvas draws to a Backend rather than to Img
+func (c *Canvas) IsVector() bool {
+	return c.backend != nil
+}
+
+// Backend returns the backend of a vector canvas, or nil for raster canvases
+func (c *Canvas) Backend() Backend {
+	return c.backend
+}
+
+// absolute converts a matrix working in canvas coordinates to one in absolute coordinates
+func (c *Canvas) absolute(m Matrix) Matrix {
+	return Identity().Translate(float64(c.offset.X), float64(c.offset.Y)).
+		Multiply(m).
+		Multiply(Identity().Translate(-float64(c.offset.X), -float64(c.offset.Y)))
+}
+
+// PaintTransformed calls paint with a canvas of size whose drawing is mapped through m
+// onto this canvas. Vector canvases keep the drawing as a transformed group, raster
+// canvases paint into a layer and resample it.
+func (c *Canvas) PaintTransformed(size types.Size, m Matrix, paint func(canvas *Canvas)) {
+	if c.backend != nil {
+		c.clipTransformed(m, size)
+		c.backend.BeginGroup(c.absolute(m), 1, BlendNormal)
+		paint(c.SubCanvas(0, 0, size, nil))
+		c.backend.EndGroup()
+		return
+	}
+
+	layer := c.NewOffscreenLayer(size)
+	layer.Follow(c, 0, 0)
+	paint(layer.Canvas)
+	c.DrawLayerTransformed(layer, m)
+}
+
+// PaintLayer calls paint with a canvas of the same size whose drawing is composited
+// onto this canvas as one image with opacity and mode
+func (c *Canvas) PaintLayer(opacity float64, mode BlendMode, paint func(canvas *Canvas)) {
+	if c.backend != nil {
+		c.clip(0, 0, c.Size.Width, c.Size.Height)
+		c.backend.BeginGroup(Identity(), opacity, mode)
+		paint(c.SubCanvas(0, 0, c.Size, nil))
+		c.backend.EndGroup()
+		return
+	}
+
+	layer := c.NewOffscreenLayer(c.Size)
+	layer.Follow(c, 0, 0)
+	layer.Opacity = opacity
+	layer.BlendMode = mode
+	paint(layer.Canvas)
+	c.DrawLayer(layer, 0, 0)
+}
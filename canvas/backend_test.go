@@ -0,0 +1,88 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+// recorder is a Backend that remembers the operations it receives
+type recorder struct {
+	ops  []string
+	rect image.Point
+}
+
+func (r *recorder) Rectangle(x, y, w, h int, color color.RGBA, fill bool) {
+	r.ops = append(r.ops, "rect")
+	r.rect = image.Point{X: x, Y: y}
+}
+func (r *recorder) Circle(x, y, radius int, color color.RGBA, fill bool) {
+	r.ops = append(r.ops, "circle")
+}
+func (r *recorder) Line(x1, y1, x2, y2 int, color color.RGBA, width int) {
+	r.ops = append(r.ops, "line")
+}
+func (r *recorder) Polygon(points [][2]int, color color.RGBA, fill bool) {
+	r.ops = append(r.ops, "polygon")
+}
+func (r *recorder) Text(text string, x, y int, painter *TextPainter) { r.ops = append(r.ops, "text") }
+func (r *recorder) RoundedRect(x, y, w, h int, radii Radii, shader Shader) {
+	r.ops = append(r.ops, "rrect")
+}
+func (r *recorder) BoxShadow(x, y, w, h int, radii Radii, shadow BoxShadow) {
+	r.ops = append(r.ops, "shadow")
+}
+func (r *recorder) RoundedBorder(x, y, w, h int, radii Radii, sides BorderSides) {
+	r.ops = append(r.ops, "border")
+}
+func (r *recorder) Image(x, y int, size types.Size, img image.Image) { r.ops = append(r.ops, "image") }
+func (r *recorder) BeginGroup(transform Matrix, opacity float64, mode BlendMode) {
+	r.ops = append(r.ops, "group")
+}
+func (r *recorder) EndGroup() { r.ops = append(r.ops, "end") }
+
+func TestVectorCanvasForwardsOperations(t *testing.T) {
+	rec := &recorder{}
+	canvas := NewVectorCanvas(types.Size{Width: 100, Height: 100}, rec)
+	if !canvas.IsVector() || canvas.Img != nil {
+		t.Fatal("Expected a vector canvas without pixels")
+	}
+
+	sub := canvas.SubCanvas(10, 20, types.Size{Width: 50, Height: 50}, nil)
+	sub.Rectangle(5, 5, 10, 10, Red, true)
+	sub.Line(0, 0, 10, 10, Red, 3)
+	sub.Blur(4)
+	sub.PaintLayer(0.5, BlendNormal, func(c *Canvas) {
+		c.DrawText("hi", 0, 0, nil)
+	})
+
+	if rec.rect != (image.Point{X: 15, Y: 25}) {
+		t.Errorf("Expected the rectangle to be offset to (15,25), got %v", rec.rect)
+	}
+
+	expected := []string{"rect", "line", "group", "text", "end"}
+	if len(rec.ops) != len(expected) {
+		t.Fatalf("Expected operations %v, got %v", expected, rec.ops)
+	}
+	for i, op := range expected {
+		if rec.ops[i] != op {
+			t.Errorf("Expected operation %d to be %s, got %s", i, op, rec.ops[i])
+		}
+	}
+}
+
+func TestPaintTransformedRaster(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 20, Height: 20}, false)
+	canvas.PaintTransformed(types.Size{Width: 5, Height: 5}, Identity().Translate(10, 10), func(c *Canvas) {
+		c.Rectangle(0, 0, 5, 5, Red, true)
+	})
+
+	if canvas.Img.RGBAAt(12, 12) != Red {
+		t.Errorf("Expected the translated square at (12,12), got %v", canvas.Img.RGBAAt(12, 12))
+	}
+	if canvas.Img.RGBAAt(2, 2).A != 0 {
+		t.Errorf("Expected nothing at (2,2), got %v", canvas.Img.RGBAAt(2, 2))
+	}
+}
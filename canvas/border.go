@@ -0,0 +1,136 @@
+package canvas
+
+import (
+	"image/color"
+	"math"
+)
+
+// BorderStyle is the line style of one side of a border or of a table rule.
+// Solid is the zero value, so a side only needs a width and a color.
+type BorderStyle int
+
+const (
+	BorderStyleSolid BorderStyle = iota
+	BorderStyleDashed
+	BorderStyleDotted
+	BorderStyleNone
+)
+
+// BorderSide describes one side of a border
+type BorderSide struct {
+	Width int
+	Color color.RGBA
+	Style BorderStyle
+}
+
+func (s BorderSide) thickness() int {
+	if s.Style == BorderStyleNone {
+		return 0
+	}
+	return s.Width
+}
+
+// BorderSides holds the four sides of a border
+type BorderSides struct {
+	Top    BorderSide
+	Right  BorderSide
+	Bottom BorderSide
+	Left   BorderSide
+}
+
+// UniformBorder returns border sides that are all the same
+func UniformBorder(side BorderSide) BorderSides {
+	return BorderSides{Top: side, Right: side, Bottom: side, Left: side}
+}
+
+// Widths returns the thickness of the top, right, bottom and left sides
+func (b BorderSides) Widths() (top, right, bottom, left int) {
+	return b.Top.thickness(), b.Right.thickness(), b.Bottom.thickness(), b.Left.thickness()
+}
+
+// RoundedBorder draws a border along the inside edge of a rounded rectangle.
+// Each side has its own width, color and style; corners are split between the
+// adjacent sides. Drawing is clipped to the canvas.
+func (c *Canvas) RoundedBorder(x, y, w, h int, radii Radii, sides BorderSides) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if c.backend != nil {
+		c.clip(x, y, w, h)
+		c.backend.RoundedBorder(c.offset.X+x, c.offset.Y+y, w, h, radii, sides)
+		return
+	}
+	if c.scaled() {
+		for _, side := range []*BorderSide{&sides.Top, &sides.Right, &sides.Bottom, &sides.Left} {
+			side.Width = c.deviceLength(side.Width)
+		}
+		x0, y0, x1, y1 := c.deviceX(x), c.deviceY(y), c.deviceX(x+w), c.deviceY(y+h)
+		c.device().RoundedBorder(x0, y0, x1-x0, y1-y0, radii.scaled(c.pixelRatio), sides)
+		return
+	}
+
+	top, right, bottom, left := sides.Widths()
+	outer := newRoundedRect(float64(x), float64(y), float64(w), float64(h), radii)
+	inner := outer.inset(float64(top), float64(right), float64(bottom), float64(left))
+
+	x0, y0, x1, y1 := c.clipRect(x, y, w, h)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			coverage := outer.coverage(px, py) * (1 - inner.coverage(px, py))
+			if coverage <= 0 {
+				continue
+			}
+
+			side, along := sides.sideAt(float64(px-x)+0.5, float64(py-y)+0.5, float64(w), float64(h))
+			if side.thickness() == 0 || !side.paintsAt(along) {
+				continue
+			}
+			c.blend(px, py, scaleAlpha(side.Color, coverage))
+		}
+	}
+}
+
+// sideAt picks the side a point of the border belongs to, relative to each side's
+// width, and returns it with the point's position along that side
+func (b BorderSides) sideAt(px, py, w, h float64) (BorderSide, float64) {
+	ratio := func(distance float64, width int) float64 {
+		if width == 0 {
+			return math.Inf(1)
+		}
+		return distance / float64(width)
+	}
+
+	side, along := b.Top, px
+	best := ratio(py, b.Top.thickness())
+	if r := ratio(h-py, b.Bottom.thickness()); r < best {
+		side, along, best = b.Bottom, px, r
+	}
+	if r := ratio(px, b.Left.thickness()); r < best {
+		side, along, best = b.Left, py, r
+	}
+	if r := ratio(w-px, b.Right.thickness()); r < best {
+		side, along = b.Right, py
+	}
+	return side, along
+}
+
+// paintsAt reports whether the side's style paints at the given position along the side
+func (s BorderSide) paintsAt(along float64) bool {
+	dash, gap := s.Style.Dashes(s.Width)
+	if dash == 0 {
+		return true
+	}
+	return math.Mod(along, float64(dash+gap)) < float64(dash)
+}
+
+// Dashes returns the length of the dashes and of the gaps between them for a line of
+// the style and width, or zeros when the style isn't dashed or dotted
+func (s BorderStyle) Dashes(width int) (dash, gap int) {
+	switch s {
+	case BorderStyleDashed:
+		return max(3*width, 4), max(2*width, 3)
+	case BorderStyleDotted:
+		return max(width, 1), max(width, 2)
+	}
+	return 0, 0
+}
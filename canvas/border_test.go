@@ -0,0 +1,51 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestRoundedBorder(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	transparent := color.RGBA{0, 0, 0, 0}
+
+	canvas.RoundedBorder(0, 0, 100, 100, Radii{}, BorderSides{
+		Top:  BorderSide{Width: 4, Color: red},
+		Left: BorderSide{Width: 10, Color: blue},
+	})
+
+	if canvas.Img.At(50, 0) != red || canvas.Img.At(50, 3) != red || canvas.Img.At(50, 4) != transparent {
+		t.Error("Expected a 4px red top border")
+	}
+	if canvas.Img.At(0, 50) != blue || canvas.Img.At(9, 50) != blue || canvas.Img.At(10, 50) != transparent {
+		t.Error("Expected a 10px blue left border")
+	}
+	if canvas.Img.At(99, 50) != transparent || canvas.Img.At(50, 99) != transparent {
+		t.Error("Expected no right or bottom border")
+	}
+}
+
+func TestRoundedBorderDashed(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 20}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	canvas.RoundedBorder(0, 0, 100, 20, Radii{}, BorderSides{
+		Top: BorderSide{Width: 2, Color: red, Style: BorderStyleDashed},
+	})
+
+	painted, gaps := 0, 0
+	for x := 0; x < 100; x++ {
+		if canvas.Img.At(x, 0) == red {
+			painted++
+		} else {
+			gaps++
+		}
+	}
+	if painted == 0 || gaps == 0 {
+		t.Errorf("Expected a dashed border, got %d painted and %d empty pixels", painted, gaps)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"image"
 	"image/color"
+	"time"
 
 	"github.com/hvuhsg/render/types"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
 )
 
 var ErrOutOfBounds = errors.New("object is trying to be painted out of bounds")
@@ -15,6 +18,13 @@ type Canvas struct {
 	Size             types.Size
 	offset           image.Point
 	AllowOutOfBounds bool
+	backend          Backend
+	pixelRatio       float64 // Device pixels per logical pixel, see NewScaledCanvas
+	tracer           Tracer
+	origin           image.Point // Position of the image in the coordinates of Bounds, see Follow
+	traceScale       [2]float64  // Scale of the image in the coordinates of Bounds, see FollowScaled; zero is 1
+	pixelLimit       int         // Largest offscreen canvas in device pixels, see SetPixelLimit
+	deadline         time.Time   // Time painting must be done by, see SetDeadline
 }
 
 func NewCanvas(size types.Size, allowOutOfBounds bool) *Canvas {
@@ -37,9 +47,29 @@ func (c *Canvas) SubCanvas(x, y int, size types.Size, allowOutOfBounds *bool) *C
 		Size:             size,
 		offset:           image.Point{X: c.offset.X + x, Y: c.offset.Y + y},
 		AllowOutOfBounds: *allowOutOfBounds,
+		backend:          c.backend,
+		pixelRatio:       c.pixelRatio,
+		tracer:           c.tracer,
+		origin:           c.origin,
+		traceScale:       c.traceScale,
+		pixelLimit:       c.pixelLimit,
+		deadline:         c.deadline,
 	}
 }
 
+// bounds returns the area of the shared image the canvas draws on
+func (c *Canvas) bounds() image.Rectangle {
+	return image.Rect(c.offset.X, c.offset.Y, c.offset.X+c.Size.Width, c.offset.Y+c.Size.Height)
+}
+
+// Image returns the pixels covered by the canvas. For a sub canvas this is the
+// part of the shared image it draws on, with the bounds of that part.
+// Scaled canvases return their device pixels.
+func (c *Canvas) Image() image.Image {
+	c = c.device()
+	return c.Img.SubImage(c.bounds())
+}
+
 func (c *Canvas) set(x, y int, color color.RGBA) {
 	if !c.AllowOutOfBounds {
 		c.assertPointInBounds(x, y)
@@ -50,6 +80,47 @@ func (c *Canvas) set(x, y int, color color.RGBA) {
 	c.Img.Set(c.offset.X+x, c.offset.Y+y, color)
 }
 
+// blend composites color over the existing pixel using source-over.
+// Unlike set, points outside the canvas are silently clipped.
+func (c *Canvas) blend(x, y int, color color.RGBA) {
+	if !c.isPointInBounds(x, y) || color.A == 0 {
+		return
+	}
+	if color.A == 255 {
+		c.Img.SetRGBA(c.offset.X+x, c.offset.Y+y, color)
+		return
+	}
+
+	dst := c.Img.RGBAAt(c.offset.X+x, c.offset.Y+y)
+	inv := 255 - uint32(color.A)
+	dst.R = uint8(uint32(color.R) + uint32(dst.R)*inv/255)
+	dst.G = uint8(uint32(color.G) + uint32(dst.G)*inv/255)
+	dst.B = uint8(uint32(color.B) + uint32(dst.B)*inv/255)
+	dst.A = uint8(uint32(color.A) + uint32(dst.A)*inv/255)
+	c.Img.SetRGBA(c.offset.X+x, c.offset.Y+y, dst)
+}
+
+// clipRect intersects the rectangle at (x, y) of size (w, h) with the canvas bounds
+func (c *Canvas) clipRect(x, y, w, h int) (x0, y0, x1, y1 int) {
+	return max(x, 0), max(y, 0), min(x+w, c.Size.Width), min(y+h, c.Size.Height)
+}
+
+// scaleAlpha multiplies an alpha-premultiplied color by coverage in [0, 1]
+func scaleAlpha(c color.RGBA, coverage float64) color.RGBA {
+	if coverage >= 1 {
+		return c
+	}
+	if coverage <= 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{
+		R: uint8(float64(c.R)*coverage + 0.5),
+		G: uint8(float64(c.G)*coverage + 0.5),
+		B: uint8(float64(c.B)*coverage + 0.5),
+		A: uint8(float64(c.A)*coverage + 0.5),
+	}
+}
+
 func (c *Canvas) get(x, y int) color.Color {
 	c.assertPointInBounds(x, y)
 	return c.Img.At(c.offset.X+x, c.offset.Y+y)
@@ -75,6 +146,23 @@ func (c *Canvas) DrawCanvas(other *Canvas, x, y int) {
 	c.assertPointInBounds(x, y)
 	c.assertPointInBounds(x+other.Size.Width-1, y+other.Size.Height-1)
 
+	if c.backend != nil {
+		c.clip(x, y, other.Size.Width, other.Size.Height)
+		c.backend.Image(c.offset.X+x, c.offset.Y+y, other.Size, other.Image())
+		return
+	}
+	if c.scaled() || other.scaled() {
+		// Copy device pixels, clipped since rounding can make the sizes differ by one
+		d, o := c.device(), other.device()
+		dx, dy := c.deviceX(x), c.deviceY(y)
+		for i := range min(o.Size.Width, d.Size.Width-dx) {
+			for j := range min(o.Size.Height, d.Size.Height-dy) {
+				d.set(dx+i, dy+j, o.Img.RGBAAt(o.offset.X+i, o.offset.Y+j))
+			}
+		}
+		return
+	}
+
 	// Draw each pixel from the other canvas onto this canvas
 	for i := range other.Size.Width {
 		for j := range other.Size.Height {
@@ -82,3 +170,41 @@ func (c *Canvas) DrawCanvas(other *Canvas, x, y int) {
 		}
 	}
 }
+
+// DrawCanvasScaled draws an already rendered canvas onto this canvas, scaled to size.
+// Unlike DrawCanvas, the other canvas is blended over the existing pixels and
+// anything falling outside this canvas is clipped.
+func (c *Canvas) DrawCanvasScaled(other *Canvas, x, y int, size types.Size) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return
+	}
+	if c.backend != nil {
+		c.clip(x, y, size.Width, size.Height)
+		c.backend.Image(c.offset.X+x, c.offset.Y+y, size, other.Image())
+		return
+	}
+	if c.scaled() || other.scaled() {
+		deviceSize := types.Size{Width: c.deviceX(x+size.Width) - c.deviceX(x), Height: c.deviceY(y+size.Height) - c.deviceY(y)}
+		c.device().DrawCanvasScaled(other.device(), c.deviceX(x), c.deviceY(y), deviceSize)
+		return
+	}
+
+	bounds := c.bounds()
+	dst := image.Rect(c.offset.X+x, c.offset.Y+y, c.offset.X+x+size.Width, c.offset.Y+y+size.Height)
+	src := other.bounds()
+
+	// Scale the visible part into a temporary image so clipping doesn't distort the sampling,
+	// and a child scaled far beyond the canvas doesn't allocate pixels that are never shown
+	visible := dst.Intersect(bounds)
+	if visible.Empty() || src.Empty() {
+		return
+	}
+	sx, sy := float64(dst.Dx())/float64(src.Dx()), float64(dst.Dy())/float64(src.Dy())
+	m := f64.Aff3{
+		sx, 0, float64(dst.Min.X) - float64(src.Min.X)*sx,
+		0, sy, float64(dst.Min.Y) - float64(src.Min.Y)*sy,
+	}
+	scaled := image.NewRGBA(visible)
+	draw.ApproxBiLinear.Transform(scaled, m, other.Img, src, draw.Src, nil)
+	draw.Draw(c.Img, visible, scaled, visible.Min, draw.Over)
+}
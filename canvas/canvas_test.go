@@ -139,3 +139,27 @@ func TestDrawCanvasOutOfBounds(t *testing.T) {
 	}()
 	parent.DrawCanvas(child, 60, 60)
 }
+
+func TestDrawCanvasScaled(t *testing.T) {
+	parent := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	child := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	child.Rectangle(0, 0, 10, 10, red, true)
+
+	// Scale the child up to 40x40 at (10,10)
+	parent.DrawCanvasScaled(child, 10, 10, types.Size{Width: 40, Height: 40})
+
+	if parent.Img.At(10, 10) != red || parent.Img.At(49, 49) != red {
+		t.Error("Expected the scaled child to cover (10,10)-(49,49)")
+	}
+	if parent.Img.At(50, 50) != (color.RGBA{}) {
+		t.Error("Expected pixels outside the scaled child to stay transparent")
+	}
+
+	// Drawing past the edges is clipped rather than panicking
+	sub := parent.SubCanvas(60, 60, types.Size{Width: 20, Height: 20}, nil)
+	sub.DrawCanvasScaled(child, 10, 10, types.Size{Width: 40, Height: 40})
+	if parent.Img.At(79, 79) != red || parent.Img.At(80, 80) != (color.RGBA{}) {
+		t.Error("Expected the scaled child to be clipped to the sub canvas")
+	}
+}
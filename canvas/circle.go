@@ -7,6 +7,25 @@ import (
 
 func (c *Canvas) Circle(x, y, r int, color color.RGBA, fill bool) {
 	// No bounds checking here; set will handle it
+	if c.backend != nil {
+		c.clip(x-r, y-r, 2*r+1, 2*r+1)
+		c.backend.Circle(c.offset.X+x, c.offset.Y+y, r, color, fill)
+		return
+	}
+	if c.scaled() {
+		d := c.device()
+		cx, cy := c.devicePoint(x, y)
+		radius := c.deviceLength(r)
+		if fill {
+			d.Circle(cx, cy, radius, color, true)
+			return
+		}
+		// Outlines are one logical pixel thick
+		for i := range max(c.deviceLength(1), 1) {
+			d.Circle(cx, cy, radius-i, color, false)
+		}
+		return
+	}
 
 	if fill {
 		// Fill the circle by drawing horizontal lines
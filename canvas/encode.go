@@ -0,0 +1,186 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/bmp"
+)
+
+// Format is an image file format the canvas can be encoded to
+type Format int
+
+const (
+	FormatPNG Format = iota
+	FormatJPEG
+	FormatGIF
+	FormatBMP
+)
+
+var ErrUnknownFormat = errors.New("unknown image format")
+
+func (f Format) String() string {
+	switch f {
+	case FormatPNG:
+		return "png"
+	case FormatJPEG:
+		return "jpeg"
+	case FormatGIF:
+		return "gif"
+	case FormatBMP:
+		return "bmp"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the format with the given name or file extension, such as "png" or ".jpg"
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "png":
+		return FormatPNG, nil
+	case "jpg", "jpeg":
+		return FormatJPEG, nil
+	case "gif":
+		return FormatGIF, nil
+	case "bmp":
+		return FormatBMP, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownFormat, name)
+}
+
+// FormatFromPath detects the format of a file from its extension
+func FormatFromPath(path string) (Format, error) {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return 0, fmt.Errorf("%w: %s has no extension", ErrUnknownFormat, path)
+	}
+	return ParseFormat(ext)
+}
+
+// EncodeOptions tunes how a canvas is encoded. The zero value gives sensible defaults for every format.
+type EncodeOptions struct {
+	// PNG compression, png.DefaultCompression by default
+	Compression png.CompressionLevel
+	// Maximum number of palette colors. PNGs are only quantized when this is set;
+	// GIFs always are, to 256 colors by default.
+	Colors int
+	// Dither quantized images with Floyd-Steinberg error diffusion
+	Dither bool
+	// JPEG quality from 1 to 100, 90 by default
+	Quality int
+	// Color translucent pixels are flattened onto for formats without alpha (JPEG, BMP).
+	// Left empty, white is used.
+	Background color.Color
+}
+
+// Encode writes the pixels of the canvas to w in the given format. A nil options uses the defaults.
+func (c *Canvas) Encode(w io.Writer, format Format, options *EncodeOptions) error {
+	if c.backend != nil {
+		return errors.New("vector canvases have no pixels to encode")
+	}
+	if options == nil {
+		options = &EncodeOptions{}
+	}
+	img := c.Image()
+
+	switch format {
+	case FormatPNG:
+		encoder := &png.Encoder{CompressionLevel: options.Compression}
+		if options.Colors > 0 {
+			return encoder.Encode(w, paletted(img, Quantize(img, options.Colors), options.Dither))
+		}
+		return encoder.Encode(w, img)
+
+	case FormatJPEG:
+		quality := options.Quality
+		if quality <= 0 {
+			quality = 90
+		}
+		return jpeg.Encode(w, flatten(img, options.Background), &jpeg.Options{Quality: min(quality, 100)})
+
+	case FormatGIF:
+		colors := options.Colors
+		if colors <= 0 || colors > 256 {
+			colors = 256
+		}
+		// GIF transparency is all or nothing
+		img = thresholdAlpha(img)
+		return gif.Encode(w, paletted(img, Quantize(img, colors), options.Dither), nil)
+
+	case FormatBMP:
+		return bmp.Encode(w, flatten(img, options.Background))
+	}
+	return fmt.Errorf("%w: %v", ErrUnknownFormat, format)
+}
+
+// SaveFile encodes the canvas into a file, picking the format from the file extension
+func (c *Canvas) SaveFile(path string, options *EncodeOptions) (err error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return c.Encode(file, format, options)
+}
+
+// paletted maps img onto palette, diffusing the error when dither is set
+func paletted(img image.Image, palette color.Palette, dither bool) *image.Paletted {
+	bounds := img.Bounds()
+	out := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette)
+	var drawer draw.Drawer = draw.Src
+	if dither {
+		drawer = draw.FloydSteinberg
+	}
+	drawer.Draw(out, out.Bounds(), img, bounds.Min)
+	return out
+}
+
+// flatten composites img over an opaque background
+func flatten(img image.Image, background color.Color) *image.RGBA {
+	if background == nil {
+		background = color.White
+	}
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Over)
+	return out
+}
+
+// thresholdAlpha makes every pixel either fully transparent or fully opaque
+func thresholdAlpha(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				c = color.NRGBA{}
+			} else {
+				c.A = 255
+			}
+			out.SetNRGBA(x-bounds.Min.X, y-bounds.Min.Y, c)
+		}
+	}
+	return out
+}
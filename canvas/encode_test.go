@@ -0,0 +1,122 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+	"golang.org/x/image/bmp"
+)
+
+func testCanvas() *Canvas {
+	canvas := NewCanvas(types.Size{Width: 20, Height: 10}, false)
+	canvas.Rectangle(0, 0, 10, 10, Red, true)
+	return canvas
+}
+
+func TestParseFormat(t *testing.T) {
+	cases := map[string]Format{"png": FormatPNG, ".JPG": FormatJPEG, "jpeg": FormatJPEG, ".gif": FormatGIF, "bmp": FormatBMP}
+	for name, expected := range cases {
+		format, err := ParseFormat(name)
+		if err != nil || format != expected {
+			t.Errorf("Expected %q to be %v, got %v (%v)", name, expected, format, err)
+		}
+	}
+
+	if _, err := FormatFromPath("out.tiff"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat for .tiff, got %v", err)
+	}
+	if _, err := FormatFromPath("out"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat without an extension, got %v", err)
+	}
+}
+
+func TestEncodeFormats(t *testing.T) {
+	canvas := testCanvas()
+	decoders := map[Format]func(*bytes.Buffer) (image.Image, error){
+		FormatPNG:  func(b *bytes.Buffer) (image.Image, error) { return png.Decode(b) },
+		FormatJPEG: func(b *bytes.Buffer) (image.Image, error) { return jpeg.Decode(b) },
+		FormatGIF:  func(b *bytes.Buffer) (image.Image, error) { return gif.Decode(b) },
+		FormatBMP:  func(b *bytes.Buffer) (image.Image, error) { return bmp.Decode(b) },
+	}
+
+	for format, decode := range decoders {
+		var buf bytes.Buffer
+		if err := canvas.Encode(&buf, format, nil); err != nil {
+			t.Fatalf("Expected %v to encode, got %v", format, err)
+		}
+		img, err := decode(&buf)
+		if err != nil {
+			t.Fatalf("Expected %v to decode, got %v", format, err)
+		}
+		if img.Bounds().Dx() != 20 || img.Bounds().Dy() != 10 {
+			t.Errorf("Expected a 20x10 %v image, got %v", format, img.Bounds())
+		}
+
+		r, g, b, _ := img.At(2, 2).RGBA()
+		if r>>8 < 200 || g>>8 > 50 || b>>8 > 50 {
+			t.Errorf("Expected red at (2,2) in %v, got %v", format, img.At(2, 2))
+		}
+	}
+}
+
+func TestEncodeFlattensAlpha(t *testing.T) {
+	canvas := testCanvas()
+
+	var buf bytes.Buffer
+	if err := canvas.Encode(&buf, FormatBMP, &EncodeOptions{Background: Blue}); err != nil {
+		t.Fatal(err)
+	}
+	img, _ := bmp.Decode(&buf)
+	if r, g, b, _ := img.At(15, 5).RGBA(); r != 0 || g != 0 || b>>8 != 255 {
+		t.Errorf("Expected transparent pixels flattened onto blue, got %v", img.At(15, 5))
+	}
+}
+
+func TestEncodePalettedPNG(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 64, Height: 1}, false)
+	for x := range 64 {
+		canvas.Rectangle(x, 0, 1, 1, color.RGBA{uint8(x * 4), 0, 0, 255}, true)
+	}
+
+	var buf bytes.Buffer
+	if err := canvas.Encode(&buf, FormatPNG, &EncodeOptions{Colors: 8, Dither: true}); err != nil {
+		t.Fatal(err)
+	}
+	img, _ := png.Decode(&buf)
+	paletted, ok := img.(*image.Paletted)
+	if !ok {
+		t.Fatalf("Expected a paletted PNG, got %T", img)
+	}
+	if len(paletted.Palette) > 8 {
+		t.Errorf("Expected at most 8 colors, got %d", len(paletted.Palette))
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jpg")
+	if err := testCanvas().SaveFile(path, &EncodeOptions{Quality: 50}); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, format, err := image.Decode(file); err != nil || format != "jpeg" {
+		t.Errorf("Expected a JPEG file, got %q (%v)", format, err)
+	}
+
+	if err := testCanvas().SaveFile(filepath.Join(t.TempDir(), "out.xyz"), nil); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package canvas
+
+import (
+	"image/color"
+	"math"
+)
+
+// Blur applies a Gaussian blur with the given standard deviation to the canvas in place.
+// The blur is approximated by three separable box blurs, so its cost doesn't grow
+// with the radius. Pixels past the canvas edges repeat the edge pixels.
+func (c *Canvas) Blur(sigma float64) {
+	if c.scaled() {
+		c.device().Blur(sigma * c.pixelRatio)
+		return
+	}
+	if sigma <= 0 || c.backend != nil {
+		return
+	}
+	// Only the part of the canvas on the image has pixels to blur
+	bounds := c.bounds().Intersect(c.Img.Bounds())
+	w, h := bounds.Dx(), bounds.Dy()
+	if bounds.Empty() {
+		return
+	}
+
+	// Work on a copy of the canvas region, one int32 per channel
+	buf := make([]int32, w*h*4)
+	for y := range h {
+		row := c.Img.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		for i := range w * 4 {
+			buf[y*w*4+i] = int32(c.Img.Pix[row+i])
+		}
+	}
+	tmp := make([]int32, max(w, h)*4)
+
+	// Past the size of the region every pixel is already close to the average, and the
+	// box sizes of larger deviations would overflow
+	sigma = min(sigma, float64(max(w, h)))
+	for _, size := range boxSizesForGaussian(sigma, 3) {
+		radius := (size - 1) / 2
+		// Horizontal pass over every row, then vertical pass over every column
+		for y := range h {
+			boxBlurLine(buf[y*w*4:], w, 4, radius, tmp)
+		}
+		for x := range w {
+			boxBlurLine(buf[x*4:], h, w*4, radius, tmp)
+		}
+	}
+
+	for y := range h {
+		row := c.Img.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		for i := range w * 4 {
+			c.Img.Pix[row+i] = uint8(buf[y*w*4+i])
+		}
+	}
+}
+
+// boxSizesForGaussian returns the widths of n box blurs that together approximate
+// a Gaussian with the given standard deviation
+func boxSizesForGaussian(sigma float64, n int) []int {
+	ideal := math.Sqrt(12*sigma*sigma/float64(n) + 1)
+	lower := int(math.Floor(ideal))
+	if lower%2 == 0 {
+		lower--
+	}
+	upper := lower + 2
+
+	m := int(math.Round((12*sigma*sigma - float64(n*lower*lower) - float64(4*n*lower) - float64(3*n)) / float64(-4*lower-4)))
+	sizes := make([]int, n)
+	for i := range sizes {
+		if i < m {
+			sizes[i] = lower
+		} else {
+			sizes[i] = upper
+		}
+	}
+	return sizes
+}
+
+// boxBlurLine blurs count RGBA pixels spaced stride values apart using a running sum.
+// Windows wider than the line are narrowed to it, as they only repeat the edge pixels.
+func boxBlurLine(line []int32, count, stride, radius int, tmp []int32) {
+	radius = min(radius, count-1)
+	if radius <= 0 {
+		return
+	}
+
+	at := func(i, channel int) int64 {
+		i = min(max(i, 0), count-1)
+		return int64(line[i*stride+channel])
+	}
+	window := int64(2*radius + 1)
+
+	for channel := range 4 {
+		var sum int64
+		for i := -radius; i <= radius; i++ {
+			sum += at(i, channel)
+		}
+		for i := range count {
+			tmp[i*4+channel] = int32((sum + window/2) / window)
+			sum += at(i+radius+1, channel) - at(i-radius, channel)
+		}
+	}
+
+	for i := range count {
+		copy(line[i*stride:i*stride+4], tmp[i*4:i*4+4])
+	}
+}
+
+// Colorize replaces every pixel with the given color, keeping the pixel's coverage.
+// Painting a subtree and colorizing it gives the silhouette used for drop shadows.
+func (c *Canvas) Colorize(col color.RGBA) {
+	if c.backend != nil {
+		return
+	}
+	if c.scaled() {
+		c.device().Colorize(col)
+		return
+	}
+	for y := range c.Size.Height {
+		for x := range c.Size.Width {
+			px := c.Img.RGBAAt(c.offset.X+x, c.offset.Y+y)
+			c.Img.SetRGBA(c.offset.X+x, c.offset.Y+y, scaleAlpha(col, float64(px.A)/255))
+		}
+	}
+}
+
+// Snapshot copies the pixels of the canvas into a new canvas of the same size.
+// Vector canvases have no pixels to copy, so their snapshot is transparent.
+func (c *Canvas) Snapshot() *Canvas {
+	snapshot := c.NewOffscreen(c.Size)
+	snapshot.AllowOutOfBounds = c.AllowOutOfBounds
+	if c.backend != nil {
+		return snapshot
+	}
+	if c.scaled() {
+		snapshot.DrawCanvas(c, 0, 0)
+		return snapshot
+	}
+	// Parts of the canvas off the image have no pixels and stay transparent
+	bounds := c.bounds().Intersect(c.Img.Bounds())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		src := c.Img.PixOffset(bounds.Min.X, y)
+		dst := snapshot.Img.PixOffset(bounds.Min.X-c.offset.X, y-c.offset.Y)
+		copy(snapshot.Img.Pix[dst:], c.Img.Pix[src:src+bounds.Dx()*4])
+	}
+	return snapshot
+}
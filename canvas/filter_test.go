@@ -0,0 +1,129 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestBlur(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	canvas.Rectangle(40, 40, 20, 20, red, true)
+
+	canvas.Blur(4)
+
+	center := canvas.Img.RGBAAt(50, 50)
+	edge := canvas.Img.RGBAAt(40, 50)
+	outside := canvas.Img.RGBAAt(35, 50)
+	far := canvas.Img.RGBAAt(10, 50)
+
+	if center.A < 200 {
+		t.Errorf("Expected the center to stay mostly opaque, got alpha %d", center.A)
+	}
+	if edge.A >= center.A || outside.A == 0 || outside.A >= edge.A {
+		t.Errorf("Expected alpha to fall off across the edge, got %d, %d, %d", center.A, edge.A, outside.A)
+	}
+	if far.A != 0 {
+		t.Errorf("Expected pixels far from the square to stay transparent, got alpha %d", far.A)
+	}
+}
+
+func TestBlurSubCanvas(t *testing.T) {
+	parent := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	parent.Rectangle(0, 0, 100, 50, red, true)
+
+	// Blurring a sub canvas leaves the rest of the image alone
+	sub := parent.SubCanvas(0, 50, types.Size{Width: 100, Height: 50}, nil)
+	sub.Blur(10)
+
+	if parent.Img.At(50, 49) != red {
+		t.Error("Expected pixels above the sub canvas to be untouched")
+	}
+}
+
+func TestBlurLargeSigma(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 40, Height: 20}, false)
+	canvas.Rectangle(0, 0, 20, 20, color.RGBA{255, 0, 0, 255}, true)
+
+	// Deviations far past the canvas blur it towards its average without hanging or overflowing
+	done := make(chan struct{})
+	go func() {
+		canvas.Blur(1e9)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a huge blur to finish")
+	}
+	left, right := canvas.Img.RGBAAt(0, 10), canvas.Img.RGBAAt(39, 10)
+	if left.A == 0 || right.A == 0 || left.A == 255 || right.A == 255 {
+		t.Errorf("Expected the blur to spread the square over the canvas, got alpha %d and %d", left.A, right.A)
+	}
+}
+
+func TestFiltersOutsideTheImage(t *testing.T) {
+	allow := true
+	parent := NewCanvas(types.Size{Width: 50, Height: 50}, true)
+	red := color.RGBA{255, 0, 0, 255}
+	parent.Rectangle(0, 0, 50, 50, red, true)
+
+	// Canvases hanging past the top left and the bottom right of the image, with a point
+	// of each snapshot that is on the image and one that isn't
+	cases := []struct{ at, on, off int }{{-30, 40, 10}, {30, 10, 40}}
+	for _, c := range cases {
+		sub := parent.SubCanvas(c.at, c.at, types.Size{Width: 60, Height: 60}, &allow)
+		snapshot := sub.Snapshot()
+		if snapshot.Img.At(c.on, c.on) != red || snapshot.Img.RGBAAt(c.off, c.off).A != 0 {
+			t.Errorf("Expected the snapshot at %d to copy only the pixels on the image", c.at)
+		}
+		sub.Blur(3)
+	}
+	if parent.Img.At(25, 25) != red {
+		t.Error("Expected blurring canvases past the edges to keep the image")
+	}
+}
+
+func TestColorize(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	canvas.set(1, 1, color.RGBA{255, 0, 0, 255})
+	canvas.set(2, 2, color.RGBA{0, 128, 0, 128})
+
+	canvas.Colorize(color.RGBA{0, 0, 255, 255})
+
+	if c := canvas.Img.RGBAAt(1, 1); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("Expected opaque blue, got %v", c)
+	}
+	if c := canvas.Img.RGBAAt(2, 2); c != (color.RGBA{0, 0, 128, 128}) {
+		t.Errorf("Expected half transparent blue, got %v", c)
+	}
+	if c := canvas.Img.RGBAAt(5, 5); c.A != 0 {
+		t.Errorf("Expected transparent pixels to stay transparent, got %v", c)
+	}
+}
+
+func TestSnapshotAndDrawCanvasOver(t *testing.T) {
+	parent := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	parent.Rectangle(10, 10, 10, 10, red, true)
+
+	snapshot := parent.SubCanvas(10, 10, types.Size{Width: 20, Height: 20}, nil).Snapshot()
+	if snapshot.Img.At(0, 0) != red || snapshot.Img.RGBAAt(15, 15).A != 0 {
+		t.Error("Expected the snapshot to copy the sub canvas pixels")
+	}
+
+	// Transparent pixels of the drawn canvas keep the background
+	parent.Rectangle(50, 50, 20, 20, blue, true)
+	parent.DrawCanvasOver(snapshot, 50, 50)
+	if parent.Img.At(50, 50) != red || parent.Img.At(65, 65) != blue {
+		t.Error("Expected the snapshot to be blended over the background")
+	}
+
+	// Drawing past the edge is clipped
+	parent.DrawCanvasOver(snapshot, 95, 95)
+}
@@ -0,0 +1,63 @@
+package canvas
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// DefaultFont is the name of the font text uses unless another one is chosen
+const DefaultFont = "default"
+
+type namedFont struct {
+	data []byte
+	font *truetype.Font // Parsed on first use
+}
+
+var (
+	fontsMu sync.Mutex
+	fonts   = map[string]*namedFont{
+		DefaultFont:   {data: goregular.TTF},
+		"bold":        {data: gobold.TTF},
+		"italic":      {data: goitalic.TTF},
+		"bold-italic": {data: gobolditalic.TTF},
+		"mono":        {data: gomono.TTF},
+	}
+)
+
+// RegisterFont makes TrueType data available to text under a name, replacing any font of that name.
+// The Go fonts are registered as default, bold, italic, bold-italic and mono.
+func RegisterFont(name string, data []byte) error {
+	font, err := truetype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("font %q: %w", name, err)
+	}
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	fonts[name] = &namedFont{data: data, font: font}
+	return nil
+}
+
+// LookupFont returns the registered font of a name and the data it was parsed from
+func LookupFont(name string) (*truetype.Font, []byte, bool) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	f, ok := fonts[name]
+	if !ok {
+		return nil, nil, false
+	}
+	if f.font == nil {
+		font, err := truetype.Parse(f.data)
+		if err != nil {
+			return nil, nil, false
+		}
+		f.font = font
+	}
+	return f.font, f.data, true
+}
@@ -0,0 +1,28 @@
+package canvas
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/gomedium"
+)
+
+func TestLookupFont(t *testing.T) {
+	for _, name := range []string{DefaultFont, "bold", "italic", "bold-italic", "mono"} {
+		if font, data, ok := LookupFont(name); !ok || font == nil || len(data) == 0 {
+			t.Errorf("Expected the built in font %q", name)
+		}
+	}
+	if _, _, ok := LookupFont("missing"); ok {
+		t.Error("Expected an unknown font to be missing")
+	}
+
+	if err := RegisterFont("medium", gomedium.TTF); err != nil {
+		t.Fatalf("Expected the font to register, got %v", err)
+	}
+	if _, _, ok := LookupFont("medium"); !ok {
+		t.Error("Expected the registered font")
+	}
+	if err := RegisterFont("broken", []byte("not a font")); err == nil {
+		t.Error("Expected invalid font data to fail")
+	}
+}
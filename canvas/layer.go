@@ -0,0 +1,294 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/types"
+)
+
+// BlendMode selects how a layer's colors combine with the colors underneath it
+type BlendMode int
+
+const (
+	BlendNormal BlendMode = iota
+	BlendMultiply
+	BlendScreen
+	BlendOverlay
+	BlendDarken
+	BlendLighten
+	BlendDifference
+	BlendAdd
+)
+
+// Layer is an offscreen canvas that is composited onto another canvas when drawn,
+// letting a whole subtree be faded, blended or masked as one image
+type Layer struct {
+	*Canvas
+	Opacity   float64
+	BlendMode BlendMode
+	Mask      *Canvas // Optional, its alpha scales the layer pixel for pixel
+
+	// LinearBlending composites in linear light instead of gamma-encoded sRGB,
+	// so half transparent edges and blend modes mix light like a camera would.
+	// Only raster canvases honor it.
+	LinearBlending bool
+}
+
+// NewLayer creates a transparent, fully opaque layer with normal blending
+func NewLayer(size types.Size) *Layer {
+	return &Layer{
+		Canvas:    NewCanvas(size, true),
+		Opacity:   1,
+		BlendMode: BlendNormal,
+	}
+}
+
+// DrawLayer composites the layer onto this canvas at (x, y).
+// Anything falling outside this canvas is clipped.
+func (c *Canvas) DrawLayer(layer *Layer, x, y int) {
+	opacity := min(max(layer.Opacity, 0), 1)
+	if opacity == 0 {
+		return
+	}
+
+	if c.backend != nil {
+		c.clip(x, y, layer.Size.Width, layer.Size.Height)
+		c.backend.BeginGroup(Identity(), opacity, layer.BlendMode)
+		c.backend.Image(c.offset.X+x, c.offset.Y+y, layer.Size, layer.masked())
+		c.backend.EndGroup()
+		return
+	}
+
+	if c.scaled() || layer.scaled() {
+		c.device().DrawLayer(layer.device(), c.deviceX(x), c.deviceY(y))
+		return
+	}
+
+	x0, y0, x1, y1 := c.clipRect(x, y, layer.Size.Width, layer.Size.Height)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			lx, ly := px-x, py-y
+			src := layer.Img.RGBAAt(layer.offset.X+lx, layer.offset.Y+ly)
+
+			coverage := opacity
+			if layer.Mask != nil {
+				if !layer.Mask.isPointInBounds(lx, ly) {
+					continue
+				}
+				coverage *= float64(layer.Mask.Img.RGBAAt(layer.Mask.offset.X+lx, layer.Mask.offset.Y+ly).A) / 255
+			}
+			src = scaleAlpha(src, coverage)
+			if src.A == 0 {
+				continue
+			}
+
+			if layer.LinearBlending {
+				dst := c.Img.RGBAAt(c.offset.X+px, c.offset.Y+py)
+				c.Img.SetRGBA(c.offset.X+px, c.offset.Y+py, blendPixelLinear(src, dst, layer.BlendMode))
+				continue
+			}
+			if layer.BlendMode == BlendNormal {
+				c.blend(px, py, src)
+				continue
+			}
+			dst := c.Img.RGBAAt(c.offset.X+px, c.offset.Y+py)
+			c.Img.SetRGBA(c.offset.X+px, c.offset.Y+py, blendPixel(src, dst, layer.BlendMode))
+		}
+	}
+}
+
+// DrawCanvasOver blends an already rendered canvas over this canvas at (x, y).
+// Unlike DrawCanvas, transparent pixels keep what is underneath and anything
+// falling outside this canvas is clipped.
+func (c *Canvas) DrawCanvasOver(other *Canvas, x, y int) {
+	c.DrawLayer(&Layer{Canvas: other, Opacity: 1}, x, y)
+}
+
+// blendPixel composites alpha-premultiplied src over dst, mixing the overlapping
+// area with the blend mode as described by the W3C compositing specification
+func blendPixel(src, dst color.RGBA, mode BlendMode) color.RGBA {
+	as, ab := float64(src.A)/255, float64(dst.A)/255
+
+	channel := func(s, d uint8) uint8 {
+		cs, cb := float64(s)/255, float64(d)/255
+		// Unpremultiplied colors for the blend function
+		var us, ub float64
+		if as > 0 {
+			us = cs / as
+		}
+		if ab > 0 {
+			ub = cb / ab
+		}
+		mixed := blendChannel(us, ub, mode)
+		out := cs*(1-ab) + cb*(1-as) + as*ab*mixed
+		return uint8(math.Round(min(max(out, 0), 1) * 255))
+	}
+
+	return color.RGBA{
+		R: channel(src.R, dst.R),
+		G: channel(src.G, dst.G),
+		B: channel(src.B, dst.B),
+		A: uint8(math.Round((as + ab*(1-as)) * 255)),
+	}
+}
+
+// blendPixelLinear is blendPixel with the colors converted to linear light first
+// and the result converted back, keeping full precision in between
+func blendPixelLinear(src, dst color.RGBA, mode BlendMode) color.RGBA {
+	as, ab := float64(src.A)/255, float64(dst.A)/255
+	alpha := as + ab*(1-as)
+	if alpha == 0 {
+		return color.RGBA{}
+	}
+
+	channel := func(s, d uint8) uint8 {
+		var us, ub float64
+		if as > 0 {
+			us = colors.Linearize(float64(s) / 255 / as)
+		}
+		if ab > 0 {
+			ub = colors.Linearize(float64(d) / 255 / ab)
+		}
+		mixed := blendChannel(us, ub, mode)
+		out := (us*as*(1-ab) + ub*ab*(1-as) + as*ab*mixed) / alpha
+		return uint8(math.Round(colors.Delinearize(min(max(out, 0), 1)) * alpha * 255))
+	}
+
+	return color.RGBA{
+		R: channel(src.R, dst.R),
+		G: channel(src.G, dst.G),
+		B: channel(src.B, dst.B),
+		A: uint8(math.Round(alpha * 255)),
+	}
+}
+
+// blendChannel applies the blend mode to one unpremultiplied source and backdrop channel
+func blendChannel(s, b float64, mode BlendMode) float64 {
+	switch mode {
+	case BlendMultiply:
+		return s * b
+	case BlendScreen:
+		return s + b - s*b
+	case BlendOverlay:
+		if b <= 0.5 {
+			return 2 * s * b
+		}
+		return 1 - 2*(1-s)*(1-b)
+	case BlendDarken:
+		return min(s, b)
+	case BlendLighten:
+		return max(s, b)
+	case BlendDifference:
+		return math.Abs(s - b)
+	case BlendAdd:
+		return min(s+b, 1)
+	default:
+		return s
+	}
+}
+
+// DrawLayerTransformed composites the layer onto this canvas after mapping its
+// pixels through m, sampling bilinearly. Anything falling outside this canvas is clipped.
+func (c *Canvas) DrawLayerTransformed(layer *Layer, m Matrix) {
+	inverse, ok := m.Invert()
+	if !ok {
+		return
+	}
+
+	if c.backend != nil {
+		c.clipTransformed(m, layer.Size)
+		c.backend.BeginGroup(c.absolute(m), min(max(layer.Opacity, 0), 1), layer.BlendMode)
+		c.backend.Image(c.offset.X, c.offset.Y, layer.Size, layer.masked())
+		c.backend.EndGroup()
+		return
+	}
+	if c.scaled() || layer.scaled() {
+		// The same transform between device pixels: back to logical pixels, transform, and scale up again
+		s, ls := c.PixelRatio(), layer.PixelRatio()
+		device := m.Multiply(Identity().Scale(1/ls, 1/ls)).Scale(s, s)
+		c.device().DrawLayerTransformed(layer.device(), device)
+		return
+	}
+
+	// Bounding box of the transformed layer, clipped to the canvas
+	bx0, by0, bx1, by1 := m.bounds(float64(layer.Size.Width), float64(layer.Size.Height))
+	x0, y0, x1, y1 := c.clipRect(bx0, by0, bx1-bx0, by1-by0)
+	if x1 <= x0 || y1 <= y0 {
+		return
+	}
+
+	// Resample into a layer covering the bounding box, then composite it as usual
+	transformed := &Layer{
+		Canvas:         NewCanvas(types.Size{Width: x1 - x0, Height: y1 - y0}, true),
+		Opacity:        layer.Opacity,
+		BlendMode:      layer.BlendMode,
+		LinearBlending: layer.LinearBlending,
+	}
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			sx, sy := inverse.Apply(float64(px)+0.5, float64(py)+0.5)
+			transformed.Img.SetRGBA(px-x0, py-y0, layer.sample(sx-0.5, sy-0.5))
+		}
+	}
+	c.DrawLayer(transformed, x0, y0)
+}
+
+// sample returns the bilinearly interpolated, masked color of the layer at (x, y),
+// where integer coordinates are pixel centers and everything outside is transparent
+func (l *Layer) sample(x, y float64) color.RGBA {
+	fx, fy := math.Floor(x), math.Floor(y)
+	tx, ty := x-fx, y-fy
+	ix, iy := int(fx), int(fy)
+
+	var r, g, b, a float64
+	for _, p := range [4]struct {
+		dx, dy int
+		weight float64
+	}{
+		{0, 0, (1 - tx) * (1 - ty)},
+		{1, 0, tx * (1 - ty)},
+		{0, 1, (1 - tx) * ty},
+		{1, 1, tx * ty},
+	} {
+		px, py := ix+p.dx, iy+p.dy
+		if p.weight == 0 || !l.isPointInBounds(px, py) {
+			continue
+		}
+		weight := p.weight
+		if l.Mask != nil {
+			if !l.Mask.isPointInBounds(px, py) {
+				continue
+			}
+			weight *= float64(l.Mask.Img.RGBAAt(l.Mask.offset.X+px, l.Mask.offset.Y+py).A) / 255
+		}
+		pixel := l.Img.RGBAAt(l.offset.X+px, l.offset.Y+py)
+		r += float64(pixel.R) * weight
+		g += float64(pixel.G) * weight
+		b += float64(pixel.B) * weight
+		a += float64(pixel.A) * weight
+	}
+
+	return color.RGBA{R: uint8(r + 0.5), G: uint8(g + 0.5), B: uint8(b + 0.5), A: uint8(a + 0.5)}
+}
+
+// masked returns the pixels of the layer with its mask applied
+func (l *Layer) masked() image.Image {
+	if l.Mask == nil {
+		return l.Image()
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, l.Size.Width, l.Size.Height))
+	for y := range l.Size.Height {
+		for x := range l.Size.Width {
+			if !l.Mask.isPointInBounds(x, y) {
+				continue
+			}
+			coverage := float64(l.Mask.Img.RGBAAt(l.Mask.offset.X+x, l.Mask.offset.Y+y).A) / 255
+			img.SetRGBA(x, y, scaleAlpha(l.Img.RGBAAt(l.offset.X+x, l.offset.Y+y), coverage))
+		}
+	}
+	return img
+}
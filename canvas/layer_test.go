@@ -0,0 +1,120 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestDrawLayerOpacity(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	white := color.RGBA{255, 255, 255, 255}
+	canvas.Rectangle(0, 0, 10, 10, white, true)
+
+	layer := NewLayer(types.Size{Width: 10, Height: 10})
+	layer.Rectangle(0, 0, 5, 10, color.RGBA{0, 0, 0, 255}, true)
+	layer.Opacity = 0.5
+	canvas.DrawLayer(layer, 0, 0)
+
+	// Half transparent black over white gives mid gray
+	if c := canvas.Img.RGBAAt(2, 5); c.R < 126 || c.R > 129 || c.A != 255 {
+		t.Errorf("Expected mid gray, got %v", c)
+	}
+	// Transparent parts of the layer leave the background alone
+	if c := canvas.Img.RGBAAt(7, 5); c != white {
+		t.Errorf("Expected white, got %v", c)
+	}
+}
+
+func TestDrawLayerBlendModes(t *testing.T) {
+	backdrop := color.RGBA{200, 100, 50, 255}
+	source := color.RGBA{100, 200, 255, 255}
+
+	tests := []struct {
+		mode     BlendMode
+		expected color.RGBA
+	}{
+		{BlendNormal, source},
+		{BlendMultiply, color.RGBA{78, 78, 50, 255}},
+		{BlendScreen, color.RGBA{222, 222, 255, 255}},
+		{BlendDarken, color.RGBA{100, 100, 50, 255}},
+		{BlendLighten, color.RGBA{200, 200, 255, 255}},
+		{BlendDifference, color.RGBA{100, 100, 205, 255}},
+	}
+
+	for _, test := range tests {
+		canvas := NewCanvas(types.Size{Width: 1, Height: 1}, false)
+		canvas.set(0, 0, backdrop)
+
+		layer := NewLayer(types.Size{Width: 1, Height: 1})
+		layer.set(0, 0, source)
+		layer.BlendMode = test.mode
+		canvas.DrawLayer(layer, 0, 0)
+
+		if c := canvas.Img.RGBAAt(0, 0); c != test.expected {
+			t.Errorf("Blend mode %d: expected %v, got %v", test.mode, test.expected, c)
+		}
+	}
+}
+
+func TestDrawLayerMask(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	layer := NewLayer(types.Size{Width: 10, Height: 10})
+	layer.Rectangle(0, 0, 10, 10, red, true)
+
+	// Only the left half of the mask is opaque
+	layer.Mask = NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	layer.Mask.Rectangle(0, 0, 5, 10, color.RGBA{0, 0, 0, 255}, true)
+
+	canvas.DrawLayer(layer, 0, 0)
+
+	if canvas.Img.At(2, 5) != red {
+		t.Error("Expected the layer to show through the opaque part of the mask")
+	}
+	if canvas.Img.RGBAAt(7, 5).A != 0 {
+		t.Error("Expected the layer to be hidden by the transparent part of the mask")
+	}
+}
+
+func TestDrawLayerTransformed(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 20, Height: 20}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// A 10x2 bar, red on the left and blue on the right
+	layer := NewLayer(types.Size{Width: 10, Height: 2})
+	layer.Rectangle(0, 0, 5, 2, red, true)
+	layer.Rectangle(5, 0, 5, 2, blue, true)
+
+	// Rotating a quarter turn about the origin and moving it back into view stands it upright
+	canvas.DrawLayerTransformed(layer, Identity().Rotate(90).Translate(2, 0))
+
+	if canvas.Img.At(0, 0) != red || canvas.Img.At(1, 4) != red {
+		t.Error("Expected the red half at the top")
+	}
+	if canvas.Img.At(0, 5) != blue || canvas.Img.At(1, 9) != blue {
+		t.Error("Expected the blue half at the bottom")
+	}
+	if canvas.Img.RGBAAt(2, 0).A != 0 || canvas.Img.RGBAAt(0, 10).A != 0 {
+		t.Error("Expected nothing outside the rotated bar")
+	}
+}
+
+func TestDrawLayerLinearBlending(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	canvas.Rectangle(0, 0, 10, 10, color.RGBA{255, 255, 255, 255}, true)
+
+	layer := NewLayer(types.Size{Width: 10, Height: 10})
+	layer.Rectangle(0, 0, 10, 10, color.RGBA{0, 0, 0, 255}, true)
+	layer.Opacity = 0.5
+	layer.LinearBlending = true
+	canvas.DrawLayer(layer, 0, 0)
+
+	// Half the light of white is encoded brighter than mid gray
+	if c := canvas.Img.RGBAAt(5, 5); c.R < 186 || c.R > 189 || c.A != 255 {
+		t.Errorf("Expected (188, 188, 188), got %v", c)
+	}
+}
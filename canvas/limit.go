@@ -0,0 +1,80 @@
+package canvas
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hvuhsg/render/types"
+)
+
+// LimitError is the panic value of an offscreen canvas larger than the pixel limit
+// of the canvas it was created for, see SetPixelLimit
+type LimitError struct {
+	Size       types.Size
+	PixelRatio float64
+	Limit      int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("offscreen canvas of %dx%d at pixel ratio %g is larger than the limit of %d pixels", e.Size.Width, e.Size.Height, e.PixelRatio, e.Limit)
+}
+
+// SetPixelLimit limits every offscreen canvas created for the canvas, its sub canvases
+// and their layers to pixels device pixels, so untrusted trees can't exhaust memory.
+// Creating a larger one panics with a *LimitError. Zero removes the limit.
+func (c *Canvas) SetPixelLimit(pixels int) {
+	c.pixelLimit = pixels
+}
+
+// PixelLimit returns the largest offscreen canvas in device pixels, 0 when unlimited
+func (c *Canvas) PixelLimit() int {
+	return c.pixelLimit
+}
+
+// DeadlineError is the panic value of painting past the deadline of a canvas, see SetDeadline
+type DeadlineError struct {
+	Deadline time.Time
+}
+
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("painting went past its deadline of %s", e.Deadline.Format(time.RFC3339Nano))
+}
+
+// SetDeadline stops painting on the canvas, its sub canvases and their layers once the
+// deadline has passed, so a tree can't keep painting after its result is no longer wanted.
+// Render objects check it through CheckDeadline. The zero time removes the deadline.
+func (c *Canvas) SetDeadline(deadline time.Time) {
+	c.deadline = deadline
+}
+
+// Deadline returns the time painting on the canvas must be done by, zero when there is none
+func (c *Canvas) Deadline() time.Time {
+	return c.deadline
+}
+
+// CheckDeadline panics with a *DeadlineError when the deadline of the canvas has passed
+func (c *Canvas) CheckDeadline() {
+	if !c.deadline.IsZero() && time.Now().After(c.deadline) {
+		panic(&DeadlineError{Deadline: c.deadline})
+	}
+}
+
+// NewOffscreen creates a canvas of size to paint on before drawing the result onto c.
+// It has the pixel ratio, pixel limit and deadline of c and allows drawing out of bounds.
+func (c *Canvas) NewOffscreen(size types.Size) *Canvas {
+	ratio := c.PixelRatio()
+	if c.pixelLimit > 0 {
+		pixels := float64(scaleLength(max(size.Width, 0), ratio)) * float64(scaleLength(max(size.Height, 0), ratio))
+		if pixels > float64(c.pixelLimit) {
+			panic(&LimitError{Size: size, PixelRatio: ratio, Limit: c.pixelLimit})
+		}
+	}
+	offscreen := NewScaledCanvas(size, ratio, true)
+	offscreen.pixelLimit, offscreen.deadline = c.pixelLimit, c.deadline
+	return offscreen
+}
+
+// NewOffscreenLayer creates a layer like NewOffscreen, opaque and blended normally
+func (c *Canvas) NewOffscreenLayer(size types.Size) *Layer {
+	return &Layer{Canvas: c.NewOffscreen(size), Opacity: 1, BlendMode: BlendNormal}
+}
@@ -0,0 +1,52 @@
+package canvas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestPixelLimit(t *testing.T) {
+	canvas := NewScaledCanvas(types.Size{Width: 100, Height: 100}, 2, false)
+	canvas.SetPixelLimit(200 * 200)
+
+	sub := canvas.SubCanvas(10, 10, types.Size{Width: 50, Height: 50}, nil)
+	offscreen := sub.NewOffscreen(types.Size{Width: 100, Height: 100})
+	if offscreen.PixelLimit() != 200*200 || offscreen.PixelRatio() != 2 {
+		t.Errorf("Expected offscreen canvases to keep the limit and pixel ratio, got %d and %g", offscreen.PixelLimit(), offscreen.PixelRatio())
+	}
+	if b := offscreen.Img.Bounds(); b.Dx() != 200 || b.Dy() != 200 {
+		t.Errorf("Expected 200x200 device pixels, got %v", b)
+	}
+
+	// 101x100 logical pixels are 202x200 device pixels, just over the limit
+	defer func() {
+		err, ok := recover().(*LimitError)
+		if !ok || err.Limit != 200*200 || err.Size != (types.Size{Width: 101, Height: 100}) {
+			t.Errorf("Expected a LimitError, got %v", err)
+		}
+	}()
+	sub.NewOffscreenLayer(types.Size{Width: 101, Height: 100})
+	t.Errorf("Expected creating a canvas over the limit to panic")
+}
+
+func TestDeadline(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	canvas.CheckDeadline()
+
+	// Sub canvases and layers keep the deadline, which has passed
+	deadline := time.Now().Add(-time.Second)
+	canvas.SetDeadline(deadline)
+	offscreen := canvas.SubCanvas(2, 2, types.Size{Width: 5, Height: 5}, nil).NewOffscreen(types.Size{Width: 5, Height: 5})
+	if !offscreen.Deadline().Equal(deadline) {
+		t.Errorf("Expected offscreen canvases to keep the deadline, got %v", offscreen.Deadline())
+	}
+	defer func() {
+		if err, ok := recover().(*DeadlineError); !ok || !err.Deadline.Equal(deadline) {
+			t.Errorf("Expected a DeadlineError, got %v", err)
+		}
+	}()
+	offscreen.CheckDeadline()
+	t.Errorf("Expected checking a passed deadline to panic")
+}
@@ -7,6 +7,18 @@ import (
 
 func (c *Canvas) Line(x1, y1, x2, y2 int, color color.RGBA, width int) {
 	// No bounds checking here; set will handle it
+	if c.backend != nil {
+		half := (max(width, 1) + 1) / 2
+		c.clip(min(x1, x2)-half, min(y1, y2)-half, abs(x2-x1)+2*half+1, abs(y2-y1)+2*half+1)
+		c.backend.Line(c.offset.X+x1, c.offset.Y+y1, c.offset.X+x2, c.offset.Y+y2, color, width)
+		return
+	}
+	if c.scaled() {
+		dx1, dy1 := c.devicePoint(x1, y1)
+		dx2, dy2 := c.devicePoint(x2, y2)
+		c.device().Line(dx1, dy1, dx2, dy2, color, max(c.deviceLength(max(width, 1)), 1))
+		return
+	}
 
 	// Handle single point case
 	if x1 == x2 && y1 == y2 {
@@ -0,0 +1,85 @@
+package canvas
+
+import "math"
+
+// Matrix is a 2D affine transformation mapping (x, y) to
+// (A*x + C*y + E, B*x + D*y + F), in the same layout as SVG's matrix()
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity returns the matrix that leaves points unchanged
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Multiply returns the transformation that applies other first and then m
+func (m Matrix) Multiply(other Matrix) Matrix {
+	return Matrix{
+		A: m.A*other.A + m.C*other.B,
+		B: m.B*other.A + m.D*other.B,
+		C: m.A*other.C + m.C*other.D,
+		D: m.B*other.C + m.D*other.D,
+		E: m.A*other.E + m.C*other.F + m.E,
+		F: m.B*other.E + m.D*other.F + m.F,
+	}
+}
+
+// Translate returns m followed by a translation
+func (m Matrix) Translate(dx, dy float64) Matrix {
+	return Matrix{A: 1, D: 1, E: dx, F: dy}.Multiply(m)
+}
+
+// Scale returns m followed by a scale about the origin
+func (m Matrix) Scale(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}.Multiply(m)
+}
+
+// Rotate returns m followed by a clockwise rotation about the origin, in degrees
+func (m Matrix) Rotate(degrees float64) Matrix {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}.Multiply(m)
+}
+
+// Skew returns m followed by a skew along the x and y axes, in degrees
+func (m Matrix) Skew(degreesX, degreesY float64) Matrix {
+	return Matrix{A: 1, B: math.Tan(degreesY * math.Pi / 180), C: math.Tan(degreesX * math.Pi / 180), D: 1}.Multiply(m)
+}
+
+// Apply transforms the point (x, y)
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.E, m.B*x + m.D*y + m.F
+}
+
+// Invert returns the inverse transformation, and false if m isn't invertible
+func (m Matrix) Invert() (Matrix, bool) {
+	det := m.A*m.D - m.B*m.C
+	if det == 0 {
+		return Matrix{}, false
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+// IsIdentity reports whether m leaves points unchanged
+func (m Matrix) IsIdentity() bool {
+	return m == Identity()
+}
+
+// bounds returns the pixel bounding box of the rectangle (0, 0, w, h) transformed by m
+func (m Matrix) bounds(w, h float64) (x0, y0, x1, y1 int) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := m.Apply(corner[0], corner[1])
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+	}
+	return int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))
+}
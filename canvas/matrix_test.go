@@ -0,0 +1,48 @@
+package canvas
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrixTransforms(t *testing.T) {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	tests := []struct {
+		name          string
+		matrix        Matrix
+		x, y          float64
+		expectX, expY float64
+	}{
+		{"identity", Identity(), 3, 4, 3, 4},
+		{"translate", Identity().Translate(10, -5), 3, 4, 13, -1},
+		{"scale", Identity().Scale(2, 3), 3, 4, 6, 12},
+		{"rotate", Identity().Rotate(90), 1, 0, 0, 1},
+		{"skew", Identity().Skew(45, 0), 0, 2, 2, 2},
+		// Scale first, then translate
+		{"chained", Identity().Scale(2, 2).Translate(1, 1), 3, 4, 7, 9},
+	}
+
+	for _, test := range tests {
+		x, y := test.matrix.Apply(test.x, test.y)
+		if !near(x, test.expectX) || !near(y, test.expY) {
+			t.Errorf("%s: expected (%v,%v), got (%v,%v)", test.name, test.expectX, test.expY, x, y)
+		}
+	}
+}
+
+func TestMatrixInvert(t *testing.T) {
+	m := Identity().Rotate(30).Scale(2, 0.5).Translate(7, -3)
+	inverse, ok := m.Invert()
+	if !ok {
+		t.Fatal("Expected the matrix to be invertible")
+	}
+	x, y := inverse.Apply(m.Apply(5, 5))
+	if math.Abs(x-5) > 1e-9 || math.Abs(y-5) > 1e-9 {
+		t.Errorf("Expected the inverse to undo the matrix, got (%v,%v)", x, y)
+	}
+
+	if _, ok := Identity().Scale(0, 1).Invert(); ok {
+		t.Error("Expected a zero scale to be singular")
+	}
+}
@@ -0,0 +1,107 @@
+package canvas
+
+import (
+	"image"
+	"math"
+
+	"github.com/hvuhsg/render/types"
+)
+
+// NewScaledCanvas creates a canvas laid out in logical pixels and rasterized at
+// pixelRatio device pixels per logical pixel, 2 for a retina export.
+// Every drawing method takes logical coordinates; Img holds the device pixels.
+func NewScaledCanvas(size types.Size, pixelRatio float64, allowOutOfBounds bool) *Canvas {
+	if pixelRatio <= 0 {
+		pixelRatio = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, scaleLength(size.Width, pixelRatio), scaleLength(size.Height, pixelRatio)))
+	return &Canvas{
+		Img:              img,
+		Size:             size,
+		offset:           image.Point{X: 0, Y: 0},
+		AllowOutOfBounds: allowOutOfBounds,
+		pixelRatio:       pixelRatio,
+	}
+}
+
+// NewScaledLayer creates a layer like NewLayer, rasterized at pixelRatio
+func NewScaledLayer(size types.Size, pixelRatio float64) *Layer {
+	return &Layer{
+		Canvas:    NewScaledCanvas(size, pixelRatio, true),
+		Opacity:   1,
+		BlendMode: BlendNormal,
+	}
+}
+
+// PixelRatio is the number of device pixels per logical pixel, 1 unless the canvas was scaled
+func (c *Canvas) PixelRatio() float64 {
+	if c.pixelRatio == 0 {
+		return 1
+	}
+	return c.pixelRatio
+}
+
+// scaled reports whether drawing has to be converted from logical to device pixels
+func (c *Canvas) scaled() bool {
+	return c.pixelRatio != 0 && c.pixelRatio != 1 && c.backend == nil
+}
+
+func scaleLength(v int, pixelRatio float64) int {
+	return int(math.Round(float64(v) * pixelRatio))
+}
+
+// device returns a canvas addressing the same pixels as c in device coordinates.
+// Edges are rounded from absolute positions, so neighbouring sub canvases stay seamless.
+func (c *Canvas) device() *Canvas {
+	if !c.scaled() {
+		return c
+	}
+	s := c.pixelRatio
+	x0, y0 := scaleLength(c.offset.X, s), scaleLength(c.offset.Y, s)
+	x1, y1 := scaleLength(c.offset.X+c.Size.Width, s), scaleLength(c.offset.Y+c.Size.Height, s)
+	return &Canvas{
+		Img:              c.Img,
+		Size:             types.Size{Width: x1 - x0, Height: y1 - y0},
+		offset:           image.Point{X: x0, Y: y0},
+		AllowOutOfBounds: c.AllowOutOfBounds,
+	}
+}
+
+// deviceX converts a logical x coordinate of c to a coordinate of its device canvas
+func (c *Canvas) deviceX(x int) int {
+	return scaleLength(c.offset.X+x, c.pixelRatio) - scaleLength(c.offset.X, c.pixelRatio)
+}
+
+// deviceY converts a logical y coordinate of c to a coordinate of its device canvas
+func (c *Canvas) deviceY(y int) int {
+	return scaleLength(c.offset.Y+y, c.pixelRatio) - scaleLength(c.offset.Y, c.pixelRatio)
+}
+
+// deviceLength converts a logical distance, such as a radius or line width, to device pixels
+func (c *Canvas) deviceLength(v int) int {
+	return scaleLength(v, c.pixelRatio)
+}
+
+// devicePoint converts a logical pixel to the device pixel at its center
+func (c *Canvas) devicePoint(x, y int) (int, int) {
+	return (c.deviceX(x) + c.deviceX(x+1) - 1) / 2, (c.deviceY(y) + c.deviceY(y+1) - 1) / 2
+}
+
+// scaled returns the radii in device pixels
+func (r Radii) scaled(pixelRatio float64) Radii {
+	return Radii{
+		TopLeft:     scaleLength(r.TopLeft, pixelRatio),
+		TopRight:    scaleLength(r.TopRight, pixelRatio),
+		BottomRight: scaleLength(r.BottomRight, pixelRatio),
+		BottomLeft:  scaleLength(r.BottomLeft, pixelRatio),
+	}
+}
+
+// device returns the layer addressed in device pixels
+func (l *Layer) device() *Layer {
+	device := &Layer{Canvas: l.Canvas.device(), Opacity: l.Opacity, BlendMode: l.BlendMode, LinearBlending: l.LinearBlending}
+	if l.Mask != nil {
+		device.Mask = l.Mask.device()
+	}
+	return device
+}
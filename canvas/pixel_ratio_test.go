@@ -0,0 +1,123 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestScaledCanvasSize(t *testing.T) {
+	canvas := NewScaledCanvas(types.Size{Width: 100, Height: 50}, 2, false)
+
+	if canvas.Size != (types.Size{Width: 100, Height: 50}) {
+		t.Errorf("Expected logical size 100x50, got %v", canvas.Size)
+	}
+	if canvas.Img.Bounds() != image.Rect(0, 0, 200, 100) {
+		t.Errorf("Expected 200x100 device pixels, got %v", canvas.Img.Bounds())
+	}
+	if canvas.PixelRatio() != 2 {
+		t.Errorf("Expected pixel ratio 2, got %v", canvas.PixelRatio())
+	}
+	if NewCanvas(canvas.Size, false).PixelRatio() != 1 {
+		t.Error("Expected unscaled canvases to have pixel ratio 1")
+	}
+}
+
+func TestScaledRectangle(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	canvas := NewScaledCanvas(types.Size{Width: 20, Height: 20}, 2, false)
+	sub := canvas.SubCanvas(5, 5, types.Size{Width: 10, Height: 10}, nil)
+	sub.Rectangle(1, 1, 3, 2, red, true)
+
+	// Logical (6, 6) to (9, 8) covers device pixels (12, 12) to (18, 16)
+	for _, p := range []image.Point{{12, 12}, {17, 15}} {
+		if canvas.Img.RGBAAt(p.X, p.Y) != red {
+			t.Errorf("Expected device pixel %v to be red", p)
+		}
+	}
+	for _, p := range []image.Point{{11, 12}, {18, 12}, {12, 16}} {
+		if canvas.Img.RGBAAt(p.X, p.Y) == red {
+			t.Errorf("Expected device pixel %v to be empty", p)
+		}
+	}
+}
+
+func TestFractionalPixelRatio(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	canvas := NewScaledCanvas(types.Size{Width: 10, Height: 10}, 1.5, false)
+	if canvas.Img.Bounds() != image.Rect(0, 0, 15, 15) {
+		t.Fatalf("Expected 15x15 device pixels, got %v", canvas.Img.Bounds())
+	}
+
+	// Neighbouring sub canvases share edges, leaving no gaps between them
+	for x := range 10 {
+		canvas.SubCanvas(x, 0, types.Size{Width: 1, Height: 10}, nil).Rectangle(0, 0, 1, 10, red, true)
+	}
+	for x := range 15 {
+		if canvas.Img.RGBAAt(x, 7) != red {
+			t.Errorf("Expected device pixel (%d, 7) to be red", x)
+		}
+	}
+}
+
+func TestScaledText(t *testing.T) {
+	painter := NewTextPainter()
+	painter.FontSize = 20
+
+	ink := func(c *Canvas) image.Rectangle {
+		bounds := image.Rectangle{}
+		img := c.Img
+		for y := range img.Bounds().Dy() {
+			for x := range img.Bounds().Dx() {
+				if img.RGBAAt(x, y).A > 0 {
+					bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		return bounds
+	}
+
+	normal := NewCanvas(types.Size{Width: 100, Height: 40}, false)
+	normal.DrawText("Hi", 0, 0, painter)
+	scaled := NewScaledCanvas(types.Size{Width: 100, Height: 40}, 2, false)
+	scaled.DrawText("Hi", 0, 0, painter)
+
+	want, got := ink(normal), ink(scaled)
+	if diff := got.Dx() - 2*want.Dx(); diff < -2 || diff > 2 {
+		t.Errorf("Expected text twice as wide at 2x, got %d and %d pixels", want.Dx(), got.Dx())
+	}
+	if diff := got.Dy() - 2*want.Dy(); diff < -2 || diff > 2 {
+		t.Errorf("Expected text twice as tall at 2x, got %d and %d pixels", want.Dy(), got.Dy())
+	}
+
+	// Measurements stay in logical pixels
+	if scaled.MeasureText("Hi", painter) != normal.MeasureText("Hi", painter) {
+		t.Error("Expected text measurements to ignore the pixel ratio")
+	}
+}
+
+func TestScaledCanvasImage(t *testing.T) {
+	canvas := NewScaledCanvas(types.Size{Width: 20, Height: 10}, 3, false)
+	sub := canvas.SubCanvas(10, 0, types.Size{Width: 10, Height: 10}, nil)
+
+	if sub.Image().Bounds() != image.Rect(30, 0, 60, 30) {
+		t.Errorf("Expected sub canvas image to cover device pixels, got %v", sub.Image().Bounds())
+	}
+}
+
+func TestScaledLayer(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	canvas := NewScaledCanvas(types.Size{Width: 10, Height: 10}, 2, false)
+	layer := NewScaledLayer(types.Size{Width: 4, Height: 4}, 2)
+	layer.Rectangle(0, 0, 4, 4, red, true)
+	canvas.DrawLayer(layer, 3, 3)
+
+	if canvas.Img.RGBAAt(6, 6) != red || canvas.Img.RGBAAt(13, 13) != red {
+		t.Error("Expected the layer to cover device pixels (6, 6) to (14, 14)")
+	}
+	if canvas.Img.RGBAAt(14, 14).A != 0 {
+		t.Error("Expected device pixel (14, 14) to be empty")
+	}
+}
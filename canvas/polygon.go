@@ -11,6 +11,26 @@ func (c *Canvas) Polygon(points [][2]int, color color.RGBA, filled bool) {
 		return
 	}
 
+	if c.backend != nil {
+		absolute := make([][2]int, len(points))
+		x0, y0, x1, y1 := points[0][0], points[0][1], points[0][0], points[0][1]
+		for i, p := range points {
+			absolute[i] = [2]int{c.offset.X + p[0], c.offset.Y + p[1]}
+			x0, y0, x1, y1 = min(x0, p[0]), min(y0, p[1]), max(x1, p[0]), max(y1, p[1])
+		}
+		c.clip(x0, y0, x1-x0+1, y1-y0+1)
+		c.backend.Polygon(absolute, color, filled)
+		return
+	}
+	if c.scaled() {
+		device := make([][2]int, len(points))
+		for i, p := range points {
+			device[i][0], device[i][1] = c.devicePoint(p[0], p[1])
+		}
+		c.device().Polygon(device, color, filled)
+		return
+	}
+
 	if !filled {
 		// Draw the outline by connecting points with lines
 		for i := 0; i < len(points); i++ {
@@ -0,0 +1,139 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Quantize builds a palette of at most n colors for img using median cut.
+// Colors are split in non-premultiplied RGBA space, so translucent pixels get
+// palette entries of their own.
+func Quantize(img image.Image, n int) color.Palette {
+	if n <= 0 {
+		n = 256
+	}
+
+	// Count each distinct color once so large flat areas stay cheap
+	counts := map[color.NRGBA]int{}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				c = color.NRGBA{}
+			}
+			counts[c]++
+		}
+	}
+	if len(counts) == 0 {
+		return color.Palette{color.NRGBA{}}
+	}
+
+	entries := make([]paletteEntry, 0, len(counts))
+	for c, count := range counts {
+		entries = append(entries, paletteEntry{channels: [4]uint8{c.R, c.G, c.B, c.A}, count: count})
+	}
+	// Map iteration order is random; sort so the palette is deterministic
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].channels, entries[j].channels
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+
+	boxes := []colorBox{newColorBox(entries)}
+	for len(boxes) < n {
+		// Split the box whose widest channel spans the most pixels
+		best, bestScore := -1, 0
+		for i, box := range boxes {
+			if len(box.entries) < 2 {
+				continue
+			}
+			_, spread := box.widestChannel()
+			if score := spread * box.count; spread > 0 && score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		a, b := boxes[best].split()
+		boxes[best] = a
+		boxes = append(boxes, b)
+	}
+
+	palette := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		palette[i] = box.average()
+	}
+	return palette
+}
+
+type paletteEntry struct {
+	channels [4]uint8
+	count    int
+}
+
+// colorBox is a group of colors that median cut either splits further or turns into one palette entry
+type colorBox struct {
+	entries []paletteEntry
+	count   int
+}
+
+func newColorBox(entries []paletteEntry) colorBox {
+	count := 0
+	for _, e := range entries {
+		count += e.count
+	}
+	return colorBox{entries: entries, count: count}
+}
+
+func (b colorBox) widestChannel() (channel, spread int) {
+	for ch := range 4 {
+		lo, hi := 255, 0
+		for _, e := range b.entries {
+			lo = min(lo, int(e.channels[ch]))
+			hi = max(hi, int(e.channels[ch]))
+		}
+		if hi-lo > spread {
+			channel, spread = ch, hi-lo
+		}
+	}
+	return channel, spread
+}
+
+// split divides the box at the pixel weighted median of its widest channel
+func (b colorBox) split() (colorBox, colorBox) {
+	channel, _ := b.widestChannel()
+	sort.SliceStable(b.entries, func(i, j int) bool {
+		return b.entries[i].channels[channel] < b.entries[j].channels[channel]
+	})
+
+	half, seen := b.count/2, 0
+	cut := 1
+	for i, e := range b.entries[:len(b.entries)-1] {
+		seen += e.count
+		cut = i + 1
+		if seen >= half {
+			break
+		}
+	}
+	return newColorBox(b.entries[:cut]), newColorBox(b.entries[cut:])
+}
+
+func (b colorBox) average() color.NRGBA {
+	var sum [4]int
+	for _, e := range b.entries {
+		for ch := range 4 {
+			sum[ch] += int(e.channels[ch]) * e.count
+		}
+	}
+	avg := func(ch int) uint8 {
+		return uint8((sum[ch] + b.count/2) / b.count)
+	}
+	return color.NRGBA{R: avg(0), G: avg(1), B: avg(2), A: avg(3)}
+}
@@ -0,0 +1,38 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestQuantizeKeepsFewColors(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	canvas.Rectangle(0, 0, 5, 10, Red, true)
+	canvas.Rectangle(5, 0, 5, 5, Blue, true)
+
+	palette := Quantize(canvas.Img, 16)
+	if len(palette) != 3 {
+		t.Fatalf("Expected red, blue and transparent, got %d colors", len(palette))
+	}
+	for _, expected := range []color.Color{Red, Blue, color.RGBA{}} {
+		if palette[palette.Index(expected)] != color.NRGBAModel.Convert(expected) {
+			t.Errorf("Expected %v in the palette %v", expected, palette)
+		}
+	}
+}
+
+func TestQuantizeLimitsColors(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 16, Height: 16}, false)
+	for y := range 16 {
+		for x := range 16 {
+			canvas.Rectangle(x, y, 1, 1, color.RGBA{uint8(x * 16), uint8(y * 16), 128, 255}, true)
+		}
+	}
+
+	palette := Quantize(canvas.Img, 10)
+	if len(palette) != 10 {
+		t.Errorf("Expected 10 colors, got %d", len(palette))
+	}
+}
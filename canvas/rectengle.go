@@ -4,6 +4,26 @@ import "image/color"
 
 func (c *Canvas) Rectangle(x, y, w, h int, color color.RGBA, fill bool) {
 	// No bounds checking here; set will handle it
+	if c.backend != nil {
+		c.clip(x, y, w, h)
+		c.backend.Rectangle(c.offset.X+x, c.offset.Y+y, w, h, color, fill)
+		return
+	}
+	if c.scaled() {
+		d := c.device()
+		x0, y0, x1, y1 := c.deviceX(x), c.deviceY(y), c.deviceX(x+w), c.deviceY(y+h)
+		if fill {
+			d.Rectangle(x0, y0, x1-x0, y1-y0, color, true)
+			return
+		}
+		// Outlines are one logical pixel thick
+		t := max(c.deviceLength(1), 1)
+		d.Rectangle(x0, y0, x1-x0, t, color, true)
+		d.Rectangle(x0, y1-t, x1-x0, t, color, true)
+		d.Rectangle(x0, y0+t, t, y1-y0-2*t, color, true)
+		d.Rectangle(x1-t, y0+t, t, y1-y0-2*t, color, true)
+		return
+	}
 
 	if fill {
 		// For filled rectangles, we can use a simpler approach
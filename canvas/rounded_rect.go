@@ -0,0 +1,140 @@
+package canvas
+
+import "math"
+
+// Radii holds the corner radii of a rounded rectangle
+type Radii struct {
+	TopLeft     int
+	TopRight    int
+	BottomRight int
+	BottomLeft  int
+}
+
+// RadiiAll returns radii with the same value for every corner
+func RadiiAll(radius int) Radii {
+	return Radii{TopLeft: radius, TopRight: radius, BottomRight: radius, BottomLeft: radius}
+}
+
+// IsZero reports whether every corner is square
+func (r Radii) IsZero() bool {
+	return r == Radii{}
+}
+
+// clamp limits every radius to half of the shorter side of a w by h rectangle
+func (r Radii) clamp(w, h float64) roundedRect {
+	limit := max(min(w, h)/2, 0)
+	clampOne := func(radius int) float64 {
+		return min(max(float64(radius), 0), limit)
+	}
+	return roundedRect{
+		w: w, h: h,
+		topLeft:     clampOne(r.TopLeft),
+		topRight:    clampOne(r.TopRight),
+		bottomRight: clampOne(r.BottomRight),
+		bottomLeft:  clampOne(r.BottomLeft),
+	}
+}
+
+// roundedRect is a rounded rectangle in floating point canvas coordinates
+type roundedRect struct {
+	x, y, w, h                                 float64
+	topLeft, topRight, bottomRight, bottomLeft float64
+}
+
+func newRoundedRect(x, y, w, h float64, radii Radii) roundedRect {
+	r := radii.clamp(w, h)
+	r.x, r.y = x, y
+	return r
+}
+
+// inset shrinks the rectangle by the given amount on each side, reducing the radii to match
+func (r roundedRect) inset(top, right, bottom, left float64) roundedRect {
+	return roundedRect{
+		x: r.x + left, y: r.y + top,
+		w: max(r.w-left-right, 0), h: max(r.h-top-bottom, 0),
+		topLeft:     max(r.topLeft-max(top, left), 0),
+		topRight:    max(r.topRight-max(top, right), 0),
+		bottomRight: max(r.bottomRight-max(bottom, right), 0),
+		bottomLeft:  max(r.bottomLeft-max(bottom, left), 0),
+	}
+}
+
+// outset grows the rectangle by spread on every side, growing the radii to match
+func (r roundedRect) outset(spread float64) roundedRect {
+	grow := func(radius float64) float64 {
+		if radius == 0 {
+			return 0
+		}
+		return max(radius+spread, 0)
+	}
+	return roundedRect{
+		x: r.x - spread, y: r.y - spread,
+		w: max(r.w+2*spread, 0), h: max(r.h+2*spread, 0),
+		topLeft:     grow(r.topLeft),
+		topRight:    grow(r.topRight),
+		bottomRight: grow(r.bottomRight),
+		bottomLeft:  grow(r.bottomLeft),
+	}
+}
+
+// distance returns the signed distance from (px, py) to the outline, negative inside
+func (r roundedRect) distance(px, py float64) float64 {
+	cx, cy := r.x+r.w/2, r.y+r.h/2
+
+	// Each quadrant is shaped by the radius of its corner
+	var radius float64
+	switch {
+	case px < cx && py < cy:
+		radius = r.topLeft
+	case px >= cx && py < cy:
+		radius = r.topRight
+	case px >= cx:
+		radius = r.bottomRight
+	default:
+		radius = r.bottomLeft
+	}
+
+	qx := math.Abs(px-cx) - (r.w/2 - radius)
+	qy := math.Abs(py-cy) - (r.h/2 - radius)
+	return math.Hypot(max(qx, 0), max(qy, 0)) + min(max(qx, qy), 0) - radius
+}
+
+// coverage returns how much of the pixel at (x, y) lies inside the rectangle, from 0 to 1
+func (r roundedRect) coverage(x, y int) float64 {
+	if r.w <= 0 || r.h <= 0 {
+		return 0
+	}
+	return min(max(0.5-r.distance(float64(x)+0.5, float64(y)+0.5), 0), 1)
+}
+
+// FillRoundedRect fills a rectangle with rounded corners using the shader, blending
+// anti-aliased edges over the existing pixels. Drawing is clipped to the canvas.
+func (c *Canvas) FillRoundedRect(x, y, w, h int, radii Radii, shader Shader) {
+	if w <= 0 || h <= 0 || shader == nil {
+		return
+	}
+	if c.backend != nil {
+		c.clip(x, y, w, h)
+		c.backend.RoundedRect(c.offset.X+x, c.offset.Y+y, w, h, radii, shader)
+		return
+	}
+	if c.scaled() {
+		x0, y0, x1, y1 := c.deviceX(x), c.deviceY(y), c.deviceX(x+w), c.deviceY(y+h)
+		c.device().FillRoundedRect(x0, y0, x1-x0, y1-y0, radii.scaled(c.pixelRatio), shader)
+		return
+	}
+
+	shape := newRoundedRect(float64(x), float64(y), float64(w), float64(h), radii)
+	x0, y0, x1, y1 := c.clipRect(x, y, w, h)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			coverage := shape.coverage(px, py)
+			if coverage <= 0 {
+				continue
+			}
+			u := (float64(px-x) + 0.5) / float64(w)
+			v := (float64(py-y) + 0.5) / float64(h)
+			c.blend(px, py, scaleAlpha(shader.At(u, v), coverage))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestFillRoundedRect(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	transparent := color.RGBA{0, 0, 0, 0}
+
+	canvas.FillRoundedRect(10, 10, 80, 60, RadiiAll(20), SolidColor{Color: red})
+
+	// Interior and straight edges are fully covered
+	for _, p := range [][2]int{{50, 40}, {10, 40}, {89, 40}, {50, 10}, {50, 69}} {
+		if canvas.Img.At(p[0], p[1]) != red {
+			t.Errorf("Expected red pixel at (%d,%d), got %v", p[0], p[1], canvas.Img.At(p[0], p[1]))
+		}
+	}
+
+	// The corners are cut off
+	for _, p := range [][2]int{{10, 10}, {89, 10}, {10, 69}, {89, 69}} {
+		if canvas.Img.At(p[0], p[1]) != transparent {
+			t.Errorf("Expected transparent corner at (%d,%d), got %v", p[0], p[1], canvas.Img.At(p[0], p[1]))
+		}
+	}
+
+	// Nothing is drawn outside the rectangle
+	if canvas.Img.At(9, 40) != transparent || canvas.Img.At(90, 40) != transparent {
+		t.Error("Expected no pixels outside the rectangle")
+	}
+}
+
+func TestFillRoundedRectPerCorner(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	canvas.FillRoundedRect(0, 0, 100, 100, Radii{TopLeft: 30}, SolidColor{Color: red})
+
+	if canvas.Img.At(0, 0) == red {
+		t.Error("Expected the top left corner to be rounded")
+	}
+	for _, p := range [][2]int{{99, 0}, {99, 99}, {0, 99}} {
+		if canvas.Img.At(p[0], p[1]) != red {
+			t.Errorf("Expected square corner at (%d,%d)", p[0], p[1])
+		}
+	}
+}
+
+func TestFillRoundedRectClipped(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 50, Height: 50}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	// Filling past the edges must not panic
+	canvas.FillRoundedRect(-20, -20, 100, 100, RadiiAll(10), SolidColor{Color: red})
+	if canvas.Img.At(0, 0) != red || canvas.Img.At(49, 49) != red {
+		t.Error("Expected the visible part of the rectangle to be filled")
+	}
+}
@@ -0,0 +1,121 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hvuhsg/render/colors"
+)
+
+// Shader provides the color of a filled area.
+// Coordinates are normalized, (0, 0) is the top left and (1, 1) the bottom right of the area.
+type Shader interface {
+	At(u, v float64) color.RGBA
+}
+
+// SolidColor fills with a single color
+type SolidColor struct {
+	Color color.RGBA
+}
+
+func (s SolidColor) At(u, v float64) color.RGBA {
+	return s.Color
+}
+
+// GradientStop is a color at an offset between 0 and 1 along a gradient
+type GradientStop struct {
+	Offset float64
+	Color  color.RGBA
+}
+
+// LinearGradient blends its stops along the line from Start to End
+type LinearGradient struct {
+	Start         [2]float64
+	End           [2]float64
+	Stops         []GradientStop
+	Interpolation colors.Space // Color space the stops are blended in, sRGB by default
+}
+
+func (g LinearGradient) At(u, v float64) color.RGBA {
+	dx, dy := g.End[0]-g.Start[0], g.End[1]-g.Start[1]
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return gradientColor(g.Stops, 0, g.Interpolation)
+	}
+	// Project the point onto the gradient line
+	t := ((u-g.Start[0])*dx + (v-g.Start[1])*dy) / length
+	return gradientColor(g.Stops, t, g.Interpolation)
+}
+
+// RadialGradient blends its stops outwards from Center up to Radius
+type RadialGradient struct {
+	Center        [2]float64
+	Radius        float64
+	Stops         []GradientStop
+	Interpolation colors.Space // Color space the stops are blended in, sRGB by default
+}
+
+func (g RadialGradient) At(u, v float64) color.RGBA {
+	if g.Radius <= 0 {
+		return gradientColor(g.Stops, 1, g.Interpolation)
+	}
+	t := math.Hypot(u-g.Center[0], v-g.Center[1]) / g.Radius
+	return gradientColor(g.Stops, t, g.Interpolation)
+}
+
+// gradientColor interpolates the stops at t, clamping outside the first and last stop
+func gradientColor(stops []GradientStop, t float64, space colors.Space) color.RGBA {
+	if len(stops) == 0 {
+		return color.RGBA{}
+	}
+	if t <= stops[0].Offset {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		if t <= stops[i].Offset {
+			from, to := stops[i-1], stops[i]
+			span := to.Offset - from.Offset
+			if span <= 0 {
+				return to.Color
+			}
+			return colors.Mix(from.Color, to.Color, (t-from.Offset)/span, space)
+		}
+	}
+	return stops[len(stops)-1].Color
+}
+
+// SRGBStops approximates stops interpolated in space with extra stops that give
+// nearly the same gradient when blended in sRGB, for outputs that only blend in sRGB
+func SRGBStops(stops []GradientStop, space colors.Space) []GradientStop {
+	if space == colors.SpaceSRGB || len(stops) < 2 {
+		return stops
+	}
+
+	const steps = 8
+	result := []GradientStop{stops[0]}
+	for i := 1; i < len(stops); i++ {
+		from, to := stops[i-1], stops[i]
+		for step := 1; step < steps && to.Offset > from.Offset; step++ {
+			t := float64(step) / steps
+			result = append(result, GradientStop{
+				Offset: from.Offset + (to.Offset-from.Offset)*t,
+				Color:  colors.Mix(from.Color, to.Color, t, space),
+			})
+		}
+		result = append(result, to)
+	}
+	return result
+}
+
+// ImageShader stretches an image over the filled area
+type ImageShader struct {
+	Image image.Image
+}
+
+func (s ImageShader) At(u, v float64) color.RGBA {
+	bounds := s.Image.Bounds()
+	x := bounds.Min.X + min(int(u*float64(bounds.Dx())), bounds.Dx()-1)
+	y := bounds.Min.Y + min(int(v*float64(bounds.Dy())), bounds.Dy()-1)
+	return color.RGBAModel.Convert(s.Image.At(max(x, bounds.Min.X), max(y, bounds.Min.Y))).(color.RGBA)
+}
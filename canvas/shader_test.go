@@ -0,0 +1,94 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/colors"
+)
+
+func TestLinearGradient(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	gradient := LinearGradient{
+		Start: [2]float64{0, 0},
+		End:   [2]float64{1, 0},
+		Stops: []GradientStop{{Offset: 0, Color: black}, {Offset: 1, Color: white}},
+	}
+
+	if c := gradient.At(0, 0.5); c != black {
+		t.Errorf("Expected black at the start, got %v", c)
+	}
+	if c := gradient.At(1, 0.5); c != white {
+		t.Errorf("Expected white at the end, got %v", c)
+	}
+	if c := gradient.At(0.5, 0.9); c.R < 126 || c.R > 129 {
+		t.Errorf("Expected mid gray halfway along the gradient, got %v", c)
+	}
+
+	// Outside the stops the nearest stop color is used
+	if c := gradient.At(-1, 0); c != black {
+		t.Errorf("Expected black before the start, got %v", c)
+	}
+}
+
+func TestRadialGradient(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	gradient := RadialGradient{
+		Center: [2]float64{0.5, 0.5},
+		Radius: 0.5,
+		Stops:  []GradientStop{{Offset: 0, Color: red}, {Offset: 1, Color: blue}},
+	}
+
+	if c := gradient.At(0.5, 0.5); c != red {
+		t.Errorf("Expected red at the center, got %v", c)
+	}
+	if c := gradient.At(1, 0.5); c != blue {
+		t.Errorf("Expected blue at the radius, got %v", c)
+	}
+}
+
+func TestImageShader(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, red)
+	img.SetRGBA(1, 0, blue)
+
+	shader := ImageShader{Image: img}
+	if c := shader.At(0.25, 0.5); c != red {
+		t.Errorf("Expected red on the left half, got %v", c)
+	}
+	if c := shader.At(1, 1); c != blue {
+		t.Errorf("Expected blue on the right edge, got %v", c)
+	}
+}
+
+func TestGradientInterpolation(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	stops := []GradientStop{{Offset: 0, Color: red}, {Offset: 1, Color: blue}}
+
+	srgb := LinearGradient{End: [2]float64{1, 0}, Stops: stops}
+	linear := LinearGradient{End: [2]float64{1, 0}, Stops: stops, Interpolation: colors.SpaceLinear}
+	if c := srgb.At(0.5, 0); c.R != 128 {
+		t.Errorf("Expected sRGB interpolation to average the channels, got %v", c)
+	}
+	if c := linear.At(0.5, 0); c.R != 188 || c.B != 188 {
+		t.Errorf("Expected linear interpolation to keep the middle bright, got %v", c)
+	}
+
+	// Approximating stops add samples and keep the ends
+	approx := SRGBStops(stops, colors.SpaceLinear)
+	if len(approx) != 9 || approx[0] != stops[0] || approx[8] != stops[1] {
+		t.Fatalf("Expected 9 stops between red and blue, got %v", approx)
+	}
+	if approx[4].Offset != 0.5 || approx[4].Color != linear.At(0.5, 0) {
+		t.Errorf("Expected the middle stop to match the linear gradient, got %v", approx[4])
+	}
+	if got := SRGBStops(stops, colors.SpaceSRGB); len(got) != 2 {
+		t.Errorf("Expected sRGB stops to stay as they are, got %v", got)
+	}
+}
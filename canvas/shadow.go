@@ -0,0 +1,107 @@
+package canvas
+
+import (
+	"image/color"
+	"math"
+)
+
+// BoxShadow is a shadow cast by a rounded rectangle
+type BoxShadow struct {
+	Color   color.RGBA
+	OffsetX int
+	OffsetY int
+	Blur    int  // Blur radius, the shadow fades out over roughly this distance
+	Spread  int  // Grows (or shrinks, when negative) the shadow before blurring
+	Inset   bool // Cast inside the rectangle instead of behind it
+}
+
+// Extent returns how far the shadow reaches past the top, right, bottom and left sides
+// of the box casting it. Inset shadows stay inside the box.
+func (s BoxShadow) Extent() (top, right, bottom, left int) {
+	if s.Inset {
+		return 0, 0, 0, 0
+	}
+	reach := shadowReach(s.Blur) + s.Spread
+	return max(reach-s.OffsetY, 0), max(reach+s.OffsetX, 0), max(reach+s.OffsetY, 0), max(reach-s.OffsetX, 0)
+}
+
+// shadowReach returns how far a blurred shadow reaches past its caster,
+// about three standard deviations
+func shadowReach(blur int) int {
+	return int(math.Ceil(1.5*float64(max(blur, 0)))) + 1
+}
+
+// shadowCoverage returns the intensity of a blurred shape's shadow at a signed distance from its edge
+func shadowCoverage(distance, blur float64) float64 {
+	if blur <= 0 {
+		return min(max(0.5-distance, 0), 1)
+	}
+	// A Gaussian blur of a straight edge, with a standard deviation of half the blur radius
+	sigma := blur / 2
+	return 0.5 * math.Erfc(distance/(sigma*math.Sqrt2))
+}
+
+// DrawBoxShadow draws the shadow of the rounded rectangle at (x, y) of size (w, h).
+// Outer shadows are only visible outside the rectangle and inset shadows only inside it.
+// Drawing is clipped to the canvas.
+func (c *Canvas) DrawBoxShadow(x, y, w, h int, radii Radii, shadow BoxShadow) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if c.backend != nil {
+		top, right, bottom, left := shadow.Extent()
+		c.clip(x-left, y-top, w+left+right, h+top+bottom)
+		c.backend.BoxShadow(c.offset.X+x, c.offset.Y+y, w, h, radii, shadow)
+		return
+	}
+	if c.scaled() {
+		shadow.OffsetX, shadow.OffsetY = c.deviceLength(shadow.OffsetX), c.deviceLength(shadow.OffsetY)
+		shadow.Blur, shadow.Spread = c.deviceLength(shadow.Blur), c.deviceLength(shadow.Spread)
+		x0, y0, x1, y1 := c.deviceX(x), c.deviceY(y), c.deviceX(x+w), c.deviceY(y+h)
+		c.device().DrawBoxShadow(x0, y0, x1-x0, y1-y0, radii.scaled(c.pixelRatio), shadow)
+		return
+	}
+
+	box := newRoundedRect(float64(x), float64(y), float64(w), float64(h), radii)
+	blur := float64(max(shadow.Blur, 0))
+
+	if shadow.Inset {
+		// The shadow is cast by everything outside a shrunken copy of the box
+		hole := box.outset(-float64(shadow.Spread))
+		hole.x += float64(shadow.OffsetX)
+		hole.y += float64(shadow.OffsetY)
+
+		x0, y0, x1, y1 := c.clipRect(x, y, w, h)
+		for py := y0; py < y1; py++ {
+			for px := x0; px < x1; px++ {
+				inside := box.coverage(px, py)
+				if inside <= 0 {
+					continue
+				}
+				intensity := 1 - shadowCoverage(hole.distance(float64(px)+0.5, float64(py)+0.5), blur)
+				c.blend(px, py, scaleAlpha(shadow.Color, intensity*inside))
+			}
+		}
+		return
+	}
+
+	caster := box.outset(float64(shadow.Spread))
+	caster.x += float64(shadow.OffsetX)
+	caster.y += float64(shadow.OffsetY)
+
+	reach := shadowReach(shadow.Blur)
+	x0, y0, x1, y1 := c.clipRect(
+		int(math.Floor(caster.x))-reach, int(math.Floor(caster.y))-reach,
+		int(math.Ceil(caster.w))+2*reach+1, int(math.Ceil(caster.h))+2*reach+1,
+	)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			outside := 1 - box.coverage(px, py)
+			if outside <= 0 {
+				continue
+			}
+			intensity := shadowCoverage(caster.distance(float64(px)+0.5, float64(py)+0.5), blur)
+			c.blend(px, py, scaleAlpha(shadow.Color, intensity*outside))
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestDrawBoxShadow(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	black := color.RGBA{0, 0, 0, 255}
+
+	canvas.DrawBoxShadow(30, 30, 40, 40, Radii{}, BoxShadow{Color: black, OffsetX: 5, OffsetY: 5, Blur: 10})
+
+	// Outer shadows don't paint under the box
+	if a := canvas.Img.RGBAAt(50, 50).A; a != 0 {
+		t.Errorf("Expected no shadow under the box, got alpha %d", a)
+	}
+
+	// The shadow is strongest next to the box and fades with distance
+	near := canvas.Img.RGBAAt(72, 50).A
+	far := canvas.Img.RGBAAt(85, 50).A
+	if near == 0 || far >= near {
+		t.Errorf("Expected the shadow to fade away from the box, got alpha %d near and %d far", near, far)
+	}
+
+	// The offset pushes the shadow to the bottom right
+	if left := canvas.Img.RGBAAt(27, 50).A; left >= near {
+		t.Errorf("Expected a weaker shadow on the left (%d) than on the right (%d)", left, near)
+	}
+}
+
+func TestDrawBoxShadowInset(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	black := color.RGBA{0, 0, 0, 255}
+
+	canvas.DrawBoxShadow(20, 20, 60, 60, Radii{}, BoxShadow{Color: black, Blur: 8, Inset: true})
+
+	if a := canvas.Img.RGBAAt(10, 50).A; a != 0 {
+		t.Errorf("Expected no inset shadow outside the box, got alpha %d", a)
+	}
+	edge := canvas.Img.RGBAAt(20, 50).A
+	center := canvas.Img.RGBAAt(50, 50).A
+	if edge == 0 || center >= edge {
+		t.Errorf("Expected the inset shadow to be strongest at the edge, got %d at the edge and %d in the center", edge, center)
+	}
+}
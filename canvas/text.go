@@ -3,23 +3,25 @@ package canvas
 import (
 	"image"
 	"image/color"
+	"strings"
 
 	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
 	"github.com/hvuhsg/render/types"
-	"golang.org/x/image/font/gofont/goregular"
 )
 
 type TextPainter struct {
 	Font      *truetype.Font
+	FontData  []byte // TrueType data Font was parsed from, needed by backends that embed fonts
 	FontSize  float64
 	TextColor color.Color
 }
 
 func NewTextPainter() *TextPainter {
-	font, _ := truetype.Parse(goregular.TTF)
+	font, data, _ := LookupFont(DefaultFont)
 	return &TextPainter{
 		Font:      font,
+		FontData:  data,
 		FontSize:  12,
 		TextColor: color.Black,
 	}
@@ -30,19 +32,33 @@ func (c *Canvas) DrawText(text string, x, y int, painter *TextPainter) {
 		painter = NewTextPainter()
 	}
 
+	if c.backend != nil {
+		size := c.MeasureText(text, painter)
+		c.clip(x, y, size.Width, size.Height)
+		c.backend.Text(text, c.offset.X+x, c.offset.Y+y, painter)
+		return
+	}
+
+	// Calculate the baseline position
+	// The y position is the baseline, so we need to adjust for the font height
+	baseline := y + int(painter.FontSize)
+
+	// Scaled canvases render the glyphs at a higher DPI in device pixels
+	d := c.device()
+	if c.scaled() {
+		x, baseline = c.deviceX(x), c.deviceY(baseline)
+	}
+
 	// Create a new freetype context
 	ctx := freetype.NewContext()
-	ctx.SetDPI(72)
+	ctx.SetDPI(72 * c.PixelRatio())
 	ctx.SetFont(painter.Font)
 	ctx.SetFontSize(painter.FontSize)
-	ctx.SetClip(image.Rect(c.offset.X, c.offset.Y, c.offset.X+c.Size.Width, c.offset.Y+c.Size.Height))
-	ctx.SetDst(c.Img)
+	ctx.SetClip(image.Rect(d.offset.X, d.offset.Y, d.offset.X+d.Size.Width, d.offset.Y+d.Size.Height))
+	ctx.SetDst(d.Img)
 	ctx.SetSrc(image.NewUniform(painter.TextColor))
 
-	// Calculate the baseline position
-	// The y position is the baseline, so we need to adjust for the font height
-	baseline := y + int(painter.FontSize)
-	pt := freetype.Pt(c.offset.X+x, c.offset.Y+baseline)
+	pt := freetype.Pt(d.offset.X+x, d.offset.Y+baseline)
 	ctx.DrawString(text, pt)
 }
 
@@ -66,3 +82,36 @@ func (c *Canvas) MeasureText(text string, painter *TextPainter) types.Size {
 		Height: int(painter.FontSize) + 4, // Use font size plus padding for height
 	}
 }
+
+// WrapText breaks text into lines no wider than maxWidth when drawn with painter.
+// Lines are broken at whitespace and at explicit newlines; a single word wider
+// than maxWidth is kept on its own line. A non-positive maxWidth disables wrapping.
+func (c *Canvas) WrapText(text string, maxWidth int, painter *TextPainter) []string {
+	paragraphs := strings.Split(text, "\n")
+	if maxWidth <= 0 {
+		return paragraphs
+	}
+
+	var lines []string
+	for _, paragraph := range paragraphs {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := words[0]
+		for _, word := range words[1:] {
+			candidate := line + " " + word
+			if c.MeasureText(candidate, painter).Width <= maxWidth {
+				line = candidate
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
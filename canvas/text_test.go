@@ -100,3 +100,33 @@ func TestDrawTextOutOfBounds(t *testing.T) {
 		}
 	}
 }
+
+func TestWrapText(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 200, Height: 100}, false)
+	painter := NewTextPainter()
+	painter.FontSize = 16
+
+	// Without a width limit the text stays on one line
+	lines := canvas.WrapText("one two three four", 0, painter)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line without wrapping, got %d", len(lines))
+	}
+
+	// Every wrapped line must fit in the requested width
+	maxWidth := canvas.MeasureText("one two", painter).Width
+	lines = canvas.WrapText("one two three four", maxWidth, painter)
+	if len(lines) < 2 {
+		t.Fatalf("Expected text to wrap onto multiple lines, got %v", lines)
+	}
+	for _, line := range lines {
+		if w := canvas.MeasureText(line, painter).Width; w > maxWidth {
+			t.Errorf("Line %q is %dpx wide, expected at most %dpx", line, w, maxWidth)
+		}
+	}
+
+	// Explicit newlines always break
+	lines = canvas.WrapText("first\nsecond", 1000, painter)
+	if len(lines) != 2 || lines[0] != "first" || lines[1] != "second" {
+		t.Errorf("Expected explicit newline to split lines, got %v", lines)
+	}
+}
@@ -0,0 +1,75 @@
+package canvas
+
+import (
+	"image"
+	"math"
+)
+
+// Tracer observes the render objects painted on a canvas and on the canvases derived
+// from it, for debugging and inspection tools. Objects are passed as any since canvases
+// don't know about render objects, see render_objects.PaintChild.
+type Tracer interface {
+	// BeginPaint is called before object paints on canvas
+	BeginPaint(object any, canvas *Canvas)
+	// EndPaint is called once object and its children have painted on canvas
+	EndPaint(object any, canvas *Canvas)
+}
+
+// SetTracer makes the objects painted on the canvas, its sub canvases and the layers
+// composited onto them report to tracer. A nil tracer stops tracing.
+func (c *Canvas) SetTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+// Tracer returns the tracer painting is reported to, nil when the canvas isn't traced
+func (c *Canvas) Tracer() Tracer {
+	return c.tracer
+}
+
+// Bounds returns the area the canvas covers in logical pixels of its image.
+// Offscreen canvases that Follow another canvas use the coordinates of that one instead,
+// scaled like the canvas will be when it is drawn.
+func (c *Canvas) Bounds() image.Rectangle {
+	return image.Rectangle{
+		Min: c.origin.Add(c.traceScaled(c.offset)),
+		Max: c.origin.Add(c.traceScaled(c.offset.Add(image.Pt(c.Size.Width, c.Size.Height)))),
+	}
+}
+
+// Follow makes an offscreen canvas that will be drawn at (x, y) of parent report to the
+// tracer of parent, with Bounds in the coordinates of parent
+func (c *Canvas) Follow(parent *Canvas, x, y int) {
+	c.FollowScaled(parent, x, y, 1, 1)
+}
+
+// FollowScaled is like Follow for an offscreen canvas that will be drawn at (x, y) of
+// parent scaled by scaleX and scaleY, such as the child of a FittedBox
+func (c *Canvas) FollowScaled(parent *Canvas, x, y int, scaleX, scaleY float64) {
+	c.tracer = parent.tracer
+	c.traceScale = [2]float64{parent.traceScaleX() * scaleX, parent.traceScaleY() * scaleY}
+	c.origin = parent.Bounds().Min.Add(parent.traceScaled(image.Pt(x, y))).Sub(c.traceScaled(c.offset))
+}
+
+// TraceScale returns how much larger Bounds is than the canvas, see FollowScaled
+func (c *Canvas) TraceScale() (x, y float64) {
+	return c.traceScaleX(), c.traceScaleY()
+}
+
+// traceScaled converts a distance on the canvas to one in the coordinates of Bounds
+func (c *Canvas) traceScaled(p image.Point) image.Point {
+	return image.Pt(int(math.Round(float64(p.X)*c.traceScaleX())), int(math.Round(float64(p.Y)*c.traceScaleY())))
+}
+
+func (c *Canvas) traceScaleX() float64 {
+	if c.traceScale[0] == 0 {
+		return 1
+	}
+	return c.traceScale[0]
+}
+
+func (c *Canvas) traceScaleY() float64 {
+	if c.traceScale[1] == 0 {
+		return 1
+	}
+	return c.traceScale[1]
+}
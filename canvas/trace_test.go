@@ -0,0 +1,76 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+// boundsRecorder remembers the bounds of the canvases objects were painted on
+type boundsRecorder struct {
+	bounds map[any]image.Rectangle
+}
+
+func (r *boundsRecorder) BeginPaint(object any, canvas *Canvas) {
+	r.bounds[object] = canvas.Bounds()
+}
+
+func (r *boundsRecorder) EndPaint(object any, canvas *Canvas) {}
+
+func TestTracerFollowsSubCanvases(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	rec := &boundsRecorder{bounds: map[any]image.Rectangle{}}
+	canvas.SetTracer(rec)
+
+	sub := canvas.SubCanvas(10, 20, types.Size{Width: 50, Height: 50}, nil).SubCanvas(5, 5, types.Size{Width: 10, Height: 10}, nil)
+	if sub.Tracer() != rec {
+		t.Fatalf("Expected sub canvases to keep the tracer")
+	}
+	if want := image.Rect(15, 25, 25, 35); sub.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, sub.Bounds())
+	}
+}
+
+func TestTracerFollowsLayers(t *testing.T) {
+	canvas := NewScaledCanvas(types.Size{Width: 100, Height: 100}, 2, false)
+	rec := &boundsRecorder{bounds: map[any]image.Rectangle{}}
+	canvas.SetTracer(rec)
+	sub := canvas.SubCanvas(30, 40, types.Size{Width: 20, Height: 20}, nil)
+
+	sub.PaintLayer(0.5, BlendNormal, func(layer *Canvas) {
+		rec.BeginPaint("faded", layer.SubCanvas(2, 3, types.Size{Width: 4, Height: 4}, nil))
+	})
+	if want := image.Rect(32, 43, 36, 47); rec.bounds["faded"] != want {
+		t.Errorf("Expected layers to report bounds %v, got %v", want, rec.bounds["faded"])
+	}
+
+	layer := NewLayer(types.Size{Width: 10, Height: 10})
+	layer.Follow(sub, -5, -5)
+	if layer.Tracer() != rec {
+		t.Errorf("Expected following layers to take the tracer")
+	}
+	if want := image.Rect(25, 35, 35, 45); layer.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, layer.Bounds())
+	}
+}
+
+func TestTracerFollowsScaledCanvases(t *testing.T) {
+	canvas := NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	sub := canvas.SubCanvas(10, 10, types.Size{Width: 50, Height: 50}, nil)
+
+	// Drawn at half size, so a 4x4 area at (8,8) covers 2x2 pixels at (4,4) of sub
+	offscreen := NewCanvas(types.Size{Width: 100, Height: 100}, true)
+	offscreen.FollowScaled(sub, 0, 0, 0.5, 0.5)
+	inner := offscreen.SubCanvas(8, 8, types.Size{Width: 4, Height: 4}, nil)
+	if want := image.Rect(14, 14, 16, 16); inner.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, inner.Bounds())
+	}
+
+	// Layers of the scaled canvas keep its scale
+	layer := NewLayer(types.Size{Width: 10, Height: 10})
+	layer.Follow(inner, 2, 2)
+	if want := image.Rect(15, 15, 20, 20); layer.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, layer.Bounds())
+	}
+}
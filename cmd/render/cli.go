@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/hvuhsg/render/terminal"
+	"github.com/hvuhsg/render/types"
+)
+
+// Exit codes
+const (
+	exitOK     = 0
+	exitFailed = 1 // Some layout failed to render
+	exitUsage  = 2 // Invalid arguments or unreadable input files
+)
+
+const usage = `Usage: render [flags] <layout>...
+       render serve [flags]
+
+Renders JSON, YAML or HTML layout files to images. A layout may be a directory,
+in which case every layout file in it is rendered.
+
+Flags:
+`
+
+// errReported stands for errors the flag set has already printed
+var errReported = errors.New("invalid flags")
+
+// config holds the command line options
+type config struct {
+	layouts     []string
+	output      string
+	size        types.Size
+	data        string
+	format      string
+	pixelRatio  float64
+	jobs        int
+	preview     bool
+	previewMode terminal.Mode
+	debug       bool
+	inspect     string
+}
+
+// parseArgs reads the flags, which may come before, between or after the layouts
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	cfg := &config{}
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&cfg.output, "o", "", "output file, directory or pattern with {{placeholders}}")
+	fs.StringVar(&cfg.output, "output", "", "same as -o")
+	size := fs.String("size", "800x600", "image size in pixels as WIDTHxHEIGHT")
+	fs.StringVar(&cfg.data, "data", "", "JSON data file: an object, an array or JSON lines of records")
+	fs.StringVar(&cfg.format, "format", "png", "output format when -o doesn't name a file: png, jpg, gif, bmp, svg or pdf")
+	fs.Float64Var(&cfg.pixelRatio, "pixel-ratio", 1, "device pixels per layout pixel, 2 for a retina image")
+	fs.IntVar(&cfg.jobs, "jobs", runtime.NumCPU(), "number of images rendered in parallel")
+	fs.BoolVar(&cfg.preview, "preview", false, "print the result to the terminal instead of saving it")
+	previewMode := fs.String("preview-mode", "auto", "terminal output for --preview: auto, truecolor, 256 or sixel")
+	fs.StringVar(&cfg.inspect, "inspect", "", "print the layout of every object as text or json instead of saving images")
+	fs.BoolVar(&cfg.debug, "debug", false, "draw the bounds, padding, anchors, baselines and overflow of every object over the image")
+
+	for {
+		if err := fs.Parse(args); err == flag.ErrHelp {
+			return nil, err
+		} else if err != nil {
+			return nil, errReported
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		cfg.layouts = append(cfg.layouts, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(cfg.layouts) == 0 {
+		return nil, errors.New("no layout given")
+	}
+	parsed, err := parseSize(*size)
+	if err != nil {
+		return nil, err
+	}
+	cfg.size = parsed
+	if cfg.pixelRatio <= 0 {
+		return nil, fmt.Errorf("invalid pixel ratio %g, expected a positive number", cfg.pixelRatio)
+	}
+	if cfg.jobs < 1 {
+		return nil, fmt.Errorf("invalid number of jobs %d", cfg.jobs)
+	}
+	cfg.format = strings.TrimPrefix(strings.ToLower(cfg.format), ".")
+	if !outputFormats[cfg.format] {
+		return nil, fmt.Errorf("unknown format %q, expected png, jpg, gif, bmp, svg or pdf", cfg.format)
+	}
+	if cfg.inspect != "" && cfg.inspect != "text" && cfg.inspect != "json" {
+		return nil, fmt.Errorf("unknown inspect format %q, expected text or json", cfg.inspect)
+	}
+	mode, err := terminal.ParseMode(*previewMode)
+	if err != nil {
+		return nil, err
+	}
+	cfg.previewMode = mode
+	return cfg, nil
+}
+
+// parseSize reads a size such as 1200x630
+func parseSize(s string) (types.Size, error) {
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	width, werr := strconv.Atoi(w)
+	height, herr := strconv.Atoi(h)
+	if !ok || werr != nil || herr != nil || width <= 0 || height <= 0 {
+		return types.Size{}, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT such as 1200x630", s)
+	}
+	return types.Size{Width: width, Height: height}, nil
+}
+
+// run executes the command and returns its exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "serve" {
+		return runServe(args[1:], stderr)
+	}
+
+	cfg, err := parseArgs(args, stderr)
+	switch {
+	case err == flag.ErrHelp:
+		return exitOK
+	case err == errReported:
+		return exitUsage
+	case err != nil:
+		fmt.Fprintf(stderr, "render: %v\nRun render -h for usage.\n", err)
+		return exitUsage
+	}
+
+	jobs, err := plan(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "render: %v\n", err)
+		return exitUsage
+	}
+
+	failed := 0
+	for i, err := range renderAll(cfg, jobs, stdout) {
+		if err != nil {
+			failed++
+			fmt.Fprintf(stderr, "render: %s: %v\n", jobs[i].describe(), err)
+		}
+	}
+	if failed > 0 {
+		if len(jobs) > 1 {
+			fmt.Fprintf(stderr, "render: %d of %d images failed\n", failed, len(jobs))
+		}
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hvuhsg/render/scene"
+)
+
+// Output formats by extension
+var outputFormats = map[string]bool{"png": true, "jpg": true, "jpeg": true, "gif": true, "bmp": true, "svg": true, "pdf": true}
+
+// Extensions of the files read as layouts
+var layoutExtensions = map[string]bool{".json": true, ".yaml": true, ".yml": true, ".html": true, ".htm": true}
+
+// layout is a layout file, loaded on first use and shared by the jobs rendering it
+type layout struct {
+	path string
+	name string // File name without extension
+
+	once     sync.Once
+	template *scene.Template
+	err      error
+}
+
+// job renders one layout with one data record
+type job struct {
+	layout  *layout
+	record  any
+	index   int // 1 based record number
+	records int // Number of records the layout is rendered with
+	output  string
+}
+
+// describe names the layout and record of a job for error messages
+func (j *job) describe() string {
+	if j.records > 1 {
+		return fmt.Sprintf("%s (record %d)", j.layout.path, j.index)
+	}
+	return j.layout.path
+}
+
+// plan lists the images to render and where each one is written
+func plan(cfg *config) ([]*job, error) {
+	layouts, batch, err := findLayouts(cfg.layouts)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []any{nil}
+	if cfg.data != "" {
+		data, err := os.ReadFile(cfg.data)
+		if err != nil {
+			return nil, err
+		}
+		records, err = scene.DecodeRecords(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.data, err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("%s: no records", cfg.data)
+		}
+		// Arrays and JSON lines are batches even with a single record
+		if trimmed := strings.TrimSpace(string(data)); !strings.HasPrefix(trimmed, "{") || len(records) > 1 {
+			batch = true
+		}
+	}
+
+	var jobs []*job
+	for _, l := range layouts {
+		for i, record := range records {
+			jobs = append(jobs, &job{layout: l, record: record, index: i + 1, records: len(records)})
+		}
+	}
+	batch = batch || len(jobs) > 1
+	if cfg.preview {
+		return jobs, nil
+	}
+
+	written := map[string]*job{}
+	for _, j := range jobs {
+		output, err := outputPath(cfg, j, batch)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := written[output]; ok {
+			return nil, fmt.Errorf("%s and %s would both be written to %s", other.describe(), j.describe(), output)
+		}
+		written[output] = j
+		j.output = output
+	}
+	return jobs, nil
+}
+
+// findLayouts expands directories into the layout files they contain
+func findLayouts(paths []string) ([]*layout, bool, error) {
+	var layouts []*layout
+	batch := false
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, false, err
+		}
+		if !info.IsDir() {
+			if !layoutExtensions[strings.ToLower(filepath.Ext(path))] {
+				return nil, false, fmt.Errorf("%s: unknown layout format, expected .json, .yaml, .yml or .html", path)
+			}
+			layouts = append(layouts, newLayout(path))
+			continue
+		}
+
+		batch = true
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, false, err
+		}
+		found := false
+		for _, entry := range entries {
+			if !entry.IsDir() && layoutExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+				layouts = append(layouts, newLayout(filepath.Join(path, entry.Name())))
+				found = true
+			}
+		}
+		if !found {
+			return nil, false, fmt.Errorf("%s: no layout files", path)
+		}
+	}
+	return layouts, batch, nil
+}
+
+func newLayout(path string) *layout {
+	base := filepath.Base(path)
+	return &layout{path: path, name: strings.TrimSuffix(base, filepath.Ext(base))}
+}
+
+// outputPath returns the file a job is written to.
+// Single images go to -o or next to the layout; batches go into the -o directory or pattern.
+func outputPath(cfg *config, j *job, batch bool) (string, error) {
+	if !batch && cfg.output != "" && !isDir(cfg.output) {
+		return cfg.output, checkFormat(cfg.output)
+	}
+
+	if strings.Contains(cfg.output, "{{") {
+		expanded, err := scene.NewTemplate(cfg.output).Expand(patternData(j))
+		if err != nil {
+			return "", fmt.Errorf("output pattern for %s: %w", j.describe(), err)
+		}
+		output, ok := expanded.(string)
+		if !ok || output == "" {
+			return "", fmt.Errorf("output pattern for %s: expected a file name, got %v", j.describe(), expanded)
+		}
+		return output, checkFormat(output)
+	}
+
+	if batch && cfg.output != "" && filepath.Ext(cfg.output) != "" && !isDir(cfg.output) {
+		return "", errors.New("-o must be a directory or a pattern with {{placeholders}} when rendering several images")
+	}
+	dir := cfg.output
+	if dir == "" {
+		dir = filepath.Dir(j.layout.path)
+	}
+	name := j.layout.name
+	if j.records > 1 {
+		name = fmt.Sprintf("%s-%d", name, j.index)
+	}
+	return filepath.Join(dir, name+"."+cfg.format), nil
+}
+
+// patternData is what output patterns see: the record's fields with the record number
+// as index and the layout name as layout, unless the record has fields of those names
+func patternData(j *job) map[string]any {
+	data := map[string]any{}
+	if fields, ok := j.record.(map[string]any); ok {
+		for key, value := range fields {
+			data[key] = value
+		}
+	}
+	for key, value := range map[string]any{"index": float64(j.index), "layout": j.layout.name} {
+		if _, ok := data[key]; !ok {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+func checkFormat(path string) error {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if !outputFormats[ext] {
+		formats := make([]string, 0, len(outputFormats))
+		for format := range outputFormats {
+			formats = append(formats, format)
+		}
+		slices.Sort(formats)
+		return fmt.Errorf("%s: unknown output format, expected one of %s", path, strings.Join(formats, ", "))
+	}
+	return nil
+}
+
+func isDir(path string) bool {
+	if strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator)) {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,29 @@
+// Command render paints layout files to images.
+//
+// Layouts are scenes in JSON or YAML (see the scene package) or HTML with inline CSS
+// (see the markup package). Both may contain {{placeholders}} filled from a data file.
+//
+//	render layout.json -o out.png --size 1200x630 --data data.json
+//
+// A directory of layouts, several layouts, or a data file holding a JSON array or JSON
+// lines renders one image per layout and record. Their output is named after the layout
+// and record number in the directory given by -o, or by an -o pattern with placeholders
+// such as "cards/{{user.id}}.png", which also sees {{index}} and {{layout}}.
+//
+// The output format follows the file extension: png, jpg, gif, bmp, svg or pdf.
+//
+// The exit code is 0 on success, 1 when any image failed to render and 2 for invalid
+// arguments or unreadable layout and data files.
+//
+// render serve runs the HTTP render API of the server package:
+//
+//	render serve --addr :8080 --timeout 5s --concurrency 4
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
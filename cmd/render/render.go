@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/inspect"
+	"github.com/hvuhsg/render/markup"
+	"github.com/hvuhsg/render/pdf"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/scene"
+	"github.com/hvuhsg/render/svg"
+	"github.com/hvuhsg/render/terminal"
+	"github.com/hvuhsg/render/types"
+)
+
+// load parses the layout file once; HTML layouts are read as a template string
+func (l *layout) load() (*scene.Template, error) {
+	l.once.Do(func() {
+		if ext := strings.ToLower(filepath.Ext(l.path)); ext == ".html" || ext == ".htm" {
+			data, err := os.ReadFile(l.path)
+			l.template, l.err = scene.NewTemplate(string(data)), err
+			// Data is text, it can't add elements or read files through them
+			l.template.Escape = html.EscapeString
+			return
+		}
+		l.template, l.err = scene.LoadTemplate(l.path)
+	})
+	return l.template, l.err
+}
+
+// build creates the render tree of a layout for a data record
+func (l *layout) build(record any) (render_objects.RenderObject, error) {
+	template, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := template.Source.(string); ok {
+		expanded, err := template.Expand(record)
+		if err != nil {
+			return nil, err
+		}
+		return markup.Parse(expanded.(string), &markup.Options{BaseDir: filepath.Dir(l.path)})
+	}
+	return template.Build(record)
+}
+
+// renderAll renders the jobs on a pool of workers, returning the error of each job
+func renderAll(cfg *config, jobs []*job, stdout io.Writer) []error {
+	errs := make([]error, len(jobs))
+	workers := cfg.jobs
+	if cfg.preview || cfg.inspect != "" {
+		// Previews and layouts are printed one after the other
+		workers = 1
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = renderJob(cfg, jobs[i], stdout)
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
+
+// renderJob builds a job's tree and writes or previews the result
+func renderJob(cfg *config, j *job, stdout io.Writer) (err error) {
+	// A broken custom render object fails its own image, not the whole batch
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while rendering: %v", r)
+		}
+	}()
+
+	root, err := j.layout.build(j.record)
+	if err != nil {
+		return err
+	}
+	if cfg.inspect != "" {
+		return printLayout(stdout, cfg.inspect, j, inspect.Inspect(root, cfg.size))
+	}
+	if cfg.debug {
+		root = &inspect.Overlay{Child: root, Labels: true}
+	}
+
+	if cfg.preview {
+		canvas := cv.NewScaledCanvas(cfg.size, cfg.pixelRatio, false)
+		root.Paint(canvas)
+		return terminal.Print(stdout, canvas.Image(), &terminal.Options{Mode: cfg.previewMode})
+	}
+
+	if dir := filepath.Dir(j.output); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return write(root, j.output, cfg.size, cfg.pixelRatio)
+}
+
+// printLayout prints the layout of a job, as JSON lines or as text trees under the job's name
+func printLayout(w io.Writer, format string, j *job, layout *inspect.Layout) error {
+	if format == "json" {
+		return json.NewEncoder(w).Encode(layout)
+	}
+	_, err := fmt.Fprintf(w, "%s:\n%s\n", j.describe(), layout)
+	return err
+}
+
+// write paints a tree into a file in the format named by its extension
+func write(root render_objects.RenderObject, path string, size types.Size, pixelRatio float64) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".svg" && ext != ".pdf" {
+		canvas := cv.NewScaledCanvas(size, pixelRatio, false)
+		root.Paint(canvas)
+		return canvas.SaveFile(path, nil)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if ext == ".svg" {
+		err = svg.Render(f, root, size)
+	} else {
+		err = pdf.Render(f, []render_objects.RenderObject{root}, size, types.EdgeInsets{})
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hvuhsg/render/inspect"
+	"github.com/hvuhsg/render/types"
+)
+
+const card = `{"type": "Container", "padding": 10, "decoration": {"color": "#ff0000"}, "child": {"type": "Text", "text": "Hi {{name}}"}}`
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runArgs(args ...string) (int, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stderr.String()
+}
+
+func TestRenderSingle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"card.json": card, "data.json": `{"name": "Ada"}`})
+	out := filepath.Join(dir, "out.png")
+
+	code, stderr := runArgs(filepath.Join(dir, "card.json"), "-o", out, "--size", "120x40", "--data", filepath.Join(dir, "data.json"))
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("Expected the output file, got %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 120 || bounds.Dy() != 40 {
+		t.Errorf("Expected a 120x40 image, got %v", bounds)
+	}
+	if r, g, _, _ := img.At(2, 2).RGBA(); r>>8 != 255 || g != 0 {
+		t.Errorf("Expected the red background, got %v", img.At(2, 2))
+	}
+}
+
+func TestRenderDebug(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"card.json": card, "data.json": `{"name": "Ada"}`})
+	out := filepath.Join(dir, "out.png")
+
+	code, stderr := runArgs(filepath.Join(dir, "card.json"), "-o", out, "--size", "120x40", "--data", filepath.Join(dir, "data.json"), "--debug")
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("Expected the output file, got %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	// The padding of the container is tinted over its red background
+	if r, _, b, _ := img.At(5, 20).RGBA(); r>>8 == 255 || b == 0 {
+		t.Errorf("Expected the padding to be tinted, got %v", img.At(5, 20))
+	}
+}
+
+func TestRenderInspect(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"card.json": card, "data.json": `{"name": "Ada"}`})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{filepath.Join(dir, "card.json"), "--size", "120x40", "--data", filepath.Join(dir, "data.json"), "--inspect", "text"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Container at 0,0 size 72x40 in 120x40", "  Text at 10,10", `Text="Hi Ada"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the layout to contain %q, got %s", want, out)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("Expected no images to be written, got %d files", len(entries))
+	}
+
+	if code, stderr := runArgs(filepath.Join(dir, "card.json"), "--inspect", "xml"); code != exitUsage || !strings.Contains(stderr, "unknown inspect format") {
+		t.Errorf("Expected an unknown inspect format to be a usage error, got %d: %s", code, stderr)
+	}
+}
+
+func TestRenderBatch(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"layouts/card.json":  card,
+		"layouts/page.html":  `<div style="padding: 4px">Hello {{name}}</div>`,
+		"layouts/notes.txt":  "ignored",
+		"people.jsonl":       "{\"id\": \"ada\", \"name\": \"Ada\"}\n{\"id\": \"alan\", \"name\": \"Alan\"}\n",
+		"single/layout.yaml": "type: Text\ntext: hello\n",
+	})
+
+	pattern := filepath.Join(dir, "out", "{{layout}}-{{id}}.png")
+	code, stderr := runArgs("--data", filepath.Join(dir, "people.jsonl"), filepath.Join(dir, "layouts"), "-o", pattern, "--size", "80x30")
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+	}
+	for _, name := range []string{"card-ada.png", "card-alan.png", "page-ada.png", "page-alan.png"} {
+		if _, err := os.Stat(filepath.Join(dir, "out", name)); err != nil {
+			t.Errorf("Expected %s to be written", name)
+		}
+	}
+
+	// Without a pattern batches are named after the layout and record number
+	code, stderr = runArgs(filepath.Join(dir, "layouts", "card.json"), "--data", filepath.Join(dir, "people.jsonl"), "-o", filepath.Join(dir, "numbered")+"/", "--format", "svg")
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+	}
+	for _, name := range []string{"card-1.svg", "card-2.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, "numbered", name)); err != nil {
+			t.Errorf("Expected %s to be written", name)
+		}
+	}
+
+	// Without -o the image is written next to the layout
+	code, stderr = runArgs(filepath.Join(dir, "single", "layout.yaml"))
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "single", "layout.png")); err != nil {
+		t.Error("Expected layout.png next to the layout")
+	}
+}
+
+func TestHTMLLayoutEscapesData(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"page.html": `<div>Hi {{name}}</div>`, "secret.txt": "secret"})
+	page := &layout{path: filepath.Join(dir, "page.html")}
+
+	// Data is text: it can't open elements or load files next to the layout
+	for _, name := range []string{"Tom & Jerry <3", `<img src="secret.txt">`} {
+		root, err := page.build(map[string]any{"name": name})
+		if err != nil {
+			t.Errorf("Expected %q to be placed as text, got %v", name, err)
+			continue
+		}
+		if out := inspect.Inspect(root, types.Size{Width: 300, Height: 40}).String(); !strings.Contains(out, fmt.Sprintf("Text=%q", "Hi "+name)) {
+			t.Errorf("Expected the text %q, got %s", "Hi "+name, out)
+		}
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"card.json":   card,
+		"broken.json": `{"type": "Text", "text": "a", "colour": "red"}`,
+		"rows.jsonl":  "{\"name\": \"a\"}\n{\"name\": \"b\"}\n",
+	})
+	card, broken := filepath.Join(dir, "card.json"), filepath.Join(dir, "broken.json")
+
+	tests := []struct {
+		args    []string
+		code    int
+		message string
+	}{
+		{nil, exitUsage, "no layout given"},
+		{[]string{card, "--size", "wide"}, exitUsage, `invalid size "wide"`},
+		{[]string{card, "--bogus"}, exitUsage, "flag provided but not defined"},
+		{[]string{filepath.Join(dir, "missing.json")}, exitUsage, "no such file"},
+		{[]string{card, "-o", filepath.Join(dir, "out.txt")}, exitUsage, "unknown output format"},
+		{[]string{card, "--data", filepath.Join(dir, "rows.jsonl"), "-o", filepath.Join(dir, "same.png")}, exitUsage, "-o must be a directory"},
+		{[]string{card, "-o", filepath.Join(dir, "a.png")}, exitFailed, `$.child.text: unknown variable "name"`},
+		{[]string{broken, card, "--data", filepath.Join(dir, "rows.jsonl"), "-o", dir}, exitFailed, "2 of 4 images failed"},
+	}
+
+	for _, test := range tests {
+		code, stderr := runArgs(test.args...)
+		if code != test.code {
+			t.Errorf("Expected exit code %d for %v, got %d: %s", test.code, test.args, code, stderr)
+		}
+		if !strings.Contains(stderr, test.message) {
+			t.Errorf("Expected %q in the errors for %v, got %q", test.message, test.args, stderr)
+		}
+	}
+}
+
+func TestServeArgs(t *testing.T) {
+	for _, args := range [][]string{{"serve", "--bogus"}, {"serve", "extra"}, {"serve", "--timeout", "0s"}} {
+		if code, stderr := runArgs(args...); code != exitUsage {
+			t.Errorf("Expected exit code %d for %v, got %d: %s", exitUsage, args, code, stderr)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	size, err := parseSize("1200x630")
+	if err != nil || size.Width != 1200 || size.Height != 630 {
+		t.Errorf("Expected 1200x630, got %v, %v", size, err)
+	}
+	for _, invalid := range []string{"", "100", "0x10", "ax10", "10x-1"} {
+		if _, err := parseSize(invalid); err == nil {
+			t.Errorf("Expected %q to be invalid", invalid)
+		}
+	}
+}
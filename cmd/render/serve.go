@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/hvuhsg/render/server"
+)
+
+const serveUsage = `Usage: render serve [flags]
+
+Serves the render API: POST a JSON tree or HTML with data to /render to get an image.
+
+Flags:
+`
+
+// runServe runs the render server until it is interrupted
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("render serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, serveUsage)
+		fs.PrintDefaults()
+	}
+	addr := fs.String("addr", ":8080", "address to listen on")
+	maxBody := fs.Int64("max-body", 1<<20, "largest request body in bytes")
+	maxPixels := fs.Int("max-pixels", 4096*4096, "largest image in device pixels")
+	timeout := fs.Duration("timeout", 10*time.Second, "longest a request may wait for and spend on rendering")
+	concurrency := fs.Int("concurrency", runtime.NumCPU(), "renders running at once")
+	cacheBytes := fs.Int64("cache-bytes", 64<<20, "size of the result cache in bytes, 0 to disable it")
+
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return exitOK
+	} else if err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "render serve: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+	if *maxBody <= 0 || *maxPixels <= 0 || *timeout <= 0 || *concurrency <= 0 || *cacheBytes < 0 {
+		fmt.Fprintln(stderr, "render serve: limits must be positive")
+		return exitUsage
+	}
+	if *cacheBytes == 0 {
+		*cacheBytes = -1
+	}
+
+	srv := &http.Server{
+		Addr: *addr,
+		Handler: server.New(&server.Options{
+			MaxBodyBytes:  *maxBody,
+			MaxPixels:     *maxPixels,
+			Timeout:       *timeout,
+			MaxConcurrent: *concurrency,
+			CacheBytes:    *cacheBytes,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Finish the requests in flight on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(stderr, "render serve: listening on %s\n", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "render serve: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,135 @@
+// Package colors parses CSS color strings, converts between sRGB and the HSL, HSV,
+// OKLab and OKLCH color spaces, and mixes colors in gamma-encoded or linear light.
+//
+// Colors are returned as alpha-premultiplied color.RGBA values, like the rest of the renderer uses.
+package colors
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Space is the color space colors are interpolated in
+type Space int
+
+const (
+	SpaceSRGB   Space = iota // Gamma-encoded sRGB, the way most images and browsers blend
+	SpaceLinear              // Linear-light sRGB, physically correct mixing of light
+	SpaceOKLab               // Perceptually uniform OKLab, even steps in lightness and hue
+)
+
+// Linearize converts a gamma-encoded sRGB channel in [0, 1] to linear light
+func Linearize(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// Delinearize converts a linear-light channel in [0, 1] to gamma-encoded sRGB
+func Delinearize(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// straight returns the unpremultiplied channels of c in [0, 1]
+func straight(c color.Color) (r, g, b, a float64) {
+	pr, pg, pb, pa := c.RGBA()
+	if pa == 0 {
+		return 0, 0, 0, 0
+	}
+	a = float64(pa) / 0xffff
+	return float64(pr) / float64(pa), float64(pg) / float64(pa), float64(pb) / float64(pa), a
+}
+
+// premultiplied builds a color.RGBA from unpremultiplied channels in [0, 1], clamping them
+func premultiplied(r, g, b, a float64) color.RGBA {
+	a = clamp(a)
+	channel := func(v float64) uint8 {
+		return uint8(math.Round(clamp(v) * a * 255))
+	}
+	return color.RGBA{R: channel(r), G: channel(g), B: channel(b), A: uint8(math.Round(a * 255))}
+}
+
+// rgba64 returns the premultiplied 16 bit channels of unpremultiplied ones, for color.Color implementations
+func rgba64(r, g, b, a float64) (uint32, uint32, uint32, uint32) {
+	a = clamp(a)
+	channel := func(v float64) uint32 {
+		return uint32(math.Round(clamp(v) * a * 0xffff))
+	}
+	return channel(r), channel(g), channel(b), uint32(math.Round(a * 0xffff))
+}
+
+func clamp(v float64) float64 {
+	return min(max(v, 0), 1)
+}
+
+// RGBA converts any color to an alpha-premultiplied color.RGBA
+func RGBA(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+// Hex formats a color as #rrggbb, or #rrggbbaa when it isn't opaque
+func Hex(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// Mix interpolates from a to b in the given space, t being 0 for a and 1 for b.
+// Channels are weighted by alpha, so mixing with a transparent color only fades the other one.
+func Mix(a, b color.Color, t float64, space Space) color.RGBA {
+	switch space {
+	case SpaceLinear:
+		ar, ag, ab, aa := straight(a)
+		br, bg, bb, ba := straight(b)
+		alpha := aa + (ba-aa)*t
+		if alpha == 0 {
+			return color.RGBA{}
+		}
+		channel := func(x, y float64) float64 {
+			mixed := Linearize(x)*aa + (Linearize(y)*ba-Linearize(x)*aa)*t
+			return Delinearize(mixed / alpha)
+		}
+		return premultiplied(channel(ar, br), channel(ag, bg), channel(ab, bb), alpha)
+	case SpaceOKLab:
+		from, to := ToOKLab(a), ToOKLab(b)
+		alpha := from.Alpha + (to.Alpha-from.Alpha)*t
+		if alpha == 0 {
+			return color.RGBA{}
+		}
+		channel := func(x, y float64) float64 {
+			return (x*from.Alpha + (y*to.Alpha-x*from.Alpha)*t) / alpha
+		}
+		return RGBA(OKLab{L: channel(from.L, to.L), A: channel(from.A, to.A), B: channel(from.B, to.B), Alpha: alpha})
+	default:
+		from, to := RGBA(a), RGBA(b)
+		lerp := func(x, y uint8) uint8 {
+			return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+		}
+		return color.RGBA{R: lerp(from.R, to.R), G: lerp(from.G, to.G), B: lerp(from.B, to.B), A: lerp(from.A, to.A)}
+	}
+}
+
+// Lighten raises the HSL lightness of c by amount, from 0 to 1
+func Lighten(c color.Color, amount float64) color.RGBA {
+	hsl := ToHSL(c)
+	hsl.L = clamp(hsl.L + amount)
+	return RGBA(hsl)
+}
+
+// Darken lowers the HSL lightness of c by amount, from 0 to 1
+func Darken(c color.Color, amount float64) color.RGBA {
+	return Lighten(c, -amount)
+}
+
+// WithAlpha returns c with its opacity replaced by alpha, from 0 to 1
+func WithAlpha(c color.Color, alpha float64) color.RGBA {
+	r, g, b, _ := straight(c)
+	return premultiplied(r, g, b, alpha)
+}
@@ -0,0 +1,80 @@
+package colors
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestHSLRoundTrip(t *testing.T) {
+	for _, c := range []color.RGBA{{255, 0, 0, 255}, {12, 200, 99, 255}, {128, 128, 128, 255}, {30, 60, 250, 255}} {
+		if got := RGBA(ToHSL(c)); got != c {
+			t.Errorf("Expected %v to survive HSL, got %v", c, got)
+		}
+		if got := RGBA(ToHSV(c)); got != c {
+			t.Errorf("Expected %v to survive HSV, got %v", c, got)
+		}
+		if got := RGBA(ToOKLCH(c)); got != c {
+			t.Errorf("Expected %v to survive OKLCH, got %v", c, got)
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	hsl := ToHSL(color.RGBA{0, 0, 255, 255})
+	if hsl.H != 240 || hsl.S != 1 || hsl.L != 0.5 {
+		t.Errorf("Expected blue to be hsl(240, 1, 0.5), got %+v", hsl)
+	}
+
+	hsv := ToHSV(color.RGBA{0, 128, 0, 255})
+	if hsv.H != 120 || hsv.S != 1 || !near(hsv.V, 128.0/255, 1e-9) {
+		t.Errorf("Expected green to be hsv(120, 1, 0.5), got %+v", hsv)
+	}
+
+	// Reference values from the OKLab specification
+	lab := ToOKLab(color.RGBA{255, 255, 255, 255})
+	if !near(lab.L, 1, 1e-4) || !near(lab.A, 0, 1e-4) || !near(lab.B, 0, 1e-4) {
+		t.Errorf("Expected white to be oklab(1 0 0), got %+v", lab)
+	}
+	lch := ToOKLCH(color.RGBA{255, 0, 0, 255})
+	if !near(lch.L, 0.628, 1e-3) || !near(lch.C, 0.2577, 1e-3) || !near(lch.H, 29.23, 0.1) {
+		t.Errorf("Expected red to be oklch(0.628 0.258 29.2), got %+v", lch)
+	}
+}
+
+func TestMix(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+
+	if got := Mix(red, blue, 0.5, SpaceSRGB); got != (color.RGBA{128, 0, 128, 255}) {
+		t.Errorf("Expected sRGB mix to average the channels, got %v", got)
+	}
+	// Half the light of each is brighter than half the encoded value
+	if got := Mix(red, blue, 0.5, SpaceLinear); got != (color.RGBA{188, 0, 188, 255}) {
+		t.Errorf("Expected linear mix to be (188, 0, 188), got %v", got)
+	}
+	if got := Mix(red, blue, 0, SpaceOKLab); got != red {
+		t.Errorf("Expected OKLab mix at 0 to be the first color, got %v", got)
+	}
+
+	// Mixing with transparent only fades the color
+	if got := Mix(red, color.RGBA{}, 0.5, SpaceLinear); got != (color.RGBA{127, 0, 0, 128}) {
+		t.Errorf("Expected half transparent red, got %v", got)
+	}
+}
+
+func TestLightenDarken(t *testing.T) {
+	gray := color.RGBA{128, 128, 128, 255}
+	if got := Lighten(gray, 0.2); got != (color.RGBA{179, 179, 179, 255}) {
+		t.Errorf("Expected lighter gray, got %v", got)
+	}
+	if got := Darken(gray, 1); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("Expected darkening fully to give black, got %v", got)
+	}
+	if got := WithAlpha(gray, 0.5); got != (color.RGBA{64, 64, 64, 128}) {
+		t.Errorf("Expected half transparent gray, got %v", got)
+	}
+}
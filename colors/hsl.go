@@ -0,0 +1,74 @@
+package colors
+
+import (
+	"image/color"
+	"math"
+)
+
+// HSL is a color as hue in degrees, saturation, lightness and alpha, the last three from 0 to 1
+type HSL struct {
+	H, S, L, A float64
+}
+
+// ToHSL converts any color to HSL
+func ToHSL(c color.Color) HSL {
+	r, g, b, a := straight(c)
+	high, low := max(r, g, b), min(r, g, b)
+	l := (high + low) / 2
+	if high == low {
+		return HSL{H: 0, S: 0, L: l, A: a}
+	}
+
+	d := high - low
+	s := d / (1 - math.Abs(2*l-1))
+	return HSL{H: hue(r, g, b, high, d), S: s, L: l, A: a}
+}
+
+func (c HSL) RGBA() (r, g, b, a uint32) {
+	chroma := (1 - math.Abs(2*clamp(c.L)-1)) * clamp(c.S)
+	rf, gf, bf := fromHue(c.H, chroma)
+	m := clamp(c.L) - chroma/2
+	return rgba64(rf+m, gf+m, bf+m, c.A)
+}
+
+// hue returns the hue in degrees of an RGB color with the given maximum channel and chroma
+func hue(r, g, b, high, chroma float64) float64 {
+	var h float64
+	switch high {
+	case r:
+		h = math.Mod((g-b)/chroma, 6)
+	case g:
+		h = (b-r)/chroma + 2
+	default:
+		h = (r-g)/chroma + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// fromHue returns the RGB channels of a hue with the given chroma, before adding the lightness offset
+func fromHue(h, chroma float64) (r, g, b float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	sector := h / 60
+	x := chroma * (1 - math.Abs(math.Mod(sector, 2)-1))
+	switch {
+	case sector < 1:
+		return chroma, x, 0
+	case sector < 2:
+		return x, chroma, 0
+	case sector < 3:
+		return 0, chroma, x
+	case sector < 4:
+		return 0, x, chroma
+	case sector < 5:
+		return x, 0, chroma
+	default:
+		return chroma, 0, x
+	}
+}
@@ -0,0 +1,27 @@
+package colors
+
+import "image/color"
+
+// HSV is a color as hue in degrees, saturation, value and alpha, the last three from 0 to 1
+type HSV struct {
+	H, S, V, A float64
+}
+
+// ToHSV converts any color to HSV
+func ToHSV(c color.Color) HSV {
+	r, g, b, a := straight(c)
+	high, low := max(r, g, b), min(r, g, b)
+	if high == low {
+		return HSV{H: 0, S: 0, V: high, A: a}
+	}
+
+	d := high - low
+	return HSV{H: hue(r, g, b, high, d), S: d / high, V: high, A: a}
+}
+
+func (c HSV) RGBA() (r, g, b, a uint32) {
+	chroma := clamp(c.V) * clamp(c.S)
+	rf, gf, bf := fromHue(c.H, chroma)
+	m := clamp(c.V) - chroma
+	return rgba64(rf+m, gf+m, bf+m, c.A)
+}
@@ -0,0 +1,67 @@
+package colors
+
+import (
+	"image/color"
+	"math"
+)
+
+// OKLab is a color in the perceptually uniform OKLab space.
+// L is the lightness from 0 to 1, A and B the green-red and blue-yellow axes, roughly within ±0.4.
+type OKLab struct {
+	L, A, B, Alpha float64
+}
+
+// ToOKLab converts any color to OKLab
+func ToOKLab(c color.Color) OKLab {
+	r, g, b, a := straight(c)
+	r, g, b = Linearize(r), Linearize(g), Linearize(b)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return OKLab{
+		L:     0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A:     1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B:     0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+		Alpha: a,
+	}
+}
+
+// RGBA converts to sRGB, clipping colors outside the sRGB gamut
+func (c OKLab) RGBA() (r, g, b, a uint32) {
+	l := c.L + 0.3963377774*c.A + 0.2158037573*c.B
+	m := c.L - 0.1055613458*c.A - 0.0638541728*c.B
+	s := c.L - 0.0894841775*c.A - 1.2914855480*c.B
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	rf := 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	gf := -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	bf := -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+	return rgba64(Delinearize(clamp(rf)), Delinearize(clamp(gf)), Delinearize(clamp(bf)), c.Alpha)
+}
+
+// OKLCH is OKLab in polar form: lightness from 0 to 1, chroma and hue in degrees
+type OKLCH struct {
+	L, C, H, Alpha float64
+}
+
+// ToOKLCH converts any color to OKLCH
+func ToOKLCH(c color.Color) OKLCH {
+	lab := ToOKLab(c)
+	h := math.Atan2(lab.B, lab.A) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return OKLCH{L: lab.L, C: math.Hypot(lab.A, lab.B), H: h, Alpha: lab.Alpha}
+}
+
+// OKLab converts the color to rectangular OKLab coordinates
+func (c OKLCH) OKLab() OKLab {
+	h := c.H * math.Pi / 180
+	return OKLab{L: c.L, A: c.C * math.Cos(h), B: c.C * math.Sin(h), Alpha: c.Alpha}
+}
+
+func (c OKLCH) RGBA() (r, g, b, a uint32) {
+	return c.OKLab().RGBA()
+}
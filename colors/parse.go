@@ -0,0 +1,224 @@
+package colors
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+var ErrInvalidColor = errors.New("invalid color")
+
+// Parse reads a CSS color: #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(), rgba(), hsl(), hsla(),
+// oklab(), oklch(), a CSS named color or transparent. Both the comma separated and the
+// space separated function syntax with a "/ alpha" part are accepted.
+func Parse(s string) (color.RGBA, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if text == "" {
+		return color.RGBA{}, fmt.Errorf("%w: empty string", ErrInvalidColor)
+	}
+
+	if hex, ok := strings.CutPrefix(text, "#"); ok {
+		c, err := parseHex(hex)
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("%w %q: %s", ErrInvalidColor, s, err)
+		}
+		return c, nil
+	}
+
+	if open := strings.IndexByte(text, '('); open > 0 {
+		if !strings.HasSuffix(text, ")") {
+			return color.RGBA{}, fmt.Errorf("%w %q: missing closing parenthesis", ErrInvalidColor, s)
+		}
+		c, err := parseFunction(strings.TrimSpace(text[:open]), text[open+1:len(text)-1])
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("%w %q: %s", ErrInvalidColor, s, err)
+		}
+		return c, nil
+	}
+
+	switch text {
+	case "transparent":
+		return color.RGBA{}, nil
+	case "rebeccapurple":
+		// Added in CSS Color 4, missing from the SVG names
+		return color.RGBA{102, 51, 153, 255}, nil
+	}
+	if c, ok := colornames.Map[text]; ok {
+		return c, nil
+	}
+	return color.RGBA{}, fmt.Errorf("%w %q: unknown color name", ErrInvalidColor, s)
+}
+
+// MustParse is like Parse but panics on invalid input, for colors written in code
+func MustParse(s string) color.RGBA {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseHex(hex string) (color.RGBA, error) {
+	digits := make([]uint8, len(hex))
+	for i := range len(hex) {
+		v, err := strconv.ParseUint(hex[i:i+1], 16, 8)
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("%q is not a hex digit", hex[i])
+		}
+		digits[i] = uint8(v)
+	}
+
+	var channels [4]uint8
+	channels[3] = 255
+	switch len(digits) {
+	case 3, 4:
+		for i, d := range digits {
+			channels[i] = d<<4 | d
+		}
+	case 6, 8:
+		for i := 0; i < len(digits); i += 2 {
+			channels[i/2] = digits[i]<<4 | digits[i+1]
+		}
+	default:
+		return color.RGBA{}, fmt.Errorf("expected 3, 4, 6 or 8 hex digits, got %d", len(digits))
+	}
+	return RGBA(color.NRGBA{R: channels[0], G: channels[1], B: channels[2], A: channels[3]}), nil
+}
+
+// parseFunction reads the arguments of a color function such as rgb(...)
+func parseFunction(name, body string) (color.RGBA, error) {
+	args, alpha, err := splitArguments(body)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	if len(args) != 3 {
+		return color.RGBA{}, fmt.Errorf("%s() takes 3 components and an optional alpha, got %d values", name, len(args)+len(alpha))
+	}
+
+	a := 1.0
+	if len(alpha) == 1 {
+		if a, err = number(alpha[0], 1); err != nil {
+			return color.RGBA{}, fmt.Errorf("alpha: %s", err)
+		}
+	}
+
+	values := make([]float64, 3)
+	switch name {
+	case "rgb", "rgba":
+		for i, arg := range args {
+			if values[i], err = number(arg, 255); err != nil {
+				return color.RGBA{}, err
+			}
+			values[i] /= 255
+		}
+		return premultiplied(values[0], values[1], values[2], a), nil
+	case "hsl", "hsla":
+		h, err := angle(args[0])
+		if err != nil {
+			return color.RGBA{}, err
+		}
+		for i, arg := range args[1:] {
+			if values[i], err = percentage(arg); err != nil {
+				return color.RGBA{}, err
+			}
+		}
+		return RGBA(HSL{H: h, S: values[0], L: values[1], A: clamp(a)}), nil
+	case "oklab":
+		for i, arg := range args {
+			// Lightness percentages run to 1, the axes to 0.4
+			if values[i], err = number(arg, []float64{1, 0.4, 0.4}[i]); err != nil {
+				return color.RGBA{}, err
+			}
+		}
+		return RGBA(OKLab{L: values[0], A: values[1], B: values[2], Alpha: clamp(a)}), nil
+	case "oklch":
+		for i, arg := range args[:2] {
+			if values[i], err = number(arg, []float64{1, 0.4}[i]); err != nil {
+				return color.RGBA{}, err
+			}
+		}
+		h, err := angle(args[2])
+		if err != nil {
+			return color.RGBA{}, err
+		}
+		return RGBA(OKLCH{L: values[0], C: values[1], H: h, Alpha: clamp(a)}), nil
+	default:
+		return color.RGBA{}, fmt.Errorf("unknown color function %s()", name)
+	}
+}
+
+// splitArguments splits function arguments written either as "a, b, c, alpha" or "a b c / alpha"
+func splitArguments(body string) (args, alpha []string, err error) {
+	if strings.Contains(body, ",") {
+		if strings.Contains(body, "/") {
+			return nil, nil, errors.New("commas and / can't be mixed")
+		}
+		for _, arg := range strings.Split(body, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+		if len(args) == 4 {
+			args, alpha = args[:3], args[3:]
+		}
+		return args, alpha, nil
+	}
+
+	components, rest, found := strings.Cut(body, "/")
+	args = strings.Fields(components)
+	if found {
+		alpha = strings.Fields(rest)
+		if len(alpha) != 1 {
+			return nil, nil, errors.New("expected a single alpha value after /")
+		}
+	}
+	return args, alpha, nil
+}
+
+// number reads a plain number, or a percentage of full
+func number(s string, full float64) (float64, error) {
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a percentage", s)
+		}
+		return v / 100 * full, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return v, nil
+}
+
+// percentage reads a saturation or lightness, with or without the % sign, as a fraction
+func percentage(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a percentage", s)
+	}
+	return clamp(v / 100), nil
+}
+
+// angle reads a hue in degrees, accepting the deg, rad, grad and turn units
+func angle(s string) (float64, error) {
+	units := []struct {
+		suffix  string
+		degrees float64
+	}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / math.Pi}, {"turn", 360}}
+	scale := 1.0
+	for _, unit := range units {
+		if v, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, scale = v, unit.degrees
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an angle", s)
+	}
+	return v * scale, nil
+}
@@ -0,0 +1,67 @@
+package colors
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  color.RGBA
+	}{
+		{"#f00", color.RGBA{255, 0, 0, 255}},
+		{"#0f08", color.RGBA{0, 136, 0, 136}},
+		{"#336699", color.RGBA{0x33, 0x66, 0x99, 255}},
+		{"#FFFFFF80", color.RGBA{128, 128, 128, 128}},
+		{"rgb(255, 128, 0)", color.RGBA{255, 128, 0, 255}},
+		{"rgba(0, 0, 255, 0.5)", color.RGBA{0, 0, 128, 128}},
+		{"rgb(100% 0% 0% / 50%)", color.RGBA{128, 0, 0, 128}},
+		{"hsl(120, 100%, 50%)", color.RGBA{0, 255, 0, 255}},
+		{"hsl(0.5turn 100% 25%)", color.RGBA{0, 128, 128, 255}},
+		{"hsla(240deg, 100%, 50%, 1)", color.RGBA{0, 0, 255, 255}},
+		{"oklab(1 0 0)", color.RGBA{255, 255, 255, 255}},
+		{"oklch(0% 0 0)", color.RGBA{0, 0, 0, 255}},
+		{"RebeccaPurple", color.RGBA{102, 51, 153, 255}},
+		{"  white ", color.RGBA{255, 255, 255, 255}},
+		{"transparent", color.RGBA{}},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Expected %q to parse, got %v", test.input, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Expected %q to be %v, got %v", test.input, test.want, got)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"", "#ff", "#ggg", "rgb(1, 2)", "rgb(1 2 3", "rgb(1, 2, 3 / 4)", "cmyk(1 2 3)", "notacolor", "hsl(red 1 2)"} {
+		if _, err := Parse(input); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("Expected %q to fail with ErrInvalidColor, got %v", input, err)
+		}
+	}
+}
+
+func TestMustParsePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustParse to panic on an invalid color")
+		}
+	}()
+	MustParse("nope")
+}
+
+func TestHex(t *testing.T) {
+	if got := Hex(color.RGBA{0x33, 0x66, 0x99, 255}); got != "#336699" {
+		t.Errorf("Expected #336699, got %s", got)
+	}
+	if got := Hex(color.RGBA{128, 0, 0, 128}); got != "#ff000080" {
+		t.Errorf("Expected #ff000080, got %s", got)
+	}
+}
@@ -1,8 +1,7 @@
 package main
 
 import (
-	"image/png"
-	"os"
+	"log"
 
 	cv "github.com/hvuhsg/render/canvas"
 	"github.com/hvuhsg/render/types"
@@ -19,7 +18,7 @@ func main() {
 	canvas.Line(3, 3, 796, 596, cv.Yellow, 5)            // Yellow diagonal line (inset to avoid out-of-bounds)
 
 	// Save the result
-	file, _ := os.Create("basic_shapes.png")
-	defer file.Close()
-	png.Encode(file, canvas.Img)
+	if err := canvas.SaveFile("basic_shapes.png", nil); err != nil {
+		log.Fatal(err)
+	}
 }
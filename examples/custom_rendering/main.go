@@ -1,8 +1,7 @@
 package main
 
 import (
-	"image/png"
-	"os"
+	"log"
 
 	cv "github.com/hvuhsg/render/canvas"
 	"github.com/hvuhsg/render/render_objects"
@@ -46,7 +45,7 @@ func main() {
 	painter.Paint(canvas)
 
 	// Save the result
-	file, _ := os.Create("custom_rendering.png")
-	defer file.Close()
-	png.Encode(file, canvas.Img)
+	if err := canvas.SaveFile("custom_rendering.png", nil); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -1,8 +1,7 @@
 package main
 
 import (
-	"image/png"
-	"os"
+	"log"
 
 	cv "github.com/hvuhsg/render/canvas"
 	"github.com/hvuhsg/render/render_objects"
@@ -47,7 +46,7 @@ func main() {
 	align.Paint(canvas)
 
 	// Save the result
-	file, _ := os.Create("layout_composition.png")
-	defer file.Close()
-	png.Encode(file, canvas.Img)
+	if err := canvas.SaveFile("layout_composition.png", nil); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/markup"
+	"github.com/hvuhsg/render/types"
+)
+
+func main() {
+	// Build the render tree described by the markup
+	root, err := markup.ParseFile("banner.html", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	canvas := cv.NewCanvas(types.Size{Width: 640, Height: 320}, false)
+	root.Paint(canvas)
+
+	if err := canvas.SaveFile("banner.png", nil); err != nil {
+		log.Fatal(err)
+	}
+}
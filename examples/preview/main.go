@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+	"os"
+	"syscall"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/preview"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/scene"
+	"github.com/hvuhsg/render/types"
+)
+
+func main() {
+	p := preview.New(&preview.Options{Title: "Render preview"})
+
+	// The greeting is built in Go, the card is read from its JSON file on every render
+	p.Register("greeting", func() render_objects.RenderObject {
+		return &render_objects.Align{
+			Child: render_objects.NewText("Hello, World!", cv.Purple, 36, "bold"),
+			Align: render_objects.AlignCenter,
+		}
+	}, types.Size{Width: 400, Height: 120}, types.Size{Width: 260, Height: 120})
+
+	p.Register("card", func() render_objects.RenderObject {
+		template, err := scene.LoadTemplate("../user_cards/card.json")
+		if err != nil {
+			panic(err)
+		}
+		data, err := os.ReadFile("../user_cards/users.json")
+		if err != nil {
+			panic(err)
+		}
+		records, err := scene.DecodeRecords(data)
+		if err != nil {
+			panic(err)
+		}
+		root, err := template.Build(records[0])
+		if err != nil {
+			panic(err)
+		}
+		return root
+	}, types.Size{Width: 400, Height: 180})
+
+	// Edit card.json, then refresh every open page with: kill -HUP <pid>
+	p.ChangeOnSignal(syscall.SIGHUP)
+	log.Fatal(p.ListenAndServe("localhost:8090"))
+}
@@ -1,8 +1,7 @@
 package main
 
 import (
-	"image/png"
-	"os"
+	"log"
 
 	cv "github.com/hvuhsg/render/canvas"
 	"github.com/hvuhsg/render/render_objects"
@@ -37,7 +36,7 @@ func main() {
 	align.Paint(canvas)
 
 	// Save the result
-	file, _ := os.Create("text_rendering.png")
-	defer file.Close()
-	png.Encode(file, canvas.Img)
+	if err := canvas.SaveFile("text_rendering.png", nil); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/scene"
+	"github.com/hvuhsg/render/types"
+)
+
+func main() {
+	// Parse the card template once
+	template, err := scene.LoadTemplate("card.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read one record per user
+	data, err := os.ReadFile("users.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	records, err := scene.DecodeRecords(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Render a card for each user
+	for i, record := range records {
+		root, err := template.Build(record)
+		if err != nil {
+			log.Fatalf("record %d: %v", i+1, err)
+		}
+
+		canvas := cv.NewCanvas(types.Size{Width: 400, Height: 180}, false)
+		root.Paint(canvas)
+
+		id := record.(map[string]any)["user"].(map[string]any)["id"]
+		if err := canvas.SaveFile(fmt.Sprintf("card_%s.png", id), nil); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package inspect
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"io"
+
+	"github.com/hvuhsg/render/render_objects"
+)
+
+// HitTest returns the path of objects under point, from the root to the deepest object
+// that contains it. Where siblings overlap the one painted last, on top, is chosen.
+// Children of a FittedBox are hit where they were scaled to, but bounds are taken before
+// any Transform, so rotated or skewed children are hit at their untransformed place.
+// It returns nil when the root doesn't contain point.
+func HitTest(root *Node, point image.Point) []*Node {
+	if root == nil || !point.In(area(root)) {
+		return nil
+	}
+	path := []*Node{root}
+	for node := root; ; {
+		var hit *Node
+		for i := len(node.Children) - 1; i >= 0; i-- {
+			if point.In(area(node.Children[i])) {
+				hit = node.Children[i]
+				break
+			}
+		}
+		if hit == nil {
+			return path
+		}
+		path = append(path, hit)
+		node = hit
+	}
+}
+
+// Region is the area painted by a render_objects.Semantic, in logical pixels
+type Region struct {
+	ID     string `json:"id"`
+	Link   string `json:"link,omitempty"`
+	Label  string `json:"label,omitempty"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Regions returns the semantic regions of a traced tree in paint order,
+// clipped to the root. Regions left empty by the clipping are dropped.
+func Regions(root *Node) []Region {
+	if root == nil {
+		return nil
+	}
+	var regions []Region
+	root.Walk(func(node *Node) {
+		semantic, ok := node.Object.(*render_objects.Semantic)
+		if !ok {
+			return
+		}
+		b := area(node).Intersect(root.Bounds)
+		if b.Empty() {
+			return
+		}
+		regions = append(regions, Region{
+			ID:     semantic.ID,
+			Link:   semantic.Link,
+			Label:  semantic.Label,
+			X:      b.Min.X,
+			Y:      b.Min.Y,
+			Width:  b.Dx(),
+			Height: b.Dy(),
+		})
+	})
+	return regions
+}
+
+// area returns the part of the image a node covers: its bounds, except for a Semantic,
+// which covers the area its child was painted on rather than all the space it was offered
+func area(node *Node) image.Rectangle {
+	if _, ok := node.Object.(*render_objects.Semantic); ok && len(node.Children) > 0 {
+		return node.Children[0].Bounds
+	}
+	return node.Bounds
+}
+
+// WriteImageMap writes regions as an HTML <map> with one rectangular <area> per region.
+// Areas are listed topmost first, as browsers pick the first area that contains a click.
+func WriteImageMap(w io.Writer, name string, regions []Region) error {
+	if _, err := fmt.Fprintf(w, "<map name=\"%s\">\n", html.EscapeString(name)); err != nil {
+		return err
+	}
+	for i := len(regions) - 1; i >= 0; i-- {
+		r := regions[i]
+		alt := r.Label
+		if alt == "" {
+			alt = r.ID
+		}
+		area := fmt.Sprintf("  <area shape=\"rect\" coords=\"%d,%d,%d,%d\"", r.X, r.Y, r.X+r.Width, r.Y+r.Height)
+		if r.ID != "" {
+			area += fmt.Sprintf(" id=\"%s\"", html.EscapeString(r.ID))
+		}
+		if r.Link != "" {
+			area += fmt.Sprintf(" href=\"%s\"", html.EscapeString(r.Link))
+		}
+		area += fmt.Sprintf(" alt=\"%s\">\n", html.EscapeString(alt))
+		if _, err := io.WriteString(w, area); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</map>\n")
+	return err
+}
@@ -0,0 +1,132 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestHitTest(t *testing.T) {
+	first, second := box(20, 10), box(30, 10)
+	row := &render_objects.Row{
+		Alignment: types.MainAxisAlignmentSpaceBetween,
+		Sizing:    types.MainAxisSizeMax,
+		Children:  []render_objects.RenderObject{first, second},
+	}
+	root := render_objects.NewPadding(row, 5)
+	node := Trace(root, cv.NewCanvas(types.Size{Width: 110, Height: 40}, false))
+
+	path := HitTest(node, image.Pt(80, 8))
+	if len(path) != 3 || path[0].Object != root || path[1].Object != row || path[2].Object != second {
+		t.Errorf("Expected the path padding, row, second box, got %v", pathTypes(path))
+	}
+
+	// Between the boxes only the row is hit
+	if path := HitTest(node, image.Pt(50, 8)); len(path) != 2 || path[1].Object != row {
+		t.Errorf("Expected the path to end at the row, got %v", pathTypes(path))
+	}
+	if path := HitTest(node, image.Pt(200, 8)); path != nil {
+		t.Errorf("Expected no path outside the root, got %v", pathTypes(path))
+	}
+}
+
+func TestHitTestTopmost(t *testing.T) {
+	below, above := box(40, 40), box(20, 20)
+	stack := &render_objects.Stack{Children: []render_objects.RenderObject{below, above}}
+	node := Trace(stack, cv.NewCanvas(types.Size{Width: 40, Height: 40}, false))
+
+	if path := HitTest(node, image.Pt(10, 10)); len(path) != 2 || path[1].Object != above {
+		t.Errorf("Expected the child painted last to be hit, got %v", pathTypes(path))
+	}
+	if path := HitTest(node, image.Pt(30, 30)); len(path) != 2 || path[1].Object != below {
+		t.Errorf("Expected the child below to be hit outside the one above, got %v", pathTypes(path))
+	}
+}
+
+func semanticBanner() *Node {
+	root := &render_objects.Column{Children: []render_objects.RenderObject{
+		&render_objects.Semantic{Child: box(100, 20), ID: "header", Label: "Summer sale"},
+		render_objects.NewPaddingWithSides(&render_objects.Semantic{
+			Child: box(30, 10),
+			ID:    "cta",
+			Link:  "https://example.com/?a=1&b=2",
+		}, 0, 0, 0, 70),
+	}}
+	return Trace(root, cv.NewCanvas(types.Size{Width: 100, Height: 40}, false))
+}
+
+func TestRegions(t *testing.T) {
+	regions := Regions(semanticBanner())
+	expected := []Region{
+		{ID: "header", Label: "Summer sale", X: 0, Y: 0, Width: 100, Height: 20},
+		{ID: "cta", Link: "https://example.com/?a=1&b=2", X: 70, Y: 20, Width: 30, Height: 10},
+	}
+	if len(regions) != len(expected) {
+		t.Fatalf("Expected %d regions, got %+v", len(expected), regions)
+	}
+	for i := range expected {
+		if regions[i] != expected[i] {
+			t.Errorf("Expected region %d to be %+v, got %+v", i, expected[i], regions[i])
+		}
+	}
+
+	data, err := json.Marshal(regions[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `{"id":"cta","link":"https://example.com/?a=1\u0026b=2","x":70,"y":20,"width":30,"height":10}` {
+		t.Errorf("Expected the region as JSON, got %s", got)
+	}
+}
+
+func TestRegionsCoverTheChild(t *testing.T) {
+	// Padding offers the region all the space inside it, the child only takes 30x10
+	padded := render_objects.NewPadding(&render_objects.Semantic{ID: "cta", Child: box(30, 10)}, 10)
+	node := Trace(padded, cv.NewCanvas(types.Size{Width: 200, Height: 100}, false))
+	if regions := Regions(node); len(regions) != 1 || regions[0] != (Region{ID: "cta", X: 10, Y: 10, Width: 30, Height: 10}) {
+		t.Errorf("Expected the region to cover the child at (10,10) size 30x10, got %+v", regions)
+	}
+	if path := HitTest(node, image.Pt(100, 50)); len(path) != 1 {
+		t.Errorf("Expected the region not to be hit beside its child, got %v", pathTypes(path))
+	}
+
+	// A fitted child is scaled from 200x100 to 100x50 and centered
+	fitted := &render_objects.FittedBox{Child: &render_objects.Semantic{ID: "logo", Child: box(200, 100)}}
+	node = Trace(fitted, cv.NewCanvas(types.Size{Width: 100, Height: 100}, false))
+	if regions := Regions(node); len(regions) != 1 || regions[0] != (Region{ID: "logo", X: 0, Y: 25, Width: 100, Height: 50}) {
+		t.Errorf("Expected the region scaled with the child to (0,25) size 100x50, got %+v", regions)
+	}
+	if path := HitTest(node, image.Pt(50, 80)); len(path) != 1 {
+		t.Errorf("Expected nothing under the fitted box to be hit below the scaled child, got %v", pathTypes(path))
+	}
+}
+
+func TestWriteImageMap(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteImageMap(&b, "banner", Regions(semanticBanner())); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<map name="banner">
+  <area shape="rect" coords="70,20,100,30" id="cta" href="https://example.com/?a=1&amp;b=2" alt="cta">
+  <area shape="rect" coords="0,0,100,20" id="header" alt="Summer sale">
+</map>
+`
+	if b.String() != expected {
+		t.Errorf("Expected the image map\n%s\ngot\n%s", expected, b.String())
+	}
+}
+
+// pathTypes names the objects of a path, for error messages
+func pathTypes(path []*Node) []string {
+	names := make([]string, len(path))
+	for i, node := range path {
+		names[i] = node.Type()
+	}
+	return names
+}
@@ -0,0 +1,158 @@
+// Package inspect shows how a render tree was laid out, for debugging layouts.
+//
+// Trace paints a tree and records where every object of it was painted, and Overlay
+// draws those records on top of the painted image:
+//
+//	canvas := cv.NewCanvas(size, false)
+//	(&inspect.Overlay{Child: root, Labels: true}).Paint(canvas)
+//
+// Inspect describes the same records with the key properties of each object, to print
+// as an indented text tree or as JSON:
+//
+//	fmt.Print(inspect.Inspect(root, size))
+//
+// HitTest finds the objects under a point of a traced tree, and Regions lists the areas
+// named with render_objects.Semantic, to write as JSON or as an HTML image map:
+//
+//	inspect.WriteImageMap(w, "banner", inspect.Regions(inspect.Trace(root, canvas)))
+//
+// Objects are only seen when their parent paints them through render_objects.PaintChild,
+// as every render object of this module does.
+package inspect
+
+import (
+	"image"
+	"math"
+	"reflect"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Node is a render object as it was painted
+type Node struct {
+	Object      render_objects.RenderObject
+	Bounds      image.Rectangle   // Area the object was given to paint on, relative to the root canvas
+	Constraints types.Size        // Space the parent offered: its size less its margin and padding
+	Overflow    []image.Rectangle // Parts of the object that didn't fit in its bounds or in its parent
+	Children    []*Node
+
+	scaleX, scaleY float64 // Size of Bounds relative to the canvas the object painted on
+}
+
+// Type returns the name of the type of the object, without package and pointer
+func (n *Node) Type() string {
+	t := reflect.TypeOf(n.Object)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Guides returns the layout guides of the object at the size it was painted at
+func (n *Node) Guides() render_objects.Guides {
+	return render_objects.GuidesOf(n.Object, types.Size{Width: n.Bounds.Dx(), Height: n.Bounds.Dy()})
+}
+
+// Walk calls visit for the node and all of its descendants, parents before their children
+func (n *Node) Walk(visit func(node *Node)) {
+	visit(n)
+	for _, child := range n.Children {
+		child.Walk(visit)
+	}
+}
+
+// Trace paints root on canvas and returns the tree of the objects that were painted.
+// A tracer already set on the canvas keeps seeing the painting.
+func Trace(root render_objects.RenderObject, canvas *cv.Canvas) *Node {
+	t := &tracer{next: canvas.Tracer(), origin: canvas.Bounds().Min, rootSize: canvas.Size}
+	canvas.SetTracer(t)
+	defer canvas.SetTracer(t.next)
+
+	render_objects.PaintChild(root, canvas)
+	return t.root
+}
+
+// tracer builds the Node tree from the paint calls of a canvas
+type tracer struct {
+	next     cv.Tracer
+	origin   image.Point
+	rootSize types.Size
+	stack    []*Node
+	root     *Node
+}
+
+func (t *tracer) BeginPaint(object any, canvas *cv.Canvas) {
+	if t.next != nil {
+		t.next.BeginPaint(object, canvas)
+	}
+	obj, ok := object.(render_objects.RenderObject)
+	if !ok {
+		return
+	}
+
+	node := &Node{Object: obj, Bounds: canvas.Bounds().Sub(t.origin), Constraints: t.rootSize}
+	node.scaleX, node.scaleY = canvas.TraceScale()
+	content := image.Rectangle{Max: image.Pt(t.rootSize.Width, t.rootSize.Height)}
+	measure := t.rootSize
+	if len(t.stack) > 0 {
+		parent := t.stack[len(t.stack)-1]
+		guides := parent.Guides()
+		content = inset(inset(parent.Bounds, guides.Margin), guides.Padding)
+		node.Constraints = types.Size{Width: content.Dx(), Height: content.Dy()}
+		// The parent measured the object on its own canvas, before any scaling of it
+		measure = types.Size{
+			Width:  int(math.Round(float64(content.Dx()) / parent.scaleX)),
+			Height: int(math.Round(float64(content.Dy()) / parent.scaleY)),
+		}
+		parent.Children = append(parent.Children, node)
+	} else {
+		t.root = node
+	}
+	node.Overflow = overflow(node, measure, content)
+	t.stack = append(t.stack, node)
+}
+
+func (t *tracer) EndPaint(object any, canvas *cv.Canvas) {
+	if _, ok := object.(render_objects.RenderObject); ok {
+		t.stack = t.stack[:len(t.stack)-1]
+	}
+	if t.next != nil {
+		t.next.EndPaint(object, canvas)
+	}
+}
+
+// overflow returns the parts of the object that don't fit: beyond its bounds when it
+// wants to be larger than them at the size it was measured against, and beyond the
+// content area of its parent
+func overflow(node *Node, measure types.Size, content image.Rectangle) []image.Rectangle {
+	want := node.Object.Size(measure)
+	scaled := image.Pt(int(math.Round(float64(want.Width)*node.scaleX)), int(math.Round(float64(want.Height)*node.scaleY)))
+	extent := node.Bounds.Union(image.Rectangle{Min: node.Bounds.Min, Max: node.Bounds.Min.Add(scaled)})
+	return subtract(extent, node.Bounds.Intersect(content))
+}
+
+// subtract returns up to four rectangles covering the parts of r outside of keep
+func subtract(r, keep image.Rectangle) []image.Rectangle {
+	if r.Empty() {
+		return nil
+	}
+	keep = keep.Intersect(r)
+	if keep.Empty() {
+		return []image.Rectangle{r}
+	}
+
+	var parts []image.Rectangle
+	for _, part := range []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, keep.Min.Y),       // Above
+		image.Rect(r.Min.X, keep.Max.Y, r.Max.X, r.Max.Y),       // Below
+		image.Rect(r.Min.X, keep.Min.Y, keep.Min.X, keep.Max.Y), // Left
+		image.Rect(keep.Max.X, keep.Min.Y, r.Max.X, keep.Max.Y), // Right
+	} {
+		if !part.Empty() {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
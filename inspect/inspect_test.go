@@ -0,0 +1,133 @@
+package inspect
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func box(width, height int) *render_objects.ColoredBox {
+	return &render_objects.ColoredBox{Width: width, Height: height, Color: color.RGBA{255, 0, 0, 255}}
+}
+
+func TestTrace(t *testing.T) {
+	first, second := box(20, 10), box(30, 10)
+	row := &render_objects.Row{
+		Alignment: types.MainAxisAlignmentSpaceBetween,
+		Sizing:    types.MainAxisSizeMax,
+		Children:  []render_objects.RenderObject{first, second},
+	}
+	root := render_objects.NewPadding(row, 5)
+
+	canvas := cv.NewCanvas(types.Size{Width: 110, Height: 40}, false)
+	node := Trace(root, canvas)
+
+	if node.Type() != "Padding" || node.Bounds != image.Rect(0, 0, 110, 40) {
+		t.Errorf("Expected the padding to cover the canvas, got %s at %v", node.Type(), node.Bounds)
+	}
+	if len(node.Children) != 1 || len(node.Children[0].Children) != 2 {
+		t.Fatalf("Expected a row of two boxes")
+	}
+
+	rowNode := node.Children[0]
+	if rowNode.Constraints != (types.Size{Width: 100, Height: 30}) {
+		t.Errorf("Expected the row to be constrained to the padded area, got %v", rowNode.Constraints)
+	}
+	// Space between pushes the second box to the right edge of the row
+	if got := rowNode.Children[1].Bounds; got != image.Rect(75, 5, 105, 15) {
+		t.Errorf("Expected the second box at (75,5)-(105,15), got %v", got)
+	}
+	if canvas.Tracer() != nil {
+		t.Errorf("Expected the canvas tracer to be restored")
+	}
+}
+
+func TestTraceOverflow(t *testing.T) {
+	width := 40
+	row := &render_objects.Row{Children: []render_objects.RenderObject{box(30, 10), box(30, 10)}}
+	sized := &render_objects.SizedBox{Width: &width, Child: row}
+	root := &render_objects.Align{Align: render_objects.AlignTopLeft, Child: sized}
+
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 20}, true)
+	node := Trace(root, canvas).Children[0]
+
+	rowNode := node.Children[0]
+	if len(rowNode.Overflow) != 1 || rowNode.Overflow[0] != image.Rect(40, 0, 60, 10) {
+		t.Errorf("Expected the row to overflow the sized box by 20 pixels, got %v", rowNode.Overflow)
+	}
+	if len(node.Overflow) != 0 {
+		t.Errorf("Expected no overflow of the sized box, got %v", node.Overflow)
+	}
+}
+
+func TestTraceScaledOverflow(t *testing.T) {
+	// A 400x100 box fits a 100x25 fitted box at a quarter of its size
+	fitted := &render_objects.FittedBox{Child: box(400, 100)}
+	size := types.Size{Width: 100, Height: 25}
+	node := Trace(fitted, cv.NewCanvas(size, false))
+	if len(node.Children) != 1 || len(node.Children[0].Overflow) != 0 {
+		t.Errorf("Expected the scaled box to fit, got %v", node.Children[0].Overflow)
+	}
+	if layout := Inspect(fitted, size); layout.Children[0].Overflow {
+		t.Errorf("Expected the layout of the scaled box not to overflow")
+	}
+
+	// A 300 pixel row in a 200 pixel box still overflows by 100 pixels, a quarter of them shown
+	width := 200
+	row := &render_objects.Row{Children: []render_objects.RenderObject{box(150, 100), box(150, 100)}}
+	fitted = &render_objects.FittedBox{Child: &render_objects.SizedBox{Width: &width, Child: row}}
+	node = Trace(fitted, cv.NewCanvas(types.Size{Width: 50, Height: 25}, false))
+	rowNode := node.Children[0].Children[0]
+	if len(rowNode.Overflow) != 1 || rowNode.Overflow[0] != image.Rect(50, 0, 75, 25) {
+		t.Errorf("Expected the row to overflow by 25 scaled pixels, got %v", rowNode.Overflow)
+	}
+}
+
+func TestTraceLayers(t *testing.T) {
+	inner := box(10, 10)
+	root := &render_objects.Align{
+		Align: render_objects.AlignBottomRight,
+		Child: &render_objects.Opacity{Opacity: 0.5, Child: render_objects.NewRotation(inner, 45)},
+	}
+
+	canvas := cv.NewScaledCanvas(types.Size{Width: 50, Height: 50}, 2, false)
+	node := Trace(root, canvas)
+
+	var found *Node
+	node.Walk(func(n *Node) {
+		if n.Object == inner {
+			found = n
+		}
+	})
+	if found == nil {
+		t.Fatalf("Expected objects painted on layers to be traced")
+	}
+	if found.Bounds != image.Rect(40, 40, 50, 50) {
+		t.Errorf("Expected the box at its untransformed position (40,40)-(50,50), got %v", found.Bounds)
+	}
+}
+
+func TestTraceChainsTracers(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 20, Height: 20}, false)
+	outer := &countingTracer{}
+	canvas.SetTracer(outer)
+
+	Trace(&render_objects.Align{Child: box(5, 5), Align: render_objects.AlignCenter}, canvas)
+	if outer.begun != 2 || outer.ended != 2 {
+		t.Errorf("Expected the existing tracer to see both objects, got %d begun and %d ended", outer.begun, outer.ended)
+	}
+	if canvas.Tracer() != outer {
+		t.Errorf("Expected the existing tracer to be restored")
+	}
+}
+
+type countingTracer struct {
+	begun, ended int
+}
+
+func (c *countingTracer) BeginPaint(object any, canvas *cv.Canvas) { c.begun++ }
+func (c *countingTracer) EndPaint(object any, canvas *cv.Canvas)   { c.ended++ }
@@ -0,0 +1,188 @@
+package inspect
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"slices"
+	"strings"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Layout describes a render object as it was laid out, for printing as an indented
+// text tree with String or as JSON
+type Layout struct {
+	Type         string         `json:"type"`
+	X            int            `json:"x"` // Offset from the top left corner of the parent
+	Y            int            `json:"y"`
+	Width        int            `json:"width"` // Size the object computed for itself
+	Height       int            `json:"height"`
+	BoundsWidth  int            `json:"boundsWidth"` // Size of the area it was given to paint on
+	BoundsHeight int            `json:"boundsHeight"`
+	MaxWidth     int            `json:"maxWidth"` // Constraints, the space the parent offered
+	MaxHeight    int            `json:"maxHeight"`
+	Overflow     bool           `json:"overflow,omitempty"`
+	Properties   map[string]any `json:"properties,omitempty"`
+	Children     []*Layout      `json:"children,omitempty"`
+}
+
+// Inspect lays out and paints root at size and describes the resulting tree
+func Inspect(root render_objects.RenderObject, size types.Size) *Layout {
+	return Describe(Trace(root, cv.NewCanvas(size, true)))
+}
+
+// Describe turns a traced tree into its description
+func Describe(node *Node) *Layout {
+	return describe(node, image.Point{})
+}
+
+func describe(node *Node, parent image.Point) *Layout {
+	size := node.Object.Size(node.Constraints)
+	layout := &Layout{
+		Type:         node.Type(),
+		X:            node.Bounds.Min.X - parent.X,
+		Y:            node.Bounds.Min.Y - parent.Y,
+		Width:        size.Width,
+		Height:       size.Height,
+		BoundsWidth:  node.Bounds.Dx(),
+		BoundsHeight: node.Bounds.Dy(),
+		MaxWidth:     node.Constraints.Width,
+		MaxHeight:    node.Constraints.Height,
+		Overflow:     len(node.Overflow) > 0,
+		Properties:   properties(node.Object),
+	}
+	for _, child := range node.Children {
+		layout.Children = append(layout.Children, describe(child, node.Bounds.Min))
+	}
+	return layout
+}
+
+// String prints the tree one object per line, children indented below their parent.
+// The area an object was given follows its size when the two differ:
+//
+//	Container at 0,0 size 300x64 in 300x100 max 300x100 Margin=10 Padding=12
+//	  Row at 22,22 size 256x20 max 256x56 Alignment=spaceBetween Sizing=max
+//	    Text at 0,0 size 42x20 max 256x20 Color=#000000 FontSize=16 Text=Hello
+func (l *Layout) String() string {
+	var b strings.Builder
+	l.write(&b, 0)
+	return b.String()
+}
+
+func (l *Layout) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%s at %d,%d size %dx%d", strings.Repeat("  ", depth), l.Type, l.X, l.Y, l.Width, l.Height)
+	if l.BoundsWidth != l.Width || l.BoundsHeight != l.Height {
+		fmt.Fprintf(b, " in %dx%d", l.BoundsWidth, l.BoundsHeight)
+	}
+	fmt.Fprintf(b, " max %dx%d", l.MaxWidth, l.MaxHeight)
+	if l.Overflow {
+		b.WriteString(" OVERFLOW")
+	}
+
+	names := make([]string, 0, len(l.Properties))
+	for name := range l.Properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		value := l.Properties[name]
+		// Quote strings that wouldn't read as one value
+		if s, ok := value.(string); ok && (s == "" || strings.ContainsAny(s, " \t\n\"=")) {
+			value = fmt.Sprintf("%q", s)
+		}
+		fmt.Fprintf(b, " %s=%v", name, value)
+	}
+	b.WriteByte('\n')
+
+	for _, child := range l.Children {
+		child.write(b, depth+1)
+	}
+}
+
+// properties returns the key properties of an object: the ones it describes itself,
+// or otherwise its exported fields that are set, other than its children.
+// Nested structs and slices are flattened into dotted and indexed names.
+func properties(obj render_objects.RenderObject) map[string]any {
+	props := map[string]any{}
+	if describer, ok := obj.(render_objects.Describer); ok {
+		for name, value := range describer.Describe() {
+			addProperty(props, name, reflect.ValueOf(value))
+		}
+	} else {
+		v := reflect.ValueOf(obj)
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Struct {
+			addFields(props, "", v)
+		}
+	}
+
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+func addFields(props map[string]any, prefix string, v reflect.Value) {
+	for i := range v.NumField() {
+		if field := v.Type().Field(i); field.IsExported() {
+			addProperty(props, prefix+field.Name, v.Field(i))
+		}
+	}
+}
+
+// addProperty adds a value unless it is zero or transparent, which stand for the default
+func addProperty(props map[string]any, name string, v reflect.Value) {
+	if !v.IsValid() {
+		return
+	}
+	if c, ok := v.Interface().(color.Color); ok && v.Kind() != reflect.Pointer {
+		if _, _, _, a := c.RGBA(); a > 0 {
+			props[name] = colors.Hex(c)
+		}
+		return
+	}
+	if v.IsZero() {
+		return
+	}
+
+	switch value := v.Interface().(type) {
+	case render_objects.RenderObject:
+		// Children are described on their own
+		return
+	case image.Image:
+		props[name] = fmt.Sprintf("image %dx%d", value.Bounds().Dx(), value.Bounds().Dy())
+		return
+	case fmt.Stringer:
+		props[name] = value.String()
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		addProperty(props, name, v.Elem())
+	case reflect.Struct:
+		addFields(props, name+".", v)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			addProperty(props, fmt.Sprintf("%s[%d]", name, i), v.Index(i))
+		}
+	case reflect.Bool:
+		props[name] = v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		props[name] = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		props[name] = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		props[name] = v.Float()
+	case reflect.String:
+		props[name] = v.String()
+	}
+	// Functions, maps and channels aren't described
+}
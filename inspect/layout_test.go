@@ -0,0 +1,112 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestInspect(t *testing.T) {
+	root := &render_objects.Container{
+		Padding: types.EdgeInsetsAll(10),
+		Child: &render_objects.Row{
+			Alignment: types.MainAxisAlignmentEnd,
+			Sizing:    types.MainAxisSizeMax,
+			Children:  []render_objects.RenderObject{box(20, 10), render_objects.NewText("Hi there", color.Black, 12, "")},
+		},
+	}
+
+	layout := Inspect(root, types.Size{Width: 200, Height: 50})
+	lines := strings.Split(strings.TrimSuffix(layout.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a line per object, got %q", lines)
+	}
+
+	tests := []string{
+		"Container at 0,0 size 200x36 in 200x50 max 200x50 Padding=10",
+		"  Row at 10,10 size 180x16 max 180x30 Alignment=end Sizing=max",
+		"    ColoredBox at ",
+		`    Text at `,
+	}
+	for i, want := range tests {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("Expected line %d to start with %q, got %q", i, want, lines[i])
+		}
+	}
+	if !strings.Contains(lines[3], `Text="Hi there"`) || !strings.Contains(lines[3], "Color=#000000") {
+		t.Errorf("Expected the text properties, got %q", lines[3])
+	}
+
+	// Offsets are relative to the parent, so the end aligned children sit at the right of the row
+	text := layout.Children[0].Children[1]
+	if text.X+text.Width != 180 || text.Y != 0 {
+		t.Errorf("Expected the text to end at the right edge of the row, got x %d and width %d", text.X, text.Width)
+	}
+}
+
+func TestInspectSize(t *testing.T) {
+	text := render_objects.NewText("Hi", color.Black, 16, "")
+	layout := Inspect(render_objects.NewPadding(text, 10), types.Size{Width: 300, Height: 100}).Children[0]
+
+	// The text is given all the space inside the padding but only needs a little of it
+	size := text.Size(types.Size{Width: 280, Height: 80})
+	if layout.Width != size.Width || layout.Height != size.Height || layout.BoundsWidth != 280 || layout.BoundsHeight != 80 {
+		t.Errorf("Expected size %v in 280x80, got %dx%d in %dx%d", size, layout.Width, layout.Height, layout.BoundsWidth, layout.BoundsHeight)
+	}
+	if want := fmt.Sprintf("Text at 10,10 size %dx%d in 280x80 max 280x80 ", size.Width, size.Height); !strings.HasPrefix(layout.String(), want) {
+		t.Errorf("Expected the layout to start with %q, got %q", want, layout.String())
+	}
+}
+
+func TestInspectJSON(t *testing.T) {
+	width := 10
+	sized := &render_objects.SizedBox{Width: &width, Child: &render_objects.Row{Children: []render_objects.RenderObject{box(30, 10)}}}
+	root := &render_objects.Align{Child: sized}
+
+	data, err := json.Marshal(Inspect(root, types.Size{Width: 100, Height: 20}))
+	if err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+
+	var decoded Layout
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected the JSON to decode, got %v", err)
+	}
+	row := decoded.Children[0].Children[0]
+	if row.Type != "Row" || !row.Overflow || row.MaxWidth != 10 {
+		t.Errorf("Expected an overflowing row constrained to 10 pixels, got %s", data)
+	}
+	if !strings.Contains(string(data), `"properties":{"Width":10}`) {
+		t.Errorf("Expected the sized box width as its only property, got %s", data)
+	}
+}
+
+func TestProperties(t *testing.T) {
+	props := properties(&render_objects.Container{
+		Child:      box(1, 1),
+		Decoration: render_objects.BoxDecoration{Shadows: []cv.BoxShadow{{Color: color.RGBA{0, 0, 0, 128}, Blur: 4}}},
+		Margin:     types.EdgeInsets{Top: 1, Right: 2, Bottom: 3, Left: 4},
+		Alignment:  render_objects.AlignCenter,
+	})
+
+	want := map[string]any{
+		"Decoration.Shadows[0].Color": "#00000080",
+		"Decoration.Shadows[0].Blur":  int64(4),
+		"Margin":                      "1 2 3 4",
+		"Alignment":                   "center",
+	}
+	if len(props) != len(want) {
+		t.Errorf("Expected %d properties without the child and unset fields, got %v", len(want), props)
+	}
+	for name, value := range want {
+		if props[name] != value {
+			t.Errorf("Expected %s to be %v, got %v", name, value, props[name])
+		}
+	}
+}
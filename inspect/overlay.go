@@ -0,0 +1,123 @@
+package inspect
+
+import (
+	"image"
+	"image/color"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Colors of the overlay, translucent ones premultiplied
+var (
+	boundsColor     = color.RGBA{R: 0, G: 150, B: 220, A: 255}
+	marginColor     = color.RGBA{R: 77, G: 46, B: 0, A: 77}
+	paddingColor    = color.RGBA{R: 10, G: 45, B: 73, A: 77}
+	anchorColor     = color.RGBA{R: 233, G: 30, B: 99, A: 255}
+	baselineColor   = color.RGBA{R: 67, G: 160, B: 71, A: 255}
+	overflowColor   = color.RGBA{R: 115, G: 29, B: 27, A: 128}
+	labelColor      = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	labelBackground = color.RGBA{R: 0, G: 0, B: 0, A: 170}
+)
+
+// labelFontSize is the size of the type labels
+const labelFontSize = 9
+
+// Overlay paints its child, then draws how the child's tree was laid out on top of it:
+// the bounds of every object in blue, margins in orange, padding and borders in light blue,
+// alignment anchors as pink crosses, text baselines in green and overflow in red
+type Overlay struct {
+	Child  render_objects.RenderObject
+	Labels bool // Name the type of every object at its top left corner
+}
+
+func (o *Overlay) Paint(canvas *cv.Canvas) {
+	root := Trace(o.Child, canvas)
+	if root == nil {
+		return
+	}
+
+	layer := cv.NewScaledLayer(canvas.Size, canvas.PixelRatio())
+	drawOverlay(layer.Canvas, root, o.Labels)
+	canvas.DrawLayer(layer, 0, 0)
+}
+
+func (o *Overlay) Size(parentSize types.Size) types.Size {
+	return o.Child.Size(parentSize)
+}
+
+// drawOverlay draws the layout of the traced tree, areas first so lines stay visible
+func drawOverlay(canvas *cv.Canvas, root *Node, labels bool) {
+	root.Walk(func(node *Node) {
+		guides := node.Guides()
+		box := inset(node.Bounds, guides.Margin)
+		fillAll(canvas, subtract(node.Bounds, box), marginColor)
+		fillAll(canvas, subtract(box, inset(box, guides.Padding)), paddingColor)
+	})
+
+	root.Walk(func(node *Node) {
+		b := node.Bounds
+		if !b.Empty() {
+			canvas.Rectangle(b.Min.X, b.Min.Y, b.Dx(), b.Dy(), boundsColor, false)
+		}
+
+		guides := node.Guides()
+		for _, baseline := range guides.Baselines {
+			canvas.Rectangle(b.Min.X, b.Min.Y+baseline, b.Dx(), 1, baselineColor, true)
+		}
+		for _, anchor := range guides.Anchors {
+			p := b.Min.Add(anchor)
+			canvas.Rectangle(p.X-3, p.Y, 7, 1, anchorColor, true)
+			canvas.Rectangle(p.X, p.Y-3, 1, 7, anchorColor, true)
+		}
+	})
+
+	root.Walk(func(node *Node) {
+		fillAll(canvas, node.Overflow, overflowColor)
+	})
+
+	if labels {
+		drawLabels(canvas, root)
+	}
+}
+
+// drawLabels names every object at its top left corner, stacking the labels
+// of objects that start at the same point
+func drawLabels(canvas *cv.Canvas, root *Node) {
+	painter := cv.NewTextPainter()
+	painter.FontSize = labelFontSize
+	painter.TextColor = labelColor
+
+	stacked := map[image.Point]int{}
+	root.Walk(func(node *Node) {
+		if node.Bounds.Empty() {
+			return
+		}
+		name := node.Type()
+		size := canvas.MeasureText(name, painter)
+		x, y := node.Bounds.Min.X, node.Bounds.Min.Y+stacked[node.Bounds.Min]*size.Height
+		stacked[node.Bounds.Min]++
+
+		canvas.Rectangle(x, y, size.Width, size.Height, labelBackground, true)
+		canvas.DrawText(name, x+2, y+1, painter)
+	})
+}
+
+// inset shrinks r by insets on each side
+func inset(r image.Rectangle, insets types.EdgeInsets) image.Rectangle {
+	inner := image.Rectangle{
+		Min: image.Pt(r.Min.X+insets.Left, r.Min.Y+insets.Top),
+		Max: image.Pt(r.Max.X-insets.Right, r.Max.Y-insets.Bottom),
+	}
+	if inner.Min.X > inner.Max.X || inner.Min.Y > inner.Max.Y {
+		return image.Rectangle{Min: inner.Min, Max: inner.Min}
+	}
+	return inner
+}
+
+func fillAll(canvas *cv.Canvas, rects []image.Rectangle, color color.RGBA) {
+	for _, r := range rects {
+		canvas.Rectangle(r.Min.X, r.Min.Y, r.Dx(), r.Dy(), color, true)
+	}
+}
@@ -0,0 +1,65 @@
+package inspect
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestOverlay(t *testing.T) {
+	width := 30
+	white := color.RGBA{255, 255, 255, 255}
+	root := &render_objects.Container{
+		Decoration: render_objects.BoxDecoration{Background: cv.SolidColor{Color: white}},
+		Margin:     types.EdgeInsetsAll(4),
+		Padding:    types.EdgeInsetsAll(6),
+		Child: &render_objects.Column{Children: []render_objects.RenderObject{
+			render_objects.NewText("Hi", color.Black, 10, ""),
+			&render_objects.SizedBox{Width: &width, Child: &render_objects.Row{Children: []render_objects.RenderObject{box(20, 10), box(20, 10)}}},
+		}},
+	}
+
+	canvas := cv.NewCanvas(types.Size{Width: 80, Height: 60}, true)
+	(&Overlay{Child: root}).Paint(canvas)
+
+	tests := []struct {
+		name  string
+		x, y  int
+		check func(c color.RGBA) bool
+	}{
+		{"bounds", 0, 30, func(c color.RGBA) bool { return c == boundsColor }},
+		{"margin", 2, 30, func(c color.RGBA) bool { return c.R > c.B && c.A > 0 }},
+		{"padding", 6, 40, func(c color.RGBA) bool { return c.B > c.R && c.B < 255 }},
+		{"baseline", 20, 20, func(c color.RGBA) bool { return c == baselineColor }},
+		{"overflow", 45, 30, func(c color.RGBA) bool { return c.R > c.G && c.R > c.B && c.R < 255 }},
+	}
+	for _, test := range tests {
+		if c := canvas.Img.RGBAAt(test.x, test.y); !test.check(c) {
+			t.Errorf("Expected the %s color at (%d, %d), got %v", test.name, test.x, test.y, c)
+		}
+	}
+}
+
+func TestOverlayLabels(t *testing.T) {
+	root := &render_objects.Align{Child: box(10, 10), Align: render_objects.AlignCenter}
+	size := types.Size{Width: 60, Height: 40}
+
+	plain := cv.NewCanvas(size, false)
+	(&Overlay{Child: root}).Paint(plain)
+	labeled := cv.NewCanvas(size, false)
+	(&Overlay{Child: root, Labels: true}).Paint(labeled)
+
+	if got := labeled.Img.RGBAAt(5, 5); got == plain.Img.RGBAAt(5, 5) {
+		t.Errorf("Expected a label in the top left corner, got %v in both images", got)
+	}
+	// The anchor of the alignment is marked at the center
+	if got := plain.Img.RGBAAt(30, 18); got != anchorColor {
+		t.Errorf("Expected the anchor color above the center, got %v", got)
+	}
+	if size := (&Overlay{Child: root}).Size(size); size != (types.Size{Width: 10, Height: 10}) {
+		t.Errorf("Expected the size of the child, got %v", size)
+	}
+}
@@ -0,0 +1,517 @@
+package markup
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// inherited holds the properties children take from their parent
+type inherited struct {
+	color    color.Color
+	fontSize float64
+	bold     bool
+	italic   bool
+	align    string
+}
+
+// apply returns the properties of an element with the given style
+func (i inherited) apply(s *style) inherited {
+	if s.color != nil {
+		i.color = *s.color
+	}
+	if s.fontSize != nil {
+		i.fontSize = *s.fontSize
+	}
+	if s.bold != nil {
+		i.bold = *s.bold
+	}
+	if s.italic != nil {
+		i.italic = *s.italic
+	}
+	if s.textAlign != nil {
+		i.align = *s.textAlign
+	}
+	return i
+}
+
+// font returns the name of the registered font for the weight and style
+func (i inherited) font() string {
+	switch {
+	case i.bold && i.italic:
+		return "bold-italic"
+	case i.bold:
+		return "bold"
+	case i.italic:
+		return "italic"
+	default:
+		return cv.DefaultFont
+	}
+}
+
+// piece is a part of a line of inline content: text, an inline box or a line break
+type piece struct {
+	text      string
+	inherited inherited
+	object    render_objects.RenderObject
+	lineBreak bool
+}
+
+type builder struct {
+	src     string
+	options *Options
+}
+
+func (b *builder) root(nodes []*node) (render_objects.RenderObject, error) {
+	base := inherited{color: color.Black, fontSize: 16, align: "left"}
+	if b.options.Color != nil {
+		base.color = b.options.Color
+	}
+	if b.options.FontSize > 0 {
+		base.fontSize = b.options.FontSize
+	}
+
+	objects, err := b.flow(nodes, base)
+	if err != nil {
+		return nil, err
+	}
+	switch len(objects) {
+	case 0:
+		return &render_objects.SizedBox{Width: render_objects.Px(0), Height: render_objects.Px(0)}, nil
+	case 1:
+		return objects[0], nil
+	default:
+		return &render_objects.Column{Children: objects}, nil
+	}
+}
+
+// style parses the style attribute of an element, adding the defaults of its tag
+func (b *builder) style(n *node) (*style, error) {
+	s, err := parseStyle(n.attrs["style"])
+	if err != nil {
+		return nil, b.errorf(n, "%v", err)
+	}
+	yes := true
+	switch n.tag {
+	case "b", "strong":
+		if s.bold == nil {
+			s.bold = &yes
+		}
+	case "i", "em":
+		if s.italic == nil {
+			s.italic = &yes
+		}
+	}
+	if s.display == "" {
+		s.display = "inline"
+		if n.tag == "div" || n.tag == "p" {
+			s.display = "block"
+		}
+	}
+	return s, nil
+}
+
+// flow lays out block level elements one below the other, with runs of inline content between them
+func (b *builder) flow(nodes []*node, inh inherited) ([]render_objects.RenderObject, error) {
+	var objects []render_objects.RenderObject
+	var run []*node
+	flush := func() error {
+		line, err := b.inline(run, inh, true)
+		if line != nil {
+			objects = append(objects, line)
+		}
+		run = nil
+		return err
+	}
+
+	for _, n := range nodes {
+		if n.tag == "" {
+			run = append(run, n)
+			continue
+		}
+		s, err := b.style(n)
+		if err != nil {
+			return nil, err
+		}
+		switch s.display {
+		case "none":
+			continue
+		case "inline":
+			run = append(run, n)
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		box, err := b.box(n, s, inh.apply(s), true)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, box)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// box builds an element with a box of its own.
+// Stretched boxes take the full width of their parent unless they have a width.
+func (b *builder) box(n *node, s *style, inh inherited, stretch bool) (render_objects.RenderObject, error) {
+	if n.tag == "img" {
+		return b.image(n, s)
+	}
+
+	var content render_objects.RenderObject
+	if s.display == "flex" {
+		flex, err := b.flex(n, s, inh)
+		if err != nil {
+			return nil, err
+		}
+		content = flex
+	} else {
+		objects, err := b.flow(n.children, inh)
+		if err != nil {
+			return nil, err
+		}
+		switch len(objects) {
+		case 0:
+		case 1:
+			content = objects[0]
+		default:
+			content = &render_objects.Column{Children: objects}
+		}
+	}
+
+	// An empty container would fill its parent, so empty elements get an empty child
+	stretched := stretch || s.width != nil
+	switch {
+	case content == nil && stretched:
+		content = &render_objects.SizedBox{Width: render_objects.Px(render_objects.SizeExpand), Height: render_objects.Px(0)}
+	case content == nil:
+		content = &render_objects.SizedBox{Width: render_objects.Px(0), Height: render_objects.Px(0)}
+	case stretched:
+		content = &render_objects.SizedBox{Child: content, Width: render_objects.Px(render_objects.SizeExpand)}
+	}
+
+	return b.decorate(s, content, s.background), nil
+}
+
+// decorate wraps content in a container with the box properties of a style
+func (b *builder) decorate(s *style, content render_objects.RenderObject, background cv.Shader) render_objects.RenderObject {
+	var object render_objects.RenderObject = &render_objects.Container{
+		Child:   content,
+		Padding: s.padding,
+		Margin:  s.margin,
+		Width:   s.width,
+		Height:  s.height,
+		Decoration: render_objects.BoxDecoration{
+			Background:   background,
+			BorderRadius: s.radius,
+			Border:       s.border,
+		},
+	}
+	if s.opacity < 1 {
+		object = &render_objects.Opacity{Child: object, Opacity: s.opacity}
+	}
+	return object
+}
+
+// flex lays out the children of a flex container in a row or column.
+// Every element child is an item, and so is every run of text.
+func (b *builder) flex(n *node, s *style, inh inherited) (render_objects.RenderObject, error) {
+	column := s.direction == "column"
+	var items []render_objects.RenderObject
+	for _, child := range n.children {
+		if child.tag == "" {
+			item, err := b.inline([]*node{child}, inh, column)
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				items = append(items, item)
+			}
+			continue
+		}
+		if child.tag == "br" {
+			continue
+		}
+
+		childStyle, err := b.style(child)
+		if err != nil {
+			return nil, err
+		}
+		if childStyle.display == "none" {
+			continue
+		}
+		item, err := b.box(child, childStyle, inh.apply(childStyle), column)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	// Free space to distribute only exists along a sized main axis
+	sizing := types.MainAxisSizeMin
+	if s.justify != types.MainAxisAlignmentStart && (!column || s.height != nil) {
+		sizing = types.MainAxisSizeMax
+	}
+	if column {
+		return &render_objects.Column{Children: items, Alignment: s.justify, Sizing: sizing}, nil
+	}
+	return &render_objects.Row{Children: items, Alignment: s.justify, Sizing: sizing}, nil
+}
+
+// inline lays out a run of text and inline elements line by line, returning nil when it is empty
+func (b *builder) inline(nodes []*node, inh inherited, wrap bool) (render_objects.RenderObject, error) {
+	pieces, err := b.pieces(nodes, inh)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines [][]piece
+	var line []piece
+	for _, p := range pieces {
+		if p.lineBreak {
+			lines = append(lines, line)
+			line = nil
+			continue
+		}
+		line = append(line, p)
+	}
+	lines = append(lines, line)
+
+	var objects []render_objects.RenderObject
+	for i, line := range lines {
+		line = collapseWhitespace(line)
+		if len(line) == 0 {
+			// Breaks leave empty lines behind, a run without content is dropped
+			if len(lines) == 1 || i == len(lines)-1 {
+				continue
+			}
+			objects = append(objects, render_objects.NewText("", inh.color, inh.fontSize, inh.font()))
+			continue
+		}
+		objects = append(objects, b.line(line, inh, wrap))
+	}
+
+	switch len(objects) {
+	case 0:
+		return nil, nil
+	case 1:
+		return objects[0], nil
+	default:
+		return &render_objects.Column{Children: objects}, nil
+	}
+}
+
+// line builds one line of inline content aligned by the text-align of its block.
+// Wrapped text aligns each of its lines as well as the block they make up.
+func (b *builder) line(line []piece, inh inherited, wrap bool) render_objects.RenderObject {
+	text := func(p piece, wrap bool) render_objects.RenderObject {
+		if wrap {
+			return render_objects.NewAlignedText(p.text, p.inherited.color, p.inherited.fontSize, p.inherited.font(), textAlign(inh.align))
+		}
+		return render_objects.NewText(p.text, p.inherited.color, p.inherited.fontSize, p.inherited.font())
+	}
+
+	var object render_objects.RenderObject
+	if len(line) == 1 && line[0].object == nil {
+		object = text(line[0], wrap)
+	} else {
+		row := &render_objects.Row{}
+		for _, p := range line {
+			if p.object != nil {
+				row.Children = append(row.Children, p.object)
+			} else {
+				row.Children = append(row.Children, text(p, false))
+			}
+		}
+		object = row
+	}
+
+	var align render_objects.AlignType
+	switch inh.align {
+	case "center":
+		align = render_objects.AlignTopCenter
+	case "right", "end":
+		align = render_objects.AlignTopRight
+	default:
+		return object
+	}
+	return &render_objects.SizedBox{
+		Child: &render_objects.Align{Child: object, Align: align},
+		Width: render_objects.Px(render_objects.SizeExpand),
+	}
+}
+
+// textAlign returns the line alignment of a text-align keyword
+func textAlign(align string) render_objects.TextAlign {
+	switch align {
+	case "center":
+		return render_objects.TextAlignCenter
+	case "right", "end":
+		return render_objects.TextAlignRight
+	default:
+		return render_objects.TextAlignLeft
+	}
+}
+
+// pieces flattens inline content, turning decorated elements and images into inline boxes
+func (b *builder) pieces(nodes []*node, inh inherited) ([]piece, error) {
+	var pieces []piece
+	for _, n := range nodes {
+		if n.tag == "" {
+			pieces = append(pieces, piece{text: n.text, inherited: inh})
+			continue
+		}
+		if n.tag == "br" {
+			pieces = append(pieces, piece{lineBreak: true})
+			continue
+		}
+
+		s, err := b.style(n)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case s.display == "none":
+			continue
+		case s.display != "inline":
+			return nil, b.errorf(n, "display %s isn't supported inside inline content", s.display)
+		case n.tag == "img":
+			img, err := b.image(n, s)
+			if err != nil {
+				return nil, err
+			}
+			pieces = append(pieces, piece{object: img})
+			continue
+		}
+
+		childInh := inh.apply(s)
+		if !s.boxed() && s.radius == (cv.Radii{}) {
+			children, err := b.pieces(n.children, childInh)
+			if err != nil {
+				return nil, err
+			}
+			pieces = append(pieces, children...)
+			continue
+		}
+
+		for _, child := range n.children {
+			if child.tag == "br" {
+				return nil, b.errorf(child, "line breaks aren't supported inside decorated inline elements")
+			}
+		}
+		content, err := b.inline(n.children, childInh, false)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			content = &render_objects.SizedBox{Width: render_objects.Px(0), Height: render_objects.Px(0)}
+		}
+		pieces = append(pieces, piece{object: b.decorate(s, content, s.background)})
+	}
+	return pieces, nil
+}
+
+// collapseWhitespace turns runs of white space into single spaces, as browsers do,
+// trimming the start and end of the line and dropping empty text
+func collapseWhitespace(line []piece) []piece {
+	var collapsed []piece
+	afterSpace := true // Leading space of a line is dropped
+	for _, p := range line {
+		if p.object != nil {
+			collapsed = append(collapsed, p)
+			afterSpace = false
+			continue
+		}
+
+		var text strings.Builder
+		for _, r := range p.text {
+			if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+				if !afterSpace {
+					text.WriteByte(' ')
+				}
+				afterSpace = true
+				continue
+			}
+			text.WriteRune(r)
+			afterSpace = false
+		}
+		if text.Len() == 0 {
+			continue
+		}
+
+		// Text in the same style as the piece before it joins that piece
+		p.text = text.String()
+		if last := len(collapsed) - 1; last >= 0 && collapsed[last].object == nil && collapsed[last].inherited == p.inherited {
+			collapsed[last].text += p.text
+			continue
+		}
+		collapsed = append(collapsed, p)
+	}
+
+	// Trailing space is dropped from the end of the line
+	if last := len(collapsed) - 1; last >= 0 && collapsed[last].object == nil {
+		collapsed[last].text = strings.TrimSuffix(collapsed[last].text, " ")
+		if collapsed[last].text == "" {
+			collapsed = collapsed[:last]
+		}
+	}
+	return collapsed
+}
+
+// image builds an img element as a box painted with the image.
+// A missing width or height follows the image's aspect ratio.
+func (b *builder) image(n *node, s *style) (render_objects.RenderObject, error) {
+	src, ok := n.attrs["src"]
+	if !ok || src == "" {
+		return nil, b.errorf(n, "missing src attribute")
+	}
+	img, err := b.options.loadImage(src)
+	if err != nil {
+		return nil, b.errorf(n, "loading %q: %v", src, err)
+	}
+
+	width, height := s.width, s.height
+	for _, attr := range []struct {
+		name   string
+		target **int
+	}{{"width", &width}, {"height", &height}} {
+		value, ok := n.attrs[attr.name]
+		if !ok || *attr.target != nil {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimSuffix(value, "px"))
+		if err != nil || v < 0 {
+			return nil, b.errorf(n, "invalid %s %q, expected a number of pixels", attr.name, value)
+		}
+		*attr.target = &v
+	}
+
+	bounds := img.Bounds()
+	natural := types.Size{Width: bounds.Dx(), Height: bounds.Dy()}
+	switch {
+	case width == nil && height == nil:
+		width, height = &natural.Width, &natural.Height
+	case width == nil && natural.Height > 0:
+		width = render_objects.Px(*height * natural.Width / natural.Height)
+	case height == nil && natural.Width > 0:
+		height = render_objects.Px(*width * natural.Height / natural.Width)
+	}
+
+	sized := *s
+	sized.width, sized.height = width, height
+	return b.decorate(&sized, nil, cv.ImageShader{Image: img}), nil
+}
+
+func (b *builder) errorf(n *node, format string, args ...any) error {
+	return newError(b.src, n.pos, fmt.Sprintf("<%s>: %s", n.tag, fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,144 @@
+// Package markup builds render trees from a small subset of HTML with inline CSS,
+// for layouts that are easier to write as markup than as Go code.
+//
+// The supported elements are div, p, span, b, strong, i, em, img and br, with the
+// attributes style, id and class, and src, width, height and alt on img. There are no
+// default styles: p behaves like div, and b and i only change the font.
+//
+// Styles are given in style attributes and support these properties:
+//
+//	display                 block, inline, flex, none
+//	flex-direction          row, column
+//	justify-content         flex-start, center, flex-end, space-between, space-around, space-evenly
+//	width, height           px
+//	padding, margin         one to four px values, and the -top, -right, -bottom and -left sides
+//	border                  width, style (solid, dashed, dotted, none) and color, in any order,
+//	                        and the -width, -style, -color, -top, -right, -bottom and -left forms
+//	border-radius           one to four px values
+//	background              a color or a linear-gradient(), also as background-color
+//	opacity                 number from 0 to 1
+//	color                   any CSS color, see colors.Parse
+//	font-size               px
+//	font-weight             normal, bold or 100 to 900
+//	font-style              normal, italic, oblique
+//	text-align              left, start, center, right, end
+//
+// color, font-size, font-weight, font-style and text-align are inherited. Anything else is
+// an error naming the element and its position in the source. Widths and heights include
+// padding and border, as with box-sizing: border-box.
+//
+// Inline content is laid out line by line: a run of text in a single style wraps to the
+// width of its block, while lines mixing styles, decorated spans or images are kept on one
+// line. Use br to break such lines.
+package markup
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hvuhsg/render/render_objects"
+)
+
+// Error reports a problem at a position of the markup
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// Options control how markup is turned into a render tree
+type Options struct {
+	BaseDir   string                                // Directory relative image sources are read from
+	LoadImage func(src string) (image.Image, error) // Replaces the default loader of img sources
+	FontSize  float64                               // Font size of the root, 16 by default
+	Color     color.Color                           // Text color of the root, black by default
+}
+
+// Parse builds the render tree described by markup.
+// Several top level elements are stacked vertically. A nil options uses the defaults.
+func Parse(src string, options *Options) (render_objects.RenderObject, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	nodes, err := parseHTML(src)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{src: src, options: options}
+	return b.root(nodes)
+}
+
+// ParseFile builds the render tree described by a markup file.
+// Image sources are relative to the file unless options set a BaseDir.
+func ParseFile(path string, options *Options) (render_objects.RenderObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.BaseDir == "" {
+		opts.BaseDir = filepath.Dir(path)
+	}
+	object, err := Parse(string(data), &opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return object, nil
+}
+
+// loadImage reads data: URIs and files relative to the base directory
+func (o *Options) loadImage(src string) (image.Image, error) {
+	if o.LoadImage != nil {
+		return o.LoadImage(src)
+	}
+
+	switch {
+	case strings.HasPrefix(src, "data:"):
+		return DecodeDataURI(src)
+	case strings.Contains(src, "://"):
+		return nil, errors.New("remote images are not supported, set Options.LoadImage to fetch them")
+	}
+
+	path := src
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(o.BaseDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// DecodeDataURI decodes a PNG, JPEG or GIF image from a base64 data URI.
+// Loaders that shouldn't touch the file system can accept just these.
+func DecodeDataURI(src string) (image.Image, error) {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(src, "data:"), ",")
+	if !strings.HasPrefix(src, "data:") || !ok || !strings.HasSuffix(meta, ";base64") {
+		return nil, errors.New("only base64 data URIs are supported")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -0,0 +1,227 @@
+package markup
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestParseBlock(t *testing.T) {
+	root, err := Parse(`<div style="padding: 4px 8px; margin: 2px; background: #ff0000; border: 2px solid navy; border-radius: 6px">Hi</div>`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+
+	container, ok := root.(*render_objects.Container)
+	if !ok {
+		t.Fatalf("Expected a Container, got %T", root)
+	}
+	if container.Padding != types.EdgeInsetsSymmetric(4, 8) {
+		t.Errorf("Expected symmetric padding, got %+v", container.Padding)
+	}
+	if container.Margin != types.EdgeInsetsAll(2) {
+		t.Errorf("Expected a 2 pixel margin, got %+v", container.Margin)
+	}
+	if container.Decoration.BorderRadius != cv.RadiiAll(6) {
+		t.Errorf("Expected radius 6, got %+v", container.Decoration.BorderRadius)
+	}
+	if container.Decoration.Border.Top != (cv.BorderSide{Width: 2, Color: color.RGBA{0, 0, 128, 255}}) {
+		t.Errorf("Expected a navy border, got %+v", container.Decoration.Border.Top)
+	}
+	if container.Decoration.Background != (cv.SolidColor{Color: color.RGBA{255, 0, 0, 255}}) {
+		t.Errorf("Expected a red background, got %+v", container.Decoration.Background)
+	}
+
+	// Blocks take the full width of their parent, margin included
+	size := root.Size(types.Size{Width: 300, Height: 200})
+	if size.Width != 300 {
+		t.Errorf("Expected the box to fill the width, got %d", size.Width)
+	}
+}
+
+func TestParseFlex(t *testing.T) {
+	root, err := Parse(`<div style="display: flex; justify-content: space-between"><span>a</span><span>b</span></div>`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+
+	content := root.(*render_objects.Container).Child.(*render_objects.SizedBox).Child
+	row, ok := content.(*render_objects.Row)
+	if !ok {
+		t.Fatalf("Expected a Row, got %T", content)
+	}
+	if row.Alignment != types.MainAxisAlignmentSpaceBetween || row.Sizing != types.MainAxisSizeMax {
+		t.Errorf("Expected a full width space between row, got %+v", row)
+	}
+	if len(row.Children) != 2 {
+		t.Errorf("Expected 2 items, got %d", len(row.Children))
+	}
+
+	root, err = Parse(`<div style="display: flex; flex-direction: column"><div>a</div><div>b</div></div>`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+	content = root.(*render_objects.Container).Child.(*render_objects.SizedBox).Child
+	if _, ok := content.(*render_objects.Column); !ok {
+		t.Errorf("Expected a Column, got %T", content)
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	root, err := Parse(`<p style="text-align: center">  Hello   <b>bold</b>
+		world </p>`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+
+	line := root.(*render_objects.Container).Child.(*render_objects.SizedBox).Child
+	align, ok := line.(*render_objects.SizedBox).Child.(*render_objects.Align)
+	if !ok || align.Align != render_objects.AlignTopCenter {
+		t.Fatalf("Expected a centered line, got %T", line)
+	}
+	row, ok := align.Child.(*render_objects.Row)
+	if !ok || len(row.Children) != 3 {
+		t.Fatalf("Expected a row of three runs of text, got %+v", align.Child)
+	}
+
+	pieces, err := (&builder{options: &Options{}}).pieces(mustParseHTML(t, "  a \n b <i> c </i> "), inherited{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	collapsed := collapseWhitespace(pieces)
+	if len(collapsed) != 2 || collapsed[0].text != "a b " || collapsed[1].text != "c" || !collapsed[1].inherited.italic {
+		t.Errorf("Expected white space to collapse into \"a b \" and \"c\", got %+v", collapsed)
+	}
+}
+
+func TestParseLineBreaks(t *testing.T) {
+	root, err := Parse(`a<br>b<br><br>c`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+	column, ok := root.(*render_objects.Column)
+	if !ok || len(column.Children) != 4 {
+		t.Fatalf("Expected 4 lines, got %+v", root)
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	src := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	root, err := Parse(`<img src="`+src+`" width="10" style="border-radius: 4px; display: block">`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+	container := root.(*render_objects.Container)
+	if *container.Width != 10 || *container.Height != 5 {
+		t.Errorf("Expected the height to follow the aspect ratio, got %dx%d", *container.Width, *container.Height)
+	}
+	if _, ok := container.Decoration.Background.(cv.ImageShader); !ok {
+		t.Errorf("Expected an image background, got %T", container.Decoration.Background)
+	}
+
+	_, err = Parse(`<img src="missing.png">`, &Options{LoadImage: func(string) (image.Image, error) {
+		return nil, errors.New("not found")
+	}})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected the loader error, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		message string
+	}{
+		{`<div style="float: left"></div>`, `line 1, column 1: <div>: unsupported property "float"`},
+		{"<div>\n  <table></table></div>", `line 2, column 3: unsupported element <table>`},
+		{`<div onclick="x"></div>`, `line 1, column 6: unsupported attribute "onclick" on <div>`},
+		{`<div><span></div>`, `unexpected </div>, expected </span>`},
+		{`<div>`, `<div> from line 1, column 1 is never closed`},
+		{`<p style="width: 50%"></p>`, `<p>: width: unsupported length "50%"`},
+		{`<p style="color: nope"></p>`, `<p>: color: invalid color`},
+		{`<span><div></div></span>`, `<div>: display block isn't supported inside inline content`},
+		{`<img>`, `<img>: missing src attribute`},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.src, nil)
+		var markupErr *Error
+		if !errors.As(err, &markupErr) {
+			t.Errorf("Expected a markup error for %q, got %v", test.src, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Expected %q to fail with %q, got %q", test.src, test.message, err)
+		}
+	}
+}
+
+func TestParsePaints(t *testing.T) {
+	root, err := Parse(`<div style="background: #0000ff; height: 20px"></div><div style="background: #00ff00; height: 20px"></div>`, nil)
+	if err != nil {
+		t.Fatalf("Expected the markup to parse, got %v", err)
+	}
+
+	canvas := cv.NewCanvas(types.Size{Width: 50, Height: 50}, false)
+	root.Paint(canvas)
+	if got := canvas.Img.RGBAAt(25, 10); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("Expected the first block to be blue, got %v", got)
+	}
+	if got := canvas.Img.RGBAAt(45, 30); got != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("Expected the second block to be green across the width, got %v", got)
+	}
+	if got := canvas.Img.RGBAAt(25, 45); got.A != 0 {
+		t.Errorf("Expected nothing below the blocks, got %v", got)
+	}
+}
+
+func TestParseAlignsWrappedLines(t *testing.T) {
+	for align, expected := range map[string]render_objects.TextAlign{
+		"center": render_objects.TextAlignCenter,
+		"right":  render_objects.TextAlignRight,
+		"left":   "",
+	} {
+		root, err := Parse(`<div style="text-align: `+align+`">The quick brown fox jumps over the lazy dog</div>`, nil)
+		if err != nil {
+			t.Fatalf("Expected the markup to parse, got %v", err)
+		}
+
+		// The lines of the paragraph are aligned, not only the block they make up
+		var text *render_objects.Text
+		switch line := root.(*render_objects.Container).Child.(*render_objects.SizedBox).Child.(type) {
+		case *render_objects.Text:
+			text = line
+		case *render_objects.SizedBox:
+			text, _ = line.Child.(*render_objects.Align).Child.(*render_objects.Text)
+		}
+		if text == nil {
+			t.Errorf("Expected text-align %s to keep a single wrapped text, got %+v", align, root)
+			continue
+		}
+		if got, _ := text.Describe()["Align"].(render_objects.TextAlign); got != expected {
+			t.Errorf("Expected text-align %s to align the lines of wrapped text %q, got %q", align, expected, got)
+		}
+	}
+}
+
+func mustParseHTML(t *testing.T, src string) []*node {
+	t.Helper()
+	nodes, err := parseHTML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return nodes
+}
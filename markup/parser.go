@@ -0,0 +1,238 @@
+package markup
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// node is an element or, with an empty tag, a run of text
+type node struct {
+	tag      string
+	attrs    map[string]string
+	children []*node
+	text     string
+	pos      int // Offset of the node in the source
+}
+
+// Elements and the attributes each one accepts besides style, id and class
+var elements = map[string][]string{
+	"div":    nil,
+	"p":      nil,
+	"span":   nil,
+	"b":      nil,
+	"strong": nil,
+	"i":      nil,
+	"em":     nil,
+	"img":    {"src", "width", "height", "alt"},
+	"br":     nil,
+}
+
+// Elements without content or closing tag
+var voidElements = map[string]bool{"img": true, "br": true}
+
+type htmlParser struct {
+	src string
+	pos int
+}
+
+// parseHTML returns the top level nodes of the source
+func parseHTML(src string) ([]*node, error) {
+	p := &htmlParser{src: src}
+	root := &node{tag: "#root"}
+	stack := []*node{root}
+
+	for p.pos < len(p.src) {
+		parent := stack[len(stack)-1]
+		rest := p.src[p.pos:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return nil, p.errorf(p.pos, "unclosed comment")
+			}
+			p.pos += end + len("-->")
+
+		case strings.HasPrefix(rest, "<!"):
+			// Doctype declarations are skipped
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return nil, p.errorf(p.pos, "unclosed declaration")
+			}
+			p.pos += end + 1
+
+		case strings.HasPrefix(rest, "</"):
+			start := p.pos
+			p.pos += 2
+			name := p.name()
+			p.spaces()
+			if !p.consume(">") {
+				return nil, p.errorf(p.pos, "expected > to end </%s>", name)
+			}
+			if len(stack) == 1 {
+				return nil, p.errorf(start, "unexpected </%s>, no element is open", name)
+			}
+			if name != parent.tag {
+				return nil, p.errorf(start, "unexpected </%s>, expected </%s> to close %s", name, parent.tag, p.describe(parent))
+			}
+			stack = stack[:len(stack)-1]
+
+		case strings.HasPrefix(rest, "<"):
+			element, selfClosing, err := p.startTag()
+			if err != nil {
+				return nil, err
+			}
+			parent.children = append(parent.children, element)
+			if !selfClosing && !voidElements[element.tag] {
+				stack = append(stack, element)
+			}
+
+		default:
+			end := strings.IndexByte(rest, '<')
+			if end < 0 {
+				end = len(rest)
+			}
+			parent.children = append(parent.children, &node{text: html.UnescapeString(rest[:end]), pos: p.pos})
+			p.pos += end
+		}
+	}
+
+	if len(stack) > 1 {
+		open := stack[len(stack)-1]
+		return nil, p.errorf(open.pos, "%s is never closed", p.describe(open))
+	}
+	return root.children, nil
+}
+
+// startTag reads an opening tag and its attributes
+func (p *htmlParser) startTag() (*node, bool, error) {
+	start := p.pos
+	p.pos++
+	name := p.name()
+	if name == "" {
+		return nil, false, p.errorf(start, "expected an element name after <")
+	}
+	allowed, ok := elements[name]
+	if !ok {
+		return nil, false, p.errorf(start, "unsupported element <%s>, expected one of %s", name, supportedElements())
+	}
+
+	element := &node{tag: name, attrs: map[string]string{}, pos: start}
+	for {
+		p.spaces()
+		switch {
+		case p.pos >= len(p.src):
+			return nil, false, p.errorf(start, "unclosed tag <%s", name)
+		case p.consume("/>"):
+			return element, true, nil
+		case p.consume(">"):
+			return element, false, nil
+		}
+
+		attrPos := p.pos
+		attr := p.name()
+		if attr == "" {
+			return nil, false, p.errorf(p.pos, "unexpected %q in <%s>", p.src[p.pos], name)
+		}
+		if attr != "style" && attr != "id" && attr != "class" && !slices.Contains(allowed, attr) {
+			return nil, false, p.errorf(attrPos, "unsupported attribute %q on <%s>", attr, name)
+		}
+		if _, dup := element.attrs[attr]; dup {
+			return nil, false, p.errorf(attrPos, "duplicate attribute %q on <%s>", attr, name)
+		}
+
+		value := ""
+		p.spaces()
+		if p.consume("=") {
+			p.spaces()
+			v, err := p.attrValue()
+			if err != nil {
+				return nil, false, err
+			}
+			value = v
+		}
+		element.attrs[attr] = html.UnescapeString(value)
+	}
+}
+
+// attrValue reads a quoted or unquoted attribute value
+func (p *htmlParser) attrValue() (string, error) {
+	start := p.pos
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		quote := p.src[p.pos]
+		end := strings.IndexByte(p.src[p.pos+1:], quote)
+		if end < 0 {
+			return "", p.errorf(start, "unterminated attribute value")
+		}
+		p.pos += end + 2
+		return p.src[start+1 : p.pos-1], nil
+	}
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n>", rune(p.src[p.pos])) && !strings.HasPrefix(p.src[p.pos:], "/>") {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf(start, "expected an attribute value")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// name reads a lower cased element or attribute name
+func (p *htmlParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToLower(p.src[start:p.pos])
+}
+
+func (p *htmlParser) spaces() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *htmlParser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// describe names an element and where it starts, for errors about it
+func (p *htmlParser) describe(n *node) string {
+	line, column := position(p.src, n.pos)
+	return fmt.Sprintf("<%s> from line %d, column %d", n.tag, line, column)
+}
+
+func (p *htmlParser) errorf(pos int, format string, args ...any) error {
+	return newError(p.src, pos, fmt.Sprintf(format, args...))
+}
+
+func newError(src string, pos int, message string) *Error {
+	line, column := position(src, pos)
+	return &Error{Line: line, Column: column, Message: message}
+}
+
+// position returns the 1 based line and column of an offset
+func position(src string, pos int) (line, column int) {
+	before := src[:min(pos, len(src))]
+	line = strings.Count(before, "\n") + 1
+	column = len(before) - strings.LastIndexByte(before, '\n')
+	return line, column
+}
+
+func supportedElements() string {
+	names := make([]string, 0, len(elements))
+	for name := range elements {
+		names = append(names, "<"+name+">")
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,487 @@
+package markup
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/types"
+)
+
+// style holds the declarations of a style attribute.
+// Inherited properties are pointers so unset values fall back to the parent's.
+type style struct {
+	display    string // Empty for the element's default
+	direction  string
+	justify    types.MainAxisAlignment
+	width      *int
+	height     *int
+	padding    types.EdgeInsets
+	margin     types.EdgeInsets
+	border     cv.BorderSides
+	radius     cv.Radii
+	background cv.Shader
+	opacity    float64
+
+	color     *color.RGBA
+	fontSize  *float64
+	bold      *bool
+	italic    *bool
+	textAlign *string
+}
+
+// boxed reports whether the style gives an element a box of its own
+func (s *style) boxed() bool {
+	top, right, bottom, left := s.border.Widths()
+	return s.width != nil || s.height != nil || s.background != nil || s.opacity < 1 ||
+		s.padding != (types.EdgeInsets{}) || s.margin != (types.EdgeInsets{}) ||
+		top+right+bottom+left > 0
+}
+
+// parseStyle reads the declarations of a style attribute
+func parseStyle(text string) (*style, error) {
+	s := &style{direction: "row", opacity: 1}
+	for _, declaration := range strings.Split(text, ";") {
+		if strings.TrimSpace(declaration) == "" {
+			continue
+		}
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected property: value, got %q", strings.TrimSpace(declaration))
+		}
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, fmt.Errorf("missing value for %q", property)
+		}
+		if err := s.set(property, value); err == errUnsupportedProperty {
+			return nil, fmt.Errorf("unsupported property %q", property)
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", property, err)
+		}
+	}
+	return s, nil
+}
+
+func (s *style) set(property, value string) error {
+	keyword := strings.ToLower(value)
+	switch property {
+	case "display":
+		return oneOf(keyword, &s.display, "block", "inline", "flex", "none")
+	case "flex-direction":
+		return oneOf(keyword, &s.direction, "row", "column")
+	case "justify-content":
+		justify, ok := justifyValues[keyword]
+		if !ok {
+			return fmt.Errorf("unsupported value %q, expected flex-start, center, flex-end, space-between, space-around or space-evenly", value)
+		}
+		s.justify = justify
+	case "width", "height":
+		length, err := parseLength(value)
+		if err != nil {
+			return err
+		}
+		if property == "width" {
+			s.width = &length
+		} else {
+			s.height = &length
+		}
+	case "padding", "margin":
+		insets, err := parseInsets(value)
+		if err != nil {
+			return err
+		}
+		if property == "padding" {
+			s.padding = insets
+		} else {
+			s.margin = insets
+		}
+	case "padding-top", "padding-right", "padding-bottom", "padding-left",
+		"margin-top", "margin-right", "margin-bottom", "margin-left":
+		length, err := parseLength(value)
+		if err != nil {
+			return err
+		}
+		box, side, _ := strings.Cut(property, "-")
+		insets := &s.padding
+		if box == "margin" {
+			insets = &s.margin
+		}
+		*insetSide(insets, side) = length
+	case "border":
+		side, err := parseBorderSide(value)
+		if err != nil {
+			return err
+		}
+		s.border = cv.UniformBorder(side)
+	case "border-top", "border-right", "border-bottom", "border-left":
+		side, err := parseBorderSide(value)
+		if err != nil {
+			return err
+		}
+		*borderSide(&s.border, strings.TrimPrefix(property, "border-")) = side
+	case "border-width":
+		length, err := parseLength(value)
+		if err != nil {
+			return err
+		}
+		s.eachBorderSide(func(side *cv.BorderSide) { side.Width = length })
+	case "border-style":
+		borderStyle, ok := borderStyles[keyword]
+		if !ok {
+			return fmt.Errorf("unsupported value %q, expected solid, dashed, dotted or none", value)
+		}
+		s.eachBorderSide(func(side *cv.BorderSide) { side.Style = borderStyle })
+	case "border-color":
+		c, err := colors.Parse(value)
+		if err != nil {
+			return err
+		}
+		s.eachBorderSide(func(side *cv.BorderSide) { side.Color = c })
+	case "border-radius":
+		lengths, err := parseLengths(value)
+		if err != nil {
+			return err
+		}
+		// Corners are listed clockwise from the top left, missing ones copy the opposite corner
+		r := expandFour(lengths)
+		s.radius = cv.Radii{TopLeft: r[0], TopRight: r[1], BottomRight: r[2], BottomLeft: r[3]}
+	case "background", "background-color":
+		if property == "background" && strings.HasPrefix(keyword, "linear-gradient(") {
+			gradient, err := parseLinearGradient(value)
+			if err != nil {
+				return err
+			}
+			s.background = gradient
+			return nil
+		}
+		c, err := colors.Parse(value)
+		if err != nil {
+			return err
+		}
+		s.background = cv.SolidColor{Color: c}
+	case "opacity":
+		opacity, err := strconv.ParseFloat(value, 64)
+		if err != nil || opacity < 0 || opacity > 1 {
+			return fmt.Errorf("expected a number from 0 to 1, got %q", value)
+		}
+		s.opacity = opacity
+	case "color":
+		c, err := colors.Parse(value)
+		if err != nil {
+			return err
+		}
+		s.color = &c
+	case "font-size":
+		length, err := parseLength(value)
+		if err != nil {
+			return err
+		}
+		size := float64(length)
+		s.fontSize = &size
+	case "font-weight":
+		var bold bool
+		switch keyword {
+		case "normal":
+		case "bold", "bolder":
+			bold = true
+		default:
+			weight, err := strconv.Atoi(keyword)
+			if err != nil || weight < 100 || weight > 900 {
+				return fmt.Errorf("unsupported value %q, expected normal, bold or 100 to 900", value)
+			}
+			bold = weight >= 600
+		}
+		s.bold = &bold
+	case "font-style":
+		var italic string
+		if err := oneOf(keyword, &italic, "normal", "italic", "oblique"); err != nil {
+			return err
+		}
+		isItalic := italic != "normal"
+		s.italic = &isItalic
+	case "text-align":
+		var align string
+		if err := oneOf(keyword, &align, "left", "start", "center", "right", "end"); err != nil {
+			return err
+		}
+		s.textAlign = &align
+	default:
+		return errUnsupportedProperty
+	}
+	return nil
+}
+
+var errUnsupportedProperty = errors.New("unsupported property")
+
+func (s *style) eachBorderSide(f func(side *cv.BorderSide)) {
+	for _, side := range []*cv.BorderSide{&s.border.Top, &s.border.Right, &s.border.Bottom, &s.border.Left} {
+		f(side)
+	}
+}
+
+var justifyValues = map[string]types.MainAxisAlignment{
+	"flex-start":    types.MainAxisAlignmentStart,
+	"start":         types.MainAxisAlignmentStart,
+	"center":        types.MainAxisAlignmentCenter,
+	"flex-end":      types.MainAxisAlignmentEnd,
+	"end":           types.MainAxisAlignmentEnd,
+	"space-between": types.MainAxisAlignmentSpaceBetween,
+	"space-around":  types.MainAxisAlignmentSpaceAround,
+	"space-evenly":  types.MainAxisAlignmentSpaceEvenly,
+}
+
+var borderStyles = map[string]cv.BorderStyle{
+	"solid":  cv.BorderStyleSolid,
+	"dashed": cv.BorderStyleDashed,
+	"dotted": cv.BorderStyleDotted,
+	"none":   cv.BorderStyleNone,
+}
+
+func oneOf(value string, target *string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			*target = value
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported value %q, expected %s", value, strings.Join(allowed, ", "))
+}
+
+// parseLength reads a px length; a bare 0 is allowed too
+func parseLength(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "0" {
+		return 0, nil
+	}
+	number, ok := strings.CutSuffix(value, "px")
+	if !ok {
+		return 0, fmt.Errorf("unsupported length %q, only px lengths are supported", value)
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid length %q", value)
+	}
+	return int(math.Round(n)), nil
+}
+
+// parseLengths reads one to four space separated lengths
+func parseLengths(value string) ([]int, error) {
+	fields := strings.Fields(value)
+	if len(fields) > 4 {
+		return nil, fmt.Errorf("expected 1 to 4 lengths, got %d", len(fields))
+	}
+	lengths := make([]int, len(fields))
+	for i, field := range fields {
+		length, err := parseLength(field)
+		if err != nil {
+			return nil, err
+		}
+		lengths[i] = length
+	}
+	return lengths, nil
+}
+
+// expandFour applies the CSS shorthand rules to one to four values listed clockwise from the top
+func expandFour(v []int) [4]int {
+	switch len(v) {
+	case 1:
+		return [4]int{v[0], v[0], v[0], v[0]}
+	case 2:
+		return [4]int{v[0], v[1], v[0], v[1]}
+	case 3:
+		return [4]int{v[0], v[1], v[2], v[1]}
+	default:
+		return [4]int{v[0], v[1], v[2], v[3]}
+	}
+}
+
+func parseInsets(value string) (types.EdgeInsets, error) {
+	lengths, err := parseLengths(value)
+	if err != nil {
+		return types.EdgeInsets{}, err
+	}
+	v := expandFour(lengths)
+	return types.EdgeInsets{Top: v[0], Right: v[1], Bottom: v[2], Left: v[3]}, nil
+}
+
+func insetSide(insets *types.EdgeInsets, side string) *int {
+	switch side {
+	case "top":
+		return &insets.Top
+	case "right":
+		return &insets.Right
+	case "bottom":
+		return &insets.Bottom
+	default:
+		return &insets.Left
+	}
+}
+
+func borderSide(border *cv.BorderSides, side string) *cv.BorderSide {
+	switch side {
+	case "top":
+		return &border.Top
+	case "right":
+		return &border.Right
+	case "bottom":
+		return &border.Bottom
+	default:
+		return &border.Left
+	}
+}
+
+// parseBorderSide reads a width, style and color given in any order, like "1px solid #ccc".
+// The width defaults to 1px and the color to black.
+func parseBorderSide(value string) (cv.BorderSide, error) {
+	if strings.EqualFold(value, "none") {
+		return cv.BorderSide{Style: cv.BorderStyleNone}, nil
+	}
+	side := cv.BorderSide{Width: 1, Color: color.RGBA{0, 0, 0, 255}}
+	for _, part := range splitTopLevel(value, ' ') {
+		if borderStyle, ok := borderStyles[strings.ToLower(part)]; ok {
+			side.Style = borderStyle
+			continue
+		}
+		if width, err := parseLength(part); err == nil {
+			side.Width = width
+			continue
+		}
+		c, err := colors.Parse(part)
+		if err != nil {
+			return side, fmt.Errorf("unexpected %q, expected a width, style or color", part)
+		}
+		side.Color = c
+	}
+	return side, nil
+}
+
+// Gradient directions as angles, clockwise from pointing up
+var gradientDirections = map[string]float64{
+	"to top":    0,
+	"to right":  90,
+	"to bottom": 180,
+	"to left":   270,
+
+	"to top right":    45,
+	"to right top":    45,
+	"to bottom right": 135,
+	"to right bottom": 135,
+	"to bottom left":  225,
+	"to left bottom":  225,
+	"to top left":     315,
+	"to left top":     315,
+}
+
+// parseLinearGradient reads linear-gradient(direction, color [position], ...)
+func parseLinearGradient(value string) (cv.LinearGradient, error) {
+	var gradient cv.LinearGradient
+	inner, ok := strings.CutSuffix(strings.TrimSpace(value[len("linear-gradient("):]), ")")
+	if !ok {
+		return gradient, fmt.Errorf("missing ) in %q", value)
+	}
+	args := splitTopLevel(inner, ',')
+
+	angle := 180.0
+	if len(args) > 0 {
+		first := strings.ToLower(strings.Join(strings.Fields(args[0]), " "))
+		if a, ok := gradientDirections[first]; ok {
+			angle = a
+			args = args[1:]
+		} else if degrees, ok := strings.CutSuffix(first, "deg"); ok {
+			a, err := strconv.ParseFloat(degrees, 64)
+			if err != nil {
+				return gradient, fmt.Errorf("invalid angle %q", args[0])
+			}
+			angle = a
+			args = args[1:]
+		}
+	}
+	if len(args) < 2 {
+		return gradient, fmt.Errorf("a gradient needs at least two colors")
+	}
+
+	// The gradient line runs through the center and reaches the corners, as in CSS
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	half := (math.Abs(sin) + math.Abs(cos)) / 2
+	round := func(v float64) float64 { return math.Round(v*1e6) / 1e6 }
+	gradient.Start = [2]float64{round(0.5 - sin*half), round(0.5 + cos*half)}
+	gradient.End = [2]float64{round(0.5 + sin*half), round(0.5 - cos*half)}
+
+	offsets := make([]float64, len(args))
+	for i, arg := range args {
+		parts := splitTopLevel(arg, ' ')
+		c, err := colors.Parse(parts[0])
+		if err != nil {
+			return gradient, err
+		}
+		offsets[i] = math.NaN()
+		switch len(parts) {
+		case 1:
+		case 2:
+			percent, ok := strings.CutSuffix(parts[1], "%")
+			offset, err := strconv.ParseFloat(percent, 64)
+			if !ok || err != nil {
+				return gradient, fmt.Errorf("invalid stop position %q, expected a percentage", parts[1])
+			}
+			offsets[i] = offset / 100
+		default:
+			return gradient, fmt.Errorf("invalid color stop %q", strings.TrimSpace(arg))
+		}
+		gradient.Stops = append(gradient.Stops, cv.GradientStop{Color: c})
+	}
+
+	// Stops without a position are spread evenly between their neighbours
+	if math.IsNaN(offsets[0]) {
+		offsets[0] = 0
+	}
+	if last := len(offsets) - 1; math.IsNaN(offsets[last]) {
+		offsets[last] = 1
+	}
+	for i := 1; i < len(offsets); i++ {
+		if !math.IsNaN(offsets[i]) {
+			continue
+		}
+		next := i + 1
+		for math.IsNaN(offsets[next]) {
+			next++
+		}
+		step := (offsets[next] - offsets[i-1]) / float64(next-i+1)
+		for j := i; j < next; j++ {
+			offsets[j] = offsets[j-1] + step
+		}
+	}
+	for i := range gradient.Stops {
+		gradient.Stops[i].Offset = offsets[i]
+	}
+	return gradient, nil
+}
+
+// splitTopLevel splits on a separator outside of parentheses, dropping empty parts
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	depth, start := 0, 0
+	flush := func(end int) {
+		if part := strings.TrimSpace(s[start:end]); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0 && (r == sep || sep == ' ' && (r == '\t' || r == '\n')):
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(s))
+	return parts
+}
@@ -0,0 +1,56 @@
+package markup
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestParseStyle(t *testing.T) {
+	s, err := parseStyle("padding: 1px 2px 3px; margin-left: 5px; border-bottom: dotted 3px red; border-radius: 1px 2px; font-weight: 700; font-style: italic; opacity: .5")
+	if err != nil {
+		t.Fatalf("Expected the style to parse, got %v", err)
+	}
+	if s.padding != (types.EdgeInsets{Top: 1, Right: 2, Bottom: 3, Left: 2}) {
+		t.Errorf("Expected three value padding, got %+v", s.padding)
+	}
+	if s.margin != (types.EdgeInsets{Left: 5}) {
+		t.Errorf("Expected a left margin, got %+v", s.margin)
+	}
+	if s.border.Bottom != (cv.BorderSide{Width: 3, Color: color.RGBA{255, 0, 0, 255}, Style: cv.BorderStyleDotted}) || s.border.Top.Width != 0 {
+		t.Errorf("Expected a dotted bottom border, got %+v", s.border)
+	}
+	if s.radius != (cv.Radii{TopLeft: 1, TopRight: 2, BottomRight: 1, BottomLeft: 2}) {
+		t.Errorf("Expected alternating radii, got %+v", s.radius)
+	}
+	if !*s.bold || !*s.italic || s.opacity != 0.5 {
+		t.Errorf("Expected bold italic at half opacity, got %+v", s)
+	}
+}
+
+func TestParseLinearGradient(t *testing.T) {
+	g, err := parseLinearGradient("linear-gradient(to right, red, rgb(0 0 255) 80%, white)")
+	if err != nil {
+		t.Fatalf("Expected the gradient to parse, got %v", err)
+	}
+	if g.Start != [2]float64{0, 0.5} || g.End != [2]float64{1, 0.5} {
+		t.Errorf("Expected a left to right gradient, got %v to %v", g.Start, g.End)
+	}
+	if len(g.Stops) != 3 || g.Stops[1].Offset != 0.8 || g.Stops[2].Offset != 1 {
+		t.Errorf("Expected stops at 0, 0.8 and 1, got %+v", g.Stops)
+	}
+
+	g, err = parseLinearGradient("linear-gradient(red, green, blue)")
+	if err != nil {
+		t.Fatalf("Expected the gradient to parse, got %v", err)
+	}
+	if g.Start != [2]float64{0.5, 0} || g.Stops[1].Offset != 0.5 {
+		t.Errorf("Expected an evenly spread top to bottom gradient, got %+v", g)
+	}
+
+	if _, err := parseLinearGradient("linear-gradient(red)"); err == nil {
+		t.Error("Expected a single color gradient to fail")
+	}
+}
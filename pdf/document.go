@@ -0,0 +1,418 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/freetype/truetype"
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+	"golang.org/x/image/math/fixed"
+)
+
+// Common page sizes in points, one point being one canvas pixel
+var (
+	A4     = types.Size{Width: 595, Height: 842}
+	A5     = types.Size{Width: 420, Height: 595}
+	Letter = types.Size{Width: 612, Height: 792}
+	Legal  = types.Size{Width: 612, Height: 1008}
+)
+
+// Document is a PDF file being drawn page by page.
+// Shapes, text and gradients are written as vectors and the fonts used by text are
+// embedded as subsets; pixel effects such as blurs and shadows become images.
+type Document struct {
+	PageSize   types.Size
+	Margins    types.EdgeInsets
+	PixelLimit int       // Largest embedded or offscreen image in pixels, unlimited when 0, see canvas.Canvas.SetPixelLimit
+	Deadline   time.Time // Time painting must be done by, none when zero, see canvas.Canvas.SetDeadline
+
+	objects   [][]byte // Object bodies, object n is objects[n-1]
+	pages     []*page
+	resources int // Object number of the resources shared by every page
+
+	fonts     map[*truetype.Font]*font
+	fontOrder []*font
+	xobjects  map[string]int
+	states    map[string]int
+	shadings  map[string]int
+	stateKeys map[string]string // ExtGState parameters to resource name
+	finished  bool
+}
+
+// New creates an empty document whose pages have the given size and margins
+func New(pageSize types.Size, margins types.EdgeInsets) *Document {
+	d := &Document{
+		PageSize:  pageSize,
+		Margins:   margins,
+		fonts:     map[*truetype.Font]*font{},
+		xobjects:  map[string]int{},
+		states:    map[string]int{},
+		shadings:  map[string]int{},
+		stateKeys: map[string]string{},
+	}
+	d.reserve() // Catalog
+	d.reserve() // Page tree
+	d.resources = d.reserve()
+	return d
+}
+
+// ContentSize is the size of the area inside the margins of a page
+func (d *Document) ContentSize() types.Size {
+	return types.Size{
+		Width:  d.PageSize.Width - d.Margins.Horizontal(),
+		Height: d.PageSize.Height - d.Margins.Vertical(),
+	}
+}
+
+// AddPage starts a new page and returns a canvas covering its content area.
+// Drawing outside the margins is clipped.
+func (d *Document) AddPage() *cv.Canvas {
+	p := &page{doc: d, content: &bytes.Buffer{}}
+	d.pages = append(d.pages, p)
+
+	// Flip the y axis so canvas coordinates start at the top left, then clip to the margins
+	content := d.ContentSize()
+	fmt.Fprintf(p.content, "1 0 0 -1 0 %d cm\n", d.PageSize.Height)
+	fmt.Fprintf(p.content, "%d %d %d %d re W n\n", d.Margins.Left, d.Margins.Top, content.Width, content.Height)
+
+	page := cv.NewVectorCanvas(d.PageSize, p)
+	page.SetPixelLimit(d.PixelLimit)
+	page.SetDeadline(d.Deadline)
+	return page.SubCanvas(d.Margins.Left, d.Margins.Top, content, nil)
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if err := d.finish(); err != nil {
+		return 0, err
+	}
+
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	io.WriteString(out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int64, len(d.objects))
+	for i, body := range d.objects {
+		offsets[i] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n", i+1)
+		out.Write(body)
+		io.WriteString(out, "\nendobj\n")
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, xref)
+
+	if out.err != nil {
+		return out.n, out.err
+	}
+	return out.n, out.w.(*bufio.Writer).Flush()
+}
+
+// finish writes the objects that depend on the whole document: pages, fonts and resources
+func (d *Document) finish() error {
+	if d.finished {
+		return nil
+	}
+	d.finished = true
+
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	kids := make([]string, len(d.pages))
+	for i, p := range d.pages {
+		for len(p.groups) > 0 {
+			p.EndGroup()
+		}
+		contents := d.stream("", p.content.Bytes())
+		id := d.add(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources %d 0 R /Contents %d 0 R >>",
+			d.PageSize.Width, d.PageSize.Height, d.resources, contents))
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	d.set(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	d.set(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	fonts := map[string]int{}
+	for _, f := range d.fontOrder {
+		if err := d.writeFont(f); err != nil {
+			return err
+		}
+		fonts[f.name] = f.id
+	}
+
+	d.set(d.resources, fmt.Sprintf("<< /ProcSet [/PDF /Text /ImageB /ImageC] /Font %s /XObject %s /ExtGState %s /Shading %s >>",
+		dict(fonts), dict(d.xobjects), dict(d.states), dict(d.shadings)))
+	return nil
+}
+
+// writeFont embeds the used glyphs of a font as a CID keyed TrueType font
+func (d *Document) writeFont(f *font) error {
+	glyphs := f.sortedGlyphs()
+	if f.data == nil {
+		// Fonts without data fall back to a standard font that every reader has
+		d.set(f.id, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+		return nil
+	}
+
+	subset, err := subsetFont(f.data, glyphs)
+	if err != nil {
+		return err
+	}
+	fontFile := d.stream(fmt.Sprintf("/Length1 %d", len(subset)), subset)
+
+	unitsPerEm := f.font.FUnitsPerEm()
+	bounds := f.font.Bounds(fixed.Int26_6(unitsPerEm))
+	scale := func(v int) int { return v * 1000 / int(unitsPerEm) }
+	xMin, yMin, xMax, yMax := scale(int(bounds.Min.X)), scale(int(bounds.Min.Y)), scale(int(bounds.Max.X)), scale(int(bounds.Max.Y))
+
+	name := f.tag() + "+" + f.postscriptName()
+	descriptor := d.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, xMin, yMin, xMax, yMax, yMax, yMin, yMax, fontFile))
+
+	var widths strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, f.width(glyph))
+	}
+	cidFont := d.add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+		name, descriptor, strings.TrimSpace(widths.String())))
+	toUnicode := d.stream("", f.toUnicode())
+
+	d.set(f.id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cidFont, toUnicode))
+	return nil
+}
+
+// font returns the embedded font used for a text painter, registering it on first use
+func (d *Document) font(painter *cv.TextPainter) *font {
+	if f, ok := d.fonts[painter.Font]; ok {
+		return f
+	}
+	f := &font{
+		name:   "F" + strconv.Itoa(len(d.fonts)+1),
+		id:     d.reserve(),
+		font:   painter.Font,
+		data:   painter.FontData,
+		glyphs: map[truetype.Index]rune{},
+	}
+	if painter.Font == nil {
+		f.data = nil
+	}
+	d.fonts[painter.Font] = f
+	d.fontOrder = append(d.fontOrder, f)
+	return f
+}
+
+// state returns the name of a graphics state with the given fill and stroke opacity and blend mode
+func (d *Document) state(opacity float64, mode cv.BlendMode) string {
+	key := num(opacity) + " " + strconv.Itoa(int(mode))
+	if name, ok := d.stateKeys[key]; ok {
+		return name
+	}
+	name := "GS" + strconv.Itoa(len(d.states)+1)
+	blend := blendModes[mode]
+	if blend == "" {
+		blend = "Normal"
+	}
+	d.states[name] = d.add(fmt.Sprintf("<< /Type /ExtGState /ca %s /CA %s /BM /%s >>", num(opacity), num(opacity), blend))
+	d.stateKeys[key] = name
+	return name
+}
+
+// image adds an image XObject, with its alpha as a soft mask, and returns its name
+func (d *Document) image(img image.Image) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	rgb := make([]byte, 0, w*h*3)
+	alpha := make([]byte, 0, w*h)
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 255
+		}
+	}
+
+	mask := ""
+	if !opaque {
+		maskID := d.stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8", w, h), alpha)
+		mask = fmt.Sprintf(" /SMask %d 0 R", maskID)
+	}
+	id := d.stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8%s", w, h, mask), rgb)
+
+	name := "Im" + strconv.Itoa(len(d.xobjects)+1)
+	d.xobjects[name] = id
+	return name
+}
+
+// form adds a transparency group holding content and returns its name
+func (d *Document) form(content []byte) string {
+	// Group content is in page coordinates, possibly transformed past the page, so the box is generous
+	extent := 4 * max(d.PageSize.Width, d.PageSize.Height)
+	id := d.stream(fmt.Sprintf("/Type /XObject /Subtype /Form /BBox [%d %d %d %d] /Group << /S /Transparency >> /Resources %d 0 R",
+		-extent, -extent, extent, extent, d.resources), content)
+	name := "Fm" + strconv.Itoa(len(d.xobjects)+1)
+	d.xobjects[name] = id
+	return name
+}
+
+// shading adds an axial or radial shading over the unit square and returns its name
+func (d *Document) shading(shader cv.Shader) string {
+	var body string
+	switch s := shader.(type) {
+	case cv.LinearGradient:
+		body = fmt.Sprintf("<< /ShadingType 2 /ColorSpace /DeviceRGB /Coords [%s %s %s %s] /Function %s /Extend [true true] >>",
+			num(s.Start[0]), num(s.Start[1]), num(s.End[0]), num(s.End[1]), gradientFunction(cv.SRGBStops(s.Stops, s.Interpolation)))
+	case cv.RadialGradient:
+		body = fmt.Sprintf("<< /ShadingType 3 /ColorSpace /DeviceRGB /Coords [%s %s 0 %s %s %s] /Function %s /Extend [true true] >>",
+			num(s.Center[0]), num(s.Center[1]), num(s.Center[0]), num(s.Center[1]), num(s.Radius), gradientFunction(cv.SRGBStops(s.Stops, s.Interpolation)))
+	}
+	name := "Sh" + strconv.Itoa(len(d.shadings)+1)
+	d.shadings[name] = d.add(body)
+	return name
+}
+
+// gradientFunction returns a function mapping 0..1 to the colors of the stops
+func gradientFunction(stops []cv.GradientStop) string {
+	// Extend the first and last stop to cover the whole domain
+	points := append([]cv.GradientStop{}, stops...)
+	if len(points) == 0 {
+		points = []cv.GradientStop{{Offset: 0}}
+	}
+	if points[0].Offset > 0 {
+		points = append([]cv.GradientStop{{Offset: 0, Color: points[0].Color}}, points...)
+	}
+	if last := points[len(points)-1]; last.Offset < 1 {
+		points = append(points, cv.GradientStop{Offset: 1, Color: last.Color})
+	}
+
+	segment := func(a, b color.RGBA) string {
+		return fmt.Sprintf("<< /FunctionType 2 /Domain [0 1] /C0 [%s] /C1 [%s] /N 1 >>", rgb(a), rgb(b))
+	}
+	if len(points) <= 2 {
+		return segment(points[0].Color, points[len(points)-1].Color)
+	}
+
+	var functions, bounds, encode []string
+	for i := 0; i+1 < len(points); i++ {
+		functions = append(functions, segment(points[i].Color, points[i+1].Color))
+		encode = append(encode, "0 1")
+		if i > 0 {
+			bounds = append(bounds, num(min(max(points[i].Offset, 0), 1)))
+		}
+	}
+	return fmt.Sprintf("<< /FunctionType 3 /Domain [0 1] /Functions [%s] /Bounds [%s] /Encode [%s] >>",
+		strings.Join(functions, " "), strings.Join(bounds, " "), strings.Join(encode, " "))
+}
+
+// reserve allocates an object number whose body is set later
+func (d *Document) reserve() int {
+	d.objects = append(d.objects, nil)
+	return len(d.objects)
+}
+
+func (d *Document) set(id int, body string) {
+	d.objects[id-1] = []byte(body)
+}
+
+func (d *Document) add(body string) int {
+	id := d.reserve()
+	d.set(id, body)
+	return id
+}
+
+// stream adds a compressed stream object, entries being extra dictionary entries
+func (d *Document) stream(entries string, data []byte) int {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(data)
+	zw.Close()
+
+	var body bytes.Buffer
+	if entries != "" {
+		entries += " "
+	}
+	fmt.Fprintf(&body, "<< %s/Filter /FlateDecode /Length %d >>\nstream\n", entries, compressed.Len())
+	body.Write(compressed.Bytes())
+	body.WriteString("\nendstream")
+
+	id := d.reserve()
+	d.objects[id-1] = body.Bytes()
+	return id
+}
+
+// dict formats named object references as a PDF dictionary
+func dict(refs map[string]int) string {
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("<<")
+	for _, name := range names {
+		fmt.Fprintf(&b, " /%s %d 0 R", name, refs[name])
+	}
+	b.WriteString(" >>")
+	return b.String()
+}
+
+var blendModes = map[cv.BlendMode]string{
+	cv.BlendMultiply:   "Multiply",
+	cv.BlendScreen:     "Screen",
+	cv.BlendOverlay:    "Overlay",
+	cv.BlendDarken:     "Darken",
+	cv.BlendLighten:    "Lighten",
+	cv.BlendDifference: "Difference",
+}
+
+// rgb formats the color components of a premultiplied color between 0 and 1
+func rgb(c color.RGBA) string {
+	if c.A == 0 {
+		return "0 0 0"
+	}
+	component := func(v uint8) string {
+		return num(min(float64(v)/float64(c.A), 1))
+	}
+	return component(c.R) + " " + component(c.G) + " " + component(c.B)
+}
+
+// num formats a number with at most three decimals
+func num(v float64) string {
+	v = math.Round(v*1000) / 1000
+	if v == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// countingWriter counts the bytes written, for the cross reference table, and keeps the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// checkXref verifies every cross reference entry points at the start of its object
+func checkXref(t *testing.T, pdf []byte) {
+	t.Helper()
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if match == nil {
+		t.Fatal("Expected the file to end with startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("Expected the cross reference table at %d", xref)
+	}
+
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(strings.Fields(lines[2+i])[0])
+		if prefix := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(pdf[offset:], []byte(prefix)) {
+			t.Errorf("Expected object %d at offset %d", i, offset)
+		}
+	}
+}
+
+// contents returns the decompressed streams of a PDF joined together
+func contents(t *testing.T, pdf []byte) string {
+	t.Helper()
+	var out strings.Builder
+	for _, match := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(pdf, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		out.Write(data)
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func TestDocumentPages(t *testing.T) {
+	doc := New(types.Size{Width: 200, Height: 100}, types.EdgeInsetsAll(10))
+	if size := doc.ContentSize(); size != (types.Size{Width: 180, Height: 80}) {
+		t.Errorf("Expected content size 180x80, got %v", size)
+	}
+
+	first := doc.AddPage()
+	first.Rectangle(0, 0, 20, 10, color.RGBA{255, 0, 0, 255}, true)
+	second := doc.AddPage()
+	second.DrawText("Hi", 5, 5, nil)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	checkXref(t, pdf)
+
+	if !bytes.Contains(pdf, []byte("/Count 2")) {
+		t.Error("Expected two pages")
+	}
+	if !bytes.Contains(pdf, []byte("/MediaBox [0 0 200 100]")) {
+		t.Error("Expected the page size as media box")
+	}
+	if !regexp.MustCompile(`/FontName /[A-Z]{6}\+GoRegular`).Match(pdf) {
+		t.Error("Expected an embedded subset of the Go font")
+	}
+
+	streams := contents(t, pdf)
+	for _, expected := range []string{
+		"10 10 180 80 re W n",        // Clip to the margins
+		"1 0 0 rg",                   // Fill color
+		"10 10 20 10 re f",           // Rectangle moved inside the margins
+		"1 0 0 -1 15 27 Tm [<",       // Text baseline one font size below its top
+		"beginbfchar\n<002B> <0048>", // H maps back to its character
+	} {
+		if !strings.Contains(streams, expected) {
+			t.Errorf("Expected the content to contain %q, got:\n%s", expected, streams)
+		}
+	}
+}
+
+func TestDocumentGroups(t *testing.T) {
+	doc := New(types.Size{Width: 100, Height: 100}, types.EdgeInsets{})
+	canvas := doc.AddPage()
+	canvas.PaintLayer(0.5, cv.BlendMultiply, func(c *cv.Canvas) {
+		c.Rectangle(0, 0, 10, 10, color.RGBA{0, 0, 255, 255}, true)
+	})
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	checkXref(t, pdf)
+
+	if !bytes.Contains(pdf, []byte("/ca 0.5 /CA 0.5 /BM /Multiply")) {
+		t.Error("Expected a graphics state with the group's opacity and blend mode")
+	}
+	if !bytes.Contains(pdf, []byte("/Group << /S /Transparency >>")) {
+		t.Error("Expected the group to become a transparency group")
+	}
+}
+
+func TestFlowPaginates(t *testing.T) {
+	var children []render_objects.RenderObject
+	for range 5 {
+		children = append(children, &render_objects.ColoredBox{Width: 50, Height: 40, Color: color.RGBA{0, 0, 0, 255}})
+	}
+
+	pages := paginate(children, types.Size{Width: 100, Height: 100})
+	if len(pages) != 3 || len(pages[0]) != 2 || len(pages[2]) != 1 {
+		t.Errorf("Expected pages of 2, 2 and 1 children, got %d pages", len(pages))
+	}
+
+	var buf bytes.Buffer
+	if err := Flow(&buf, children, types.Size{Width: 120, Height: 120}, types.EdgeInsetsAll(10)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/Count 3")) {
+		t.Error("Expected three pages")
+	}
+}
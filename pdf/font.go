@@ -0,0 +1,321 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/math/fixed"
+)
+
+// font is a TrueType font used on some page, embedded as a subset when the document is written
+type font struct {
+	name   string // Resource name, such as F1
+	id     int    // Object number of the Type0 font
+	font   *truetype.Font
+	data   []byte
+	glyphs map[truetype.Index]rune // Used glyphs and the character each one was drawn for
+}
+
+// width returns the advance of a glyph in thousandths of the font size
+func (f *font) width(glyph truetype.Index) int {
+	unitsPerEm := f.font.FUnitsPerEm()
+	advance := f.font.HMetric(fixed.Int26_6(unitsPerEm), glyph).AdvanceWidth
+	return int(advance) * 1000 / int(unitsPerEm)
+}
+
+// kern returns the kerning between two glyphs in thousandths of the font size
+func (f *font) kern(left, right truetype.Index) int {
+	unitsPerEm := f.font.FUnitsPerEm()
+	return int(f.font.Kern(fixed.Int26_6(unitsPerEm), left, right)) * 1000 / int(unitsPerEm)
+}
+
+// tag is the six letter prefix naming a subset, derived from the glyphs it contains
+func (f *font) tag() string {
+	glyphs := f.sortedGlyphs()
+	hash := fnv.New32a()
+	for _, glyph := range glyphs {
+		binary.Write(hash, binary.BigEndian, uint16(glyph))
+	}
+	sum := hash.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	return string(tag)
+}
+
+func (f *font) sortedGlyphs() []truetype.Index {
+	glyphs := make([]truetype.Index, 0, len(f.glyphs))
+	for glyph := range f.glyphs {
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
+
+// postscriptName is the font's PostScript name, stripped of characters PDF names can't hold
+func (f *font) postscriptName() string {
+	name := f.font.Name(truetype.NameIDPostscriptName)
+	if name == "" {
+		name = f.font.Name(truetype.NameIDFontFullName)
+	}
+	name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Font"
+	}
+	return name
+}
+
+// toUnicode returns a CMap mapping the used glyphs back to their characters, so text can be copied
+func (f *font) toUnicode() []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	glyphs := f.sortedGlyphs()
+	// bfchar sections hold at most 100 entries each
+	for start := 0; start < len(glyphs); start += 100 {
+		end := min(start+100, len(glyphs))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", glyph)
+			for _, unit := range utf16Units(f.glyphs[glyph]) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+func utf16Units(r rune) []uint16 {
+	if r < 0x10000 {
+		return []uint16{uint16(r)}
+	}
+	r -= 0x10000
+	return []uint16{uint16(0xD800 + (r >> 10)), uint16(0xDC00 + (r & 0x3FF))}
+}
+
+// Tables a TrueType font embedded in a PDF needs; everything else is left out of subsets
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+var errBadFont = errors.New("pdf: malformed TrueType font")
+
+// subsetFont returns a copy of a TrueType font keeping only the outlines of the given glyphs.
+// Glyph numbers stay the same, so text encoded with the full font still works.
+func subsetFont(data []byte, glyphs []truetype.Index) ([]byte, error) {
+	tables, err := readTables(data)
+	if err != nil {
+		return nil, err
+	}
+	head, maxp, loca, glyf := tables["head"], tables["maxp"], tables["loca"], tables["glyf"]
+	if len(head) < 54 || len(maxp) < 6 || glyf == nil {
+		return nil, errBadFont
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	shortLoca := binary.BigEndian.Uint16(head[50:]) == 0
+	offsets := make([]int, numGlyphs+1)
+	for i := range offsets {
+		if shortLoca {
+			if len(loca) < 2*i+2 {
+				return nil, errBadFont
+			}
+			offsets[i] = int(binary.BigEndian.Uint16(loca[2*i:])) * 2
+		} else {
+			if len(loca) < 4*i+4 {
+				return nil, errBadFont
+			}
+			offsets[i] = int(binary.BigEndian.Uint32(loca[4*i:]))
+		}
+	}
+	glyph := func(i int) []byte {
+		if i >= numGlyphs || offsets[i] > offsets[i+1] || offsets[i+1] > len(glyf) {
+			return nil
+		}
+		return glyf[offsets[i]:offsets[i+1]]
+	}
+
+	// Keep the requested glyphs, the missing glyph and the parts of composite glyphs
+	keep := map[int]bool{0: true}
+	queue := []int{0}
+	for _, g := range glyphs {
+		if !keep[int(g)] {
+			keep[int(g)] = true
+			queue = append(queue, int(g))
+		}
+	}
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		for _, component := range compositeComponents(glyph(g)) {
+			if !keep[component] {
+				keep[component] = true
+				queue = append(queue, component)
+			}
+		}
+	}
+
+	// Rebuild glyf and a long format loca with empty entries for dropped glyphs
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, 4*(numGlyphs+1))
+	for i := range numGlyphs {
+		binary.BigEndian.PutUint32(newLoca[4*i:], uint32(newGlyf.Len()))
+		if keep[i] {
+			newGlyf.Write(glyph(i))
+			for newGlyf.Len()%4 != 0 {
+				newGlyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[4*numGlyphs:], uint32(newGlyf.Len()))
+
+	newHead := bytes.Clone(head)
+	binary.BigEndian.PutUint32(newHead[8:], 0) // checkSumAdjustment, recomputed below
+	binary.BigEndian.PutUint16(newHead[50:], 1)
+
+	subset := map[string][]byte{}
+	for _, tag := range subsetTables {
+		if table, ok := tables[tag]; ok {
+			subset[tag] = table
+		}
+	}
+	subset["head"] = newHead
+	subset["loca"] = newLoca
+	subset["glyf"] = newGlyf.Bytes()
+	return writeFont(data[:4], subset), nil
+}
+
+// readTables returns the tables of a TrueType font by tag
+func readTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, errBadFont
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*numTables {
+		return nil, errBadFont
+	}
+	tables := map[string][]byte{}
+	for i := range numTables {
+		record := data[12+16*i:]
+		offset := int(binary.BigEndian.Uint32(record[8:]))
+		length := int(binary.BigEndian.Uint32(record[12:]))
+		if offset+length > len(data) {
+			return nil, errBadFont
+		}
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// compositeComponents returns the glyphs a composite glyph is built from
+func compositeComponents(glyph []byte) []int {
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+
+	const (
+		argsAreWords    = 0x0001
+		hasScale        = 0x0008
+		moreComponents  = 0x0020
+		hasXYScale      = 0x0040
+		hasTwoByTwo     = 0x0080
+		componentHeader = 4
+	)
+	var components []int
+	for pos := 10; pos+componentHeader <= len(glyph); {
+		flags := binary.BigEndian.Uint16(glyph[pos:])
+		components = append(components, int(binary.BigEndian.Uint16(glyph[pos+2:])))
+		pos += componentHeader
+		if flags&argsAreWords != 0 {
+			pos += 4
+		} else {
+			pos += 2
+		}
+		switch {
+		case flags&hasScale != 0:
+			pos += 2
+		case flags&hasXYScale != 0:
+			pos += 4
+		case flags&hasTwoByTwo != 0:
+			pos += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return components
+}
+
+// writeFont assembles a TrueType file from its tables
+func writeFont(version []byte, tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	numTables := len(tags)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= numTables {
+		entrySelector++
+	}
+	searchRange := (1 << entrySelector) * 16
+
+	var out bytes.Buffer
+	out.Write(version)
+	binary.Write(&out, binary.BigEndian, []uint16{
+		uint16(numTables), uint16(searchRange), uint16(entrySelector), uint16(numTables*16 - searchRange),
+	})
+
+	offset := 12 + 16*numTables
+	headOffset := 0
+	for _, tag := range tags {
+		table := tables[tag]
+		if tag == "head" {
+			headOffset = offset
+		}
+		out.WriteString(tag)
+		binary.Write(&out, binary.BigEndian, []uint32{checksum(table), uint32(offset), uint32(len(table))})
+		offset += (len(table) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		out.Write(tables[tag])
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	font := out.Bytes()
+	if headOffset > 0 {
+		binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-checksum(font))
+	}
+	return font
+}
+
+// checksum is the TrueType table checksum, the sum of the data as big endian uint32s
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
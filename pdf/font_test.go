@@ -0,0 +1,62 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	imagefont "golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestSubsetFont(t *testing.T) {
+	full, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, z := full.Index('a'), full.Index('z')
+
+	data, err := subsetFont(goregular.TTF, []truetype.Index{a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) >= len(goregular.TTF)/2 {
+		t.Errorf("Expected the subset to be much smaller than the font, got %d of %d bytes", len(data), len(goregular.TTF))
+	}
+	if sum := checksum(data); sum != 0xB1B0AFBA {
+		t.Errorf("Expected the whole font checksum to be 0xB1B0AFBA, got %#x", sum)
+	}
+
+	tables, err := readTables(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tables["cmap"]; ok {
+		t.Error("Expected the character map to be left out of the subset")
+	}
+
+	// Reading the outlines needs a character map, so graft the original one back in
+	tables["cmap"] = mustTables(t, goregular.TTF)["cmap"]
+	tables["name"] = mustTables(t, goregular.TTF)["name"]
+	subset, err := truetype.Parse(writeFont(data[:4], tables))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kept, dropped truetype.GlyphBuf
+	scale := fixed.Int26_6(subset.FUnitsPerEm())
+	if err := kept.Load(subset, scale, a, imagefont.HintingNone); err != nil || len(kept.Points) == 0 {
+		t.Errorf("Expected the outline of 'a' to be kept, got %d points (%v)", len(kept.Points), err)
+	}
+	if err := dropped.Load(subset, scale, z, imagefont.HintingNone); err != nil || len(dropped.Points) != 0 {
+		t.Errorf("Expected the outline of 'z' to be dropped, got %d points (%v)", len(dropped.Points), err)
+	}
+}
+
+func mustTables(t *testing.T, data []byte) map[string][]byte {
+	tables, err := readTables(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tables
+}
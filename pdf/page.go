@@ -0,0 +1,368 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Bezier control point distance approximating a quarter circle
+const kappa = 0.5522847498
+
+// page is the drawing backend of one page of a document
+type page struct {
+	doc     *Document
+	content *bytes.Buffer
+	groups  []group
+}
+
+// group is an open group whose content is collected apart from the rest of the page
+type group struct {
+	parent    *bytes.Buffer
+	transform cv.Matrix
+	opacity   float64
+	mode      cv.BlendMode
+}
+
+func (p *page) printf(format string, args ...any) {
+	fmt.Fprintf(p.content, format, args...)
+}
+
+// paint starts a graphics state painting with col, as fill and stroke color
+func (p *page) paint(col color.RGBA) {
+	p.printf("q\n")
+	if col.A < 255 {
+		p.printf("/%s gs\n", p.doc.state(float64(col.A)/255, cv.BlendNormal))
+	}
+	c := rgb(col)
+	p.printf("%s rg %s RG\n", c, c)
+}
+
+func (p *page) Rectangle(x, y, w, h int, color color.RGBA, fill bool) {
+	if w <= 0 || h <= 0 || color.A == 0 {
+		return
+	}
+	p.paint(color)
+	if fill {
+		p.printf("%d %d %d %d re f\nQ\n", x, y, w, h)
+		return
+	}
+	// Outlines are one pixel wide, centered on the outermost pixels
+	p.printf("1 w %s %s %d %d re S\nQ\n", num(float64(x)+0.5), num(float64(y)+0.5), w-1, h-1)
+}
+
+func (p *page) Circle(x, y, r int, color color.RGBA, fill bool) {
+	if color.A == 0 {
+		return
+	}
+	p.paint(color)
+	cx, cy := float64(x)+0.5, float64(y)+0.5
+	if fill {
+		p.ellipse(cx, cy, float64(r)+0.5)
+		p.printf("f\nQ\n")
+		return
+	}
+	p.printf("1 w\n")
+	p.ellipse(cx, cy, float64(r))
+	p.printf("S\nQ\n")
+}
+
+// ellipse adds a circle made of four Bezier curves to the current path
+func (p *page) ellipse(cx, cy, r float64) {
+	k := r * kappa
+	p.printf("%s %s m\n", num(cx+r), num(cy))
+	p.printf("%s %s %s %s %s %s c\n", num(cx+r), num(cy+k), num(cx+k), num(cy+r), num(cx), num(cy+r))
+	p.printf("%s %s %s %s %s %s c\n", num(cx-k), num(cy+r), num(cx-r), num(cy+k), num(cx-r), num(cy))
+	p.printf("%s %s %s %s %s %s c\n", num(cx-r), num(cy-k), num(cx-k), num(cy-r), num(cx), num(cy-r))
+	p.printf("%s %s %s %s %s %s c h\n", num(cx+k), num(cy-r), num(cx+r), num(cy-k), num(cx+r), num(cy))
+}
+
+func (p *page) Line(x1, y1, x2, y2 int, color color.RGBA, width int) {
+	if color.A == 0 {
+		return
+	}
+	p.paint(color)
+	p.printf("%d w 1 J %s %s m %s %s l S\nQ\n", max(width, 1),
+		num(float64(x1)+0.5), num(float64(y1)+0.5), num(float64(x2)+0.5), num(float64(y2)+0.5))
+}
+
+func (p *page) Polygon(points [][2]int, color color.RGBA, fill bool) {
+	if color.A == 0 {
+		return
+	}
+	// Outlines run through pixel centers, fills cover whole pixels
+	shift := 0.5
+	if !fill {
+		shift = 0
+	}
+	p.paint(color)
+	for i, point := range points {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		p.printf("%s %s %s\n", num(float64(point[0])+shift), num(float64(point[1])+shift), op)
+	}
+	if fill {
+		p.printf("h f\nQ\n")
+	} else {
+		p.printf("1 w s\nQ\n")
+	}
+}
+
+func (p *page) Text(text string, x, y int, painter *cv.TextPainter) {
+	col := color.RGBAModel.Convert(painter.TextColor).(color.RGBA)
+	if text == "" || col.A == 0 {
+		return
+	}
+	f := p.doc.font(painter)
+
+	var shown string
+	if f.data == nil {
+		shown = "(" + latin1(text) + ") Tj"
+	} else {
+		// Glyph numbers as two byte codes, with kerning adjustments between them
+		var b strings.Builder
+		b.WriteString("[<")
+		prev, hasPrev := truetype.Index(0), false
+		for _, r := range text {
+			glyph := f.font.Index(r)
+			if hasPrev {
+				if kern := f.kern(prev, glyph); kern != 0 {
+					fmt.Fprintf(&b, "> %d <", -kern)
+				}
+			}
+			if _, ok := f.glyphs[glyph]; !ok {
+				f.glyphs[glyph] = r
+			}
+			fmt.Fprintf(&b, "%04X", glyph)
+			prev, hasPrev = glyph, true
+		}
+		b.WriteString(">] TJ")
+		shown = b.String()
+	}
+
+	// The text matrix flips the glyphs back upright; DrawText places the baseline one font size below y
+	p.paint(col)
+	p.printf("BT /%s %s Tf 1 0 0 -1 %d %s Tm %s ET\nQ\n", f.name, num(painter.FontSize), x, num(float64(y)+painter.FontSize), shown)
+}
+
+// latin1 escapes text for a literal string in a standard font, replacing characters it can't show
+func latin1(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < ' ' || r > 0xff:
+			b.WriteByte('?')
+		case r > '~':
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (p *page) RoundedRect(x, y, w, h int, radii cv.Radii, shader cv.Shader) {
+	switch s := shader.(type) {
+	case cv.SolidColor:
+		if s.Color.A == 0 {
+			return
+		}
+		p.paint(s.Color)
+		p.roundedPath(float64(x), float64(y), float64(w), float64(h), radii)
+		p.printf("f\nQ\n")
+		return
+	case cv.LinearGradient:
+		if opaque(s.Stops) {
+			p.shade(x, y, w, h, radii, shader)
+			return
+		}
+	case cv.RadialGradient:
+		if opaque(s.Stops) {
+			p.shade(x, y, w, h, radii, shader)
+			return
+		}
+	}
+
+	// Translucent gradients and other shaders are embedded as pixels
+	p.rasterize(x, y, w, h, 0, func(c *cv.Canvas, x, y int) {
+		c.FillRoundedRect(x, y, w, h, radii, shader)
+	})
+}
+
+// shade fills a rounded rectangle with a gradient shading stretched over its box
+func (p *page) shade(x, y, w, h int, radii cv.Radii, shader cv.Shader) {
+	name := p.doc.shading(shader)
+	p.printf("q\n")
+	p.roundedPath(float64(x), float64(y), float64(w), float64(h), radii)
+	p.printf("W n %d 0 0 %d %d %d cm /%s sh\nQ\n", w, h, x, y, name)
+}
+
+func opaque(stops []cv.GradientStop) bool {
+	for _, stop := range stops {
+		if stop.Color.A < 255 {
+			return false
+		}
+	}
+	return true
+}
+
+// roundedPath adds a rounded rectangle to the current path
+func (p *page) roundedPath(x, y, w, h float64, radii cv.Radii) {
+	if radii.IsZero() {
+		p.printf("%s %s %s %s re\n", num(x), num(y), num(w), num(h))
+		return
+	}
+
+	// Corners that don't fit are scaled down together, like the raster renderer does
+	tl, tr, br, bl := float64(radii.TopLeft), float64(radii.TopRight), float64(radii.BottomRight), float64(radii.BottomLeft)
+	scale := 1.0
+	for _, pair := range [][3]float64{{tl, tr, w}, {bl, br, w}, {tl, bl, h}, {tr, br, h}} {
+		if sum := pair[0] + pair[1]; sum > pair[2] {
+			scale = min(scale, pair[2]/sum)
+		}
+	}
+	tl, tr, br, bl = tl*scale, tr*scale, br*scale, bl*scale
+
+	p.printf("%s %s m\n", num(x+tl), num(y))
+	p.printf("%s %s l\n", num(x+w-tr), num(y))
+	p.corner(x+w-tr, y, x+w, y+tr, tr, true)
+	p.printf("%s %s l\n", num(x+w), num(y+h-br))
+	p.corner(x+w, y+h-br, x+w-br, y+h, br, false)
+	p.printf("%s %s l\n", num(x+bl), num(y+h))
+	p.corner(x+bl, y+h, x, y+h-bl, bl, true)
+	p.printf("%s %s l\n", num(x), num(y+tl))
+	p.corner(x, y+tl, x+tl, y, tl, false)
+	p.printf("h\n")
+}
+
+// corner adds a quarter circle from (x1, y1) to (x2, y2); horizontal tells
+// whether the curve leaves its start point horizontally
+func (p *page) corner(x1, y1, x2, y2, r float64, horizontal bool) {
+	if r <= 0 {
+		return
+	}
+	k := r * kappa
+	dx, dy := sign(x2-x1), sign(y2-y1)
+	if horizontal {
+		p.printf("%s %s %s %s %s %s c\n", num(x1+dx*k), num(y1), num(x2), num(y2-dy*k), num(x2), num(y2))
+	} else {
+		p.printf("%s %s %s %s %s %s c\n", num(x1), num(y1+dy*k), num(x2-dx*k), num(y2), num(x2), num(y2))
+	}
+}
+
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+func (p *page) RoundedBorder(x, y, w, h int, radii cv.Radii, sides cv.BorderSides) {
+	side := sides.Top
+	if sides != cv.UniformBorder(side) || side.Style == cv.BorderStyleNone || side.Width <= 0 {
+		// Mixed sides are drawn pixel by pixel
+		p.rasterize(x, y, w, h, 0, func(c *cv.Canvas, x, y int) {
+			c.RoundedBorder(x, y, w, h, radii, sides)
+		})
+		return
+	}
+	if side.Color.A == 0 {
+		return
+	}
+
+	// A uniform border is a stroke centered half its width inside the box
+	half := float64(side.Width) / 2
+	inner := cv.Radii{
+		TopLeft:     max(radii.TopLeft-side.Width/2, 0),
+		TopRight:    max(radii.TopRight-side.Width/2, 0),
+		BottomRight: max(radii.BottomRight-side.Width/2, 0),
+		BottomLeft:  max(radii.BottomLeft-side.Width/2, 0),
+	}
+	p.paint(side.Color)
+	p.printf("%d w\n", side.Width)
+	if dash, gap := side.Style.Dashes(side.Width); dash > 0 {
+		p.printf("[%d %d] 0 d\n", dash, gap)
+	}
+	p.roundedPath(float64(x)+half, float64(y)+half, float64(w)-2*half, float64(h)-2*half, inner)
+	p.printf("S\nQ\n")
+}
+
+func (p *page) BoxShadow(x, y, w, h int, radii cv.Radii, shadow cv.BoxShadow) {
+	// Shadows are blurred pixels; leave room for the blur, spread and offset around the box
+	margin := 0
+	if !shadow.Inset {
+		margin = 2*max(shadow.Blur, 0) + max(shadow.Spread, 0) + max(abs(shadow.OffsetX), abs(shadow.OffsetY)) + 2
+	}
+	p.rasterize(x, y, w, h, margin, func(c *cv.Canvas, x, y int) {
+		c.DrawBoxShadow(x, y, w, h, radii, shadow)
+	})
+}
+
+func (p *page) Image(x, y int, size types.Size, img image.Image) {
+	if size.Width <= 0 || size.Height <= 0 || img.Bounds().Empty() {
+		return
+	}
+	name := p.doc.image(img)
+	// Images fill the unit square upwards, so flip them back into the page's y down space
+	p.printf("q %d 0 0 %d %d %d cm /%s Do Q\n", size.Width, -size.Height, x, y+size.Height, name)
+}
+
+func (p *page) BeginGroup(transform cv.Matrix, opacity float64, mode cv.BlendMode) {
+	p.groups = append(p.groups, group{parent: p.content, transform: transform, opacity: opacity, mode: mode})
+	p.content = &bytes.Buffer{}
+}
+
+func (p *page) EndGroup() {
+	if len(p.groups) == 0 {
+		return
+	}
+	g := p.groups[len(p.groups)-1]
+	p.groups = p.groups[:len(p.groups)-1]
+	content := p.content.Bytes()
+	p.content = g.parent
+
+	p.printf("q\n")
+	if m := g.transform; !m.IsIdentity() {
+		p.printf("%s %s %s %s %s %s cm\n", num(m.A), num(m.B), num(m.C), num(m.D), num(m.E), num(m.F))
+	}
+	if g.opacity >= 1 && blendModes[g.mode] == "" {
+		p.content.Write(content)
+		p.printf("Q\n")
+		return
+	}
+
+	// Opacity and blending apply to the group as a whole, so it becomes a transparency group
+	p.printf("/%s gs /%s Do\nQ\n", p.doc.state(min(max(g.opacity, 0), 1), g.mode), p.doc.form(content))
+}
+
+// rasterize paints an area of the page into pixels and embeds them as an image.
+// paint receives the position of the area inside the temporary canvas.
+func (p *page) rasterize(x, y, w, h, margin int, paint func(c *cv.Canvas, x, y int)) {
+	size := types.Size{Width: w + 2*margin, Height: h + 2*margin}
+	if limit := p.doc.PixelLimit; limit > 0 && float64(size.Width)*float64(size.Height) > float64(limit) {
+		panic(&cv.LimitError{Size: size, PixelRatio: 1, Limit: limit})
+	}
+	c := cv.NewCanvas(size, true)
+	c.SetPixelLimit(p.doc.PixelLimit)
+	c.SetDeadline(p.doc.Deadline)
+	paint(c, margin, margin)
+	p.Image(x-margin, y-margin, size, c.Img)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
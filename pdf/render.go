@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"io"
+
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Render paints each root onto its own page and writes the document to w
+func Render(w io.Writer, pages []render_objects.RenderObject, pageSize types.Size, margins types.EdgeInsets) error {
+	doc := New(pageSize, margins)
+	for _, root := range pages {
+		root.Paint(doc.AddPage())
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// Flow stacks children from top to bottom like a Column, starting a new page whenever
+// the next child doesn't fit on the current one, and writes the document to w.
+// Children taller than a page get a page of their own and are clipped.
+func Flow(w io.Writer, children []render_objects.RenderObject, pageSize types.Size, margins types.EdgeInsets) error {
+	doc := New(pageSize, margins)
+	for _, page := range paginate(children, doc.ContentSize()) {
+		column := &render_objects.Column{Children: page}
+		column.Paint(doc.AddPage())
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// paginate splits children into runs whose total height fits in the content area
+func paginate(children []render_objects.RenderObject, content types.Size) [][]render_objects.RenderObject {
+	var pages [][]render_objects.RenderObject
+	var current []render_objects.RenderObject
+	height := 0
+	for _, child := range children {
+		childHeight := child.Size(content).Height
+		if len(current) > 0 && height+childHeight > content.Height {
+			pages = append(pages, current)
+			current, height = nil, 0
+		}
+		current = append(current, child)
+		height += childHeight
+	}
+	if len(current) > 0 {
+		pages = append(pages, current)
+	}
+	return pages
+}
@@ -0,0 +1,106 @@
+package preview
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 24px; background: #f4f4f6; color: #222; }
+h2 { font-size: 16px; margin: 32px 0 8px; }
+.sizes { display: flex; flex-wrap: wrap; gap: 24px; align-items: flex-start; }
+.size { font-size: 12px; color: #666; margin-bottom: 4px; }
+img { display: block; background: repeating-conic-gradient(#ddd 0 25%, #fff 0 50%) 0 0 / 16px 16px; box-shadow: 0 1px 4px rgba(0, 0, 0, .2); }
+pre { background: #fff0f0; color: #a00; border: 1px solid #e99; padding: 12px; overflow: auto; max-width: 900px; font-size: 12px; }
+.empty { color: #666; }
+.toggle { font-size: 13px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="toggle">{{if .Debug}}<a href="/">Hide layout</a>{{else}}<a href="/?debug">Show layout</a>{{end}}</p>
+{{range .Entries}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+<div class="sizes">
+{{range .Previews}}
+<div>
+<div class="size">{{.Width}}×{{.Height}} · {{.Duration}}</div>
+{{if .Panic}}<pre>{{.Panic}}</pre>{{else}}<img src="{{.Src}}" width="{{.Width}}" height="{{.Height}}" alt="{{.Width}}×{{.Height}}">{{end}}
+</div>
+{{end}}
+</div>
+{{else}}
+<p class="empty">Nothing registered yet.</p>
+{{end}}
+<script>
+let instance = null;
+const events = new EventSource("/events");
+events.addEventListener("hello", e => {
+  // A different instance means the program was restarted
+  if (instance !== null && instance !== e.data) location.reload();
+  instance = e.data;
+});
+events.addEventListener("change", () => location.reload());
+</script>
+</body>
+</html>
+`))
+
+type pagePreview struct {
+	Width, Height int
+	Src           string
+	Panic         string
+	Duration      string
+}
+
+type pageEntry struct {
+	Name     string
+	Previews []pagePreview
+}
+
+// handlePage renders every preview and lists them, failed ones with their panic
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	entries := append([]*entry(nil), s.entries...)
+	version := s.version
+	s.mu.Unlock()
+
+	debug := r.URL.Query().Has("debug")
+	data := struct {
+		Title   string
+		Debug   bool
+		Entries []pageEntry
+	}{Title: s.options.Title, Debug: debug}
+	for _, e := range entries {
+		page := pageEntry{Name: e.name}
+		for _, size := range e.sizes {
+			rendered := s.render(e, size, debug)
+			src := fmt.Sprintf("/image/%s/%dx%d?v=%d", url.PathEscape(e.name), size.Width, size.Height, version)
+			if debug {
+				src += "&debug"
+			}
+			page.Previews = append(page.Previews, pagePreview{
+				Width:    size.Width,
+				Height:   size.Height,
+				Src:      src,
+				Panic:    rendered.panic,
+				Duration: rendered.duration.Round(10 * time.Microsecond).String(),
+			})
+		}
+		data.Entries = append(data.Entries, page)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,279 @@
+// Package preview serves render trees under development to a browser.
+//
+// A program registers named factories with the sizes to show them at and starts the
+// server. The page renders every factory on demand, shows panics such as out of bounds
+// painting inline, and reloads itself when the program calls Changed or restarts.
+// Opening it as /?debug draws the layout of every tree over its image, see inspect.Overlay:
+//
+//	p := preview.New(nil)
+//	p.Register("card", func() render_objects.RenderObject { return card(user) },
+//		types.Size{Width: 400, Height: 180}, types.Size{Width: 320, Height: 180})
+//	log.Fatal(p.ListenAndServe("localhost:8090"))
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/inspect"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Factory builds a fresh render tree every time a preview is rendered
+type Factory func() render_objects.RenderObject
+
+// DefaultSize is used for factories registered without a size
+var DefaultSize = types.Size{Width: 800, Height: 600}
+
+// Options configure a Server. Zero values take the defaults.
+type Options struct {
+	PixelRatio float64 // Device pixels per logical pixel, 1 by default
+	Title      string  // Title of the page, "Preview" by default
+}
+
+type entry struct {
+	name    string
+	factory Factory
+	sizes   []types.Size
+}
+
+// rendering is the outcome of rendering a factory at one size
+type rendering struct {
+	png      []byte
+	panic    string // Panic message and stack, empty on success
+	duration time.Duration
+}
+
+// Server renders registered factories for the browser. It is an http.Handler.
+type Server struct {
+	options  Options
+	instance string // Tells pages a restarted program apart from the one they were loaded from
+
+	mu         sync.Mutex
+	entries    []*entry
+	version    int
+	renderings map[string]*rendering // By name, size and version
+	clients    map[chan int]struct{}
+
+	mux *http.ServeMux
+}
+
+// New creates a preview server. A nil options uses the defaults.
+func New(options *Options) *Server {
+	s := &Server{
+		instance:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		renderings: map[string]*rendering{},
+		clients:    map[chan int]struct{}{},
+	}
+	if options != nil {
+		s.options = *options
+	}
+	if s.options.PixelRatio <= 0 {
+		s.options.PixelRatio = 1
+	}
+	if s.options.Title == "" {
+		s.options.Title = "Preview"
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /{$}", s.handlePage)
+	s.mux.HandleFunc("GET /image/{name}/{size}", s.handleImage)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+	return s
+}
+
+// Register adds a factory shown at each of the given sizes, replacing any factory of the same name
+func (s *Server) Register(name string, factory Factory, sizes ...types.Size) {
+	if len(sizes) == 0 {
+		sizes = []types.Size{DefaultSize}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, e := range s.entries {
+		if e.name == name {
+			s.entries[i] = &entry{name, factory, sizes}
+			s.changed()
+			return
+		}
+	}
+	s.entries = append(s.entries, &entry{name, factory, sizes})
+	s.changed()
+}
+
+// Changed drops the rendered previews and tells open pages to reload.
+// Call it whenever something the factories depend on has changed.
+func (s *Server) Changed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changed()
+}
+
+func (s *Server) changed() {
+	s.version++
+	clear(s.renderings)
+	for client := range s.clients {
+		// Clients that haven't read the last version only need the newest one
+		select {
+		case <-client:
+		default:
+		}
+		client <- s.version
+	}
+}
+
+// ChangeOnSignal calls Changed whenever the process receives one of the signals,
+// so a file watcher can trigger a refresh with kill -HUP. The returned function stops it.
+func (s *Server) ChangeOnSignal(signals ...os.Signal) (stop func()) {
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(received, signals...)
+	go func() {
+		for {
+			select {
+			case <-received:
+				s.Changed()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(received)
+		close(done)
+	}
+}
+
+// ListenAndServe serves the previews on addr until the listener fails
+func (s *Server) ListenAndServe(addr string) error {
+	log.Printf("preview: serving on http://%s", addr)
+	return http.ListenAndServe(addr, s)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// render renders a factory at a size, once per version, optionally with the debug overlay
+func (s *Server) render(e *entry, size types.Size, overlay bool) *rendering {
+	s.mu.Lock()
+	key := fmt.Sprintf("%s/%dx%d/%d/%t", e.name, size.Width, size.Height, s.version, overlay)
+	if r, ok := s.renderings[key]; ok {
+		s.mu.Unlock()
+		return r
+	}
+	version := s.version
+	s.mu.Unlock()
+
+	r := renderFactory(e.factory, size, s.options.PixelRatio, overlay)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Renderings of an older version would show stale content
+	if version == s.version {
+		s.renderings[key] = r
+	}
+	return r
+}
+
+// renderFactory builds and paints a tree, turning panics into a message with the stack
+func renderFactory(factory Factory, size types.Size, pixelRatio float64, overlay bool) (r *rendering) {
+	r = &rendering{}
+	start := time.Now()
+	defer func() {
+		r.duration = time.Since(start)
+		if p := recover(); p != nil {
+			r.png = nil
+			r.panic = fmt.Sprintf("panic: %v\n\n%s", p, debug.Stack())
+		}
+	}()
+
+	root := factory()
+	if overlay {
+		root = &inspect.Overlay{Child: root, Labels: true}
+	}
+	canvas := cv.NewScaledCanvas(size, pixelRatio, false)
+	root.Paint(canvas)
+
+	var buf bytes.Buffer
+	if err := canvas.Encode(&buf, cv.FormatPNG, nil); err != nil {
+		panic(err)
+	}
+	r.png = buf.Bytes()
+	return r
+}
+
+// lookup finds a registered factory and one of its sizes
+func (s *Server) lookup(name, size string) (*entry, types.Size, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.name != name {
+			continue
+		}
+		for _, sz := range e.sizes {
+			if fmt.Sprintf("%dx%d", sz.Width, sz.Height) == size {
+				return e, sz, true
+			}
+		}
+	}
+	return nil, types.Size{}, false
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	e, size, ok := s.lookup(r.PathValue("name"), r.PathValue("size"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rendered := s.render(e, size, r.URL.Query().Has("debug"))
+	if rendered.panic != "" {
+		http.Error(w, rendered.panic, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(rendered.png)
+}
+
+// handleEvents streams a change event for every new version, and the server instance on connect
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	client := make(chan int, 1)
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	fmt.Fprintf(w, "retry: 1000\nevent: hello\ndata: %s\n\n", s.instance)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case version := <-client:
+			fmt.Fprintf(w, "event: change\ndata: %d\n\n", version)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package preview
+
+import (
+	"bufio"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func get(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestPreviewPage(t *testing.T) {
+	p := New(&Options{Title: "Cards"})
+	p.Register("box", func() render_objects.RenderObject {
+		return &render_objects.ColoredBox{Width: 10, Height: 10, Color: cv.Red}
+	}, types.Size{Width: 40, Height: 20}, types.Size{Width: 30, Height: 30})
+
+	rec := get(t, p, "/")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"<title>Cards</title>", `src="/image/box/40x20?v=1"`, `src="/image/box/30x30?v=1"`, "EventSource"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the page to contain %q", want)
+		}
+	}
+
+	rec = get(t, p, "/image/box/40x20")
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 40 || bounds.Dy() != 20 {
+		t.Errorf("Expected a 40x20 image, got %v", bounds)
+	}
+
+	if rec := get(t, p, "/image/box/50x50"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected unregistered sizes to be missing, got %d", rec.Code)
+	}
+}
+
+func TestPreviewDebug(t *testing.T) {
+	p := New(nil)
+	p.Register("box", func() render_objects.RenderObject {
+		return &render_objects.ColoredBox{Width: 10, Height: 10, Color: cv.Red}
+	}, types.Size{Width: 40, Height: 20})
+
+	body := get(t, p, "/?debug").Body.String()
+	if !strings.Contains(body, `src="/image/box/40x20?v=1&amp;debug"`) {
+		t.Errorf("Expected the images of a debug page to show the overlay, got %s", body)
+	}
+
+	plain, err := png.Decode(get(t, p, "/image/box/40x20").Body)
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	debug, err := png.Decode(get(t, p, "/image/box/40x20?debug").Body)
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if plain.At(0, 0) == debug.At(0, 0) {
+		t.Errorf("Expected the overlay to outline the box, got %v in both images", plain.At(0, 0))
+	}
+}
+
+// outOfBounds paints past the edge of its canvas
+type outOfBounds struct{}
+
+func (outOfBounds) Paint(canvas *cv.Canvas) {
+	canvas.DrawCanvas(cv.NewCanvas(types.Size{Width: 5, Height: 5}, false), canvas.Size.Width, 0)
+}
+
+func (outOfBounds) Size(parentSize types.Size) types.Size {
+	return parentSize
+}
+
+func TestPreviewPanic(t *testing.T) {
+	p := New(nil)
+	p.Register("broken", func() render_objects.RenderObject { return outOfBounds{} }, types.Size{Width: 10, Height: 10})
+	p.Register("fine", func() render_objects.RenderObject {
+		return &render_objects.ColoredBox{Width: 10, Height: 10, Color: cv.Blue}
+	})
+
+	rec := get(t, p, "/")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the page to render despite the panic, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, cv.ErrOutOfBounds.Error()) {
+		t.Error("Expected the panic message on the page")
+	}
+	if !strings.Contains(body, `src="/image/fine/800x600`) {
+		t.Error("Expected the working preview at the default size")
+	}
+
+	if rec := get(t, p, "/image/broken/10x10"); rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected the broken image to fail, got %d", rec.Code)
+	}
+}
+
+func TestPreviewChanged(t *testing.T) {
+	renders := 0
+	p := New(nil)
+	p.Register("counter", func() render_objects.RenderObject {
+		renders++
+		return &render_objects.ColoredBox{Width: 1, Height: 1, Color: cv.Red}
+	}, types.Size{Width: 4, Height: 4})
+
+	get(t, p, "/image/counter/4x4")
+	get(t, p, "/image/counter/4x4")
+	if renders != 1 {
+		t.Errorf("Expected one render before a change, got %d", renders)
+	}
+
+	server := httptest.NewServer(p)
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", resp.Header.Get("Content-Type"))
+	}
+
+	events := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				events <- name
+			}
+		}
+		close(events)
+	}()
+
+	expectEvent(t, events, "hello")
+	p.Changed()
+	expectEvent(t, events, "change")
+
+	get(t, p, "/image/counter/4x4")
+	if renders != 2 {
+		t.Errorf("Expected a new render after the change, got %d", renders)
+	}
+}
+
+func expectEvent(t *testing.T, events <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-events:
+		if got != want {
+			t.Errorf("Expected a %s event, got %s", want, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected a %s event", want)
+	}
+}
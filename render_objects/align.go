@@ -1,6 +1,8 @@
 package render_objects
 
 import (
+	"image"
+
 	cv "github.com/hvuhsg/render/canvas"
 	types "github.com/hvuhsg/render/types"
 )
@@ -26,42 +28,56 @@ const (
 
 func (a *Align) Paint(canvas *cv.Canvas) {
 	childSize := a.Child.Size(canvas.Size)
+	x, y := a.Align.offset(canvas.Size, childSize)
+
+	childCanvas := canvas.SubCanvas(x, y, childSize, nil)
+	PaintChild(a.Child, childCanvas)
+}
 
-	var x, y int
-	switch a.Align {
+// offset returns the position of a child of childSize aligned inside a container of containerSize
+func (align AlignType) offset(containerSize, childSize types.Size) (x, y int) {
+	switch align {
 	case AlignTopLeft:
 		x = 0
 		y = 0
 	case AlignTopCenter:
-		x = (canvas.Size.Width - childSize.Width) / 2
+		x = (containerSize.Width - childSize.Width) / 2
 		y = 0
 	case AlignTopRight:
-		x = canvas.Size.Width - childSize.Width
+		x = containerSize.Width - childSize.Width
 		y = 0
 	case AlignLeftCenter:
 		x = 0
-		y = (canvas.Size.Height - childSize.Height) / 2
+		y = (containerSize.Height - childSize.Height) / 2
 	case AlignRightCenter:
-		x = canvas.Size.Width - childSize.Width
-		y = (canvas.Size.Height - childSize.Height) / 2
+		x = containerSize.Width - childSize.Width
+		y = (containerSize.Height - childSize.Height) / 2
 	case AlignBottomLeft:
 		x = 0
-		y = canvas.Size.Height - childSize.Height
+		y = containerSize.Height - childSize.Height
 	case AlignBottomCenter:
-		x = (canvas.Size.Width - childSize.Width) / 2
-		y = canvas.Size.Height - childSize.Height
+		x = (containerSize.Width - childSize.Width) / 2
+		y = containerSize.Height - childSize.Height
 	case AlignBottomRight:
-		x = canvas.Size.Width - childSize.Width
-		y = canvas.Size.Height - childSize.Height
+		x = containerSize.Width - childSize.Width
+		y = containerSize.Height - childSize.Height
 	case AlignCenter:
-		x = (canvas.Size.Width - childSize.Width) / 2
-		y = (canvas.Size.Height - childSize.Height) / 2
+		x = (containerSize.Width - childSize.Width) / 2
+		y = (containerSize.Height - childSize.Height) / 2
 	}
 
-	childCanvas := canvas.SubCanvas(x, y, childSize, nil)
-	a.Child.Paint(childCanvas)
+	return x, y
 }
 
 func (a *Align) Size(parentSize types.Size) types.Size {
 	return a.Child.Size(parentSize)
 }
+
+func (a *Align) MinIntrinsicWidth(height int) int { return MinIntrinsicWidth(a.Child, height) }
+func (a *Align) MaxIntrinsicWidth(height int) int { return MaxIntrinsicWidth(a.Child, height) }
+func (a *Align) MinIntrinsicHeight(width int) int { return MinIntrinsicHeight(a.Child, width) }
+func (a *Align) MaxIntrinsicHeight(width int) int { return MaxIntrinsicHeight(a.Child, width) }
+
+func (a *Align) Guides(size types.Size) Guides {
+	return Guides{Anchors: []image.Point{a.Align.anchor(size)}}
+}
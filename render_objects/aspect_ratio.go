@@ -0,0 +1,52 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// AspectRatio sizes its child to the largest box with the given width to height ratio that fits the parent
+type AspectRatio struct {
+	Child RenderObject
+	Ratio float64
+}
+
+func (a *AspectRatio) Paint(canvas *cv.Canvas) {
+	PaintChild(a.Child, canvas)
+}
+
+func (a *AspectRatio) Size(parentSize types.Size) types.Size {
+	if a.Ratio <= 0 {
+		return a.Child.Size(parentSize)
+	}
+
+	// Try the full width first, fall back to the full height if that's too tall
+	width := parentSize.Width
+	height := int(float64(width) / a.Ratio)
+	if height > parentSize.Height {
+		height = parentSize.Height
+		width = int(float64(height) * a.Ratio)
+	}
+
+	return types.Size{Width: width, Height: height}
+}
+
+// Intrinsic sizes follow from the ratio and the given extent
+
+func (a *AspectRatio) MinIntrinsicWidth(height int) int { return a.MaxIntrinsicWidth(height) }
+
+func (a *AspectRatio) MaxIntrinsicWidth(height int) int {
+	if a.Ratio <= 0 {
+		return MaxIntrinsicWidth(a.Child, height)
+	}
+	return int(float64(height) * a.Ratio)
+}
+
+func (a *AspectRatio) MinIntrinsicHeight(width int) int { return a.MaxIntrinsicHeight(width) }
+
+func (a *AspectRatio) MaxIntrinsicHeight(width int) int {
+	if a.Ratio <= 0 {
+		return MaxIntrinsicHeight(a.Child, width)
+	}
+	return int(float64(width) / a.Ratio)
+}
@@ -0,0 +1,21 @@
+package render_objects
+
+import (
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestAspectRatio(t *testing.T) {
+	ratio := &AspectRatio{Child: &ColoredBox{Width: 10, Height: 10}, Ratio: 16.0 / 9.0}
+
+	// Limited by the width
+	if size := ratio.Size(types.Size{Width: 160, Height: 200}); size.Width != 160 || size.Height != 90 {
+		t.Errorf("Expected 160x90, got %v", size)
+	}
+
+	// Limited by the height
+	if size := ratio.Size(types.Size{Width: 400, Height: 90}); size.Width != 160 || size.Height != 90 {
+		t.Errorf("Expected 160x90, got %v", size)
+	}
+}
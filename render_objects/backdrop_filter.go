@@ -0,0 +1,34 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// BackdropFilter blurs whatever has already been painted under its bounds,
+// then paints its child on top, giving a frosted glass effect
+type BackdropFilter struct {
+	Child  RenderObject // Optional, painted over the blurred backdrop
+	Radius float64      // Standard deviation of the blur in pixels
+}
+
+func (b *BackdropFilter) Paint(canvas *cv.Canvas) {
+	if canvas.Size.Width <= 0 || canvas.Size.Height <= 0 {
+		return
+	}
+
+	backdrop := canvas.Snapshot()
+	backdrop.Blur(b.Radius)
+	canvas.DrawCanvas(backdrop, 0, 0)
+
+	if b.Child != nil {
+		PaintChild(b.Child, canvas)
+	}
+}
+
+func (b *BackdropFilter) Size(parentSize types.Size) types.Size {
+	if b.Child == nil {
+		return parentSize
+	}
+	return b.Child.Size(parentSize)
+}
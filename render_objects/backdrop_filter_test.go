@@ -0,0 +1,38 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestBackdropFilter(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// Hard edge between red and blue halves
+	canvas.Rectangle(0, 0, 50, 100, red, true)
+	canvas.Rectangle(50, 0, 50, 100, blue, true)
+
+	filter := &BackdropFilter{Radius: 4}
+	filter.Paint(canvas.SubCanvas(0, 50, types.Size{Width: 100, Height: 50}, nil))
+
+	// The edge is softened inside the filtered region only
+	if c := canvas.Img.RGBAAt(49, 75); c.R == 255 || c.B == 0 {
+		t.Errorf("Expected the backdrop to be blurred across the edge, got %v", c)
+	}
+	if canvas.Img.At(49, 25) != red {
+		t.Error("Expected pixels outside the filter to be untouched")
+	}
+
+	// A child is painted on top of the blurred backdrop
+	canvas = cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	withChild := &BackdropFilter{Child: &ColoredBox{Width: 10, Height: 10, Color: red}, Radius: 2}
+	withChild.Paint(canvas)
+	if canvas.Img.At(5, 5) != red {
+		t.Error("Expected the child to be painted over the backdrop")
+	}
+}
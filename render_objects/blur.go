@@ -0,0 +1,30 @@
+package render_objects
+
+import (
+	"math"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Blur paints its child with a Gaussian blur, kept within the child's bounds.
+// Pixels near the edges are blurred as if the edge pixels extended outwards.
+type Blur struct {
+	Child  RenderObject
+	Radius float64 // Standard deviation of the blur in pixels, like CSS blur()
+}
+
+func (b *Blur) Paint(canvas *cv.Canvas) {
+	layer := paintLayer(canvas, b.Child, canvas.Size, 0)
+	layer.Blur(b.Radius)
+	canvas.DrawLayer(layer, 0, 0)
+}
+
+func (b *Blur) Size(parentSize types.Size) types.Size {
+	return b.Child.Size(parentSize)
+}
+
+// blurMargin is how far a blur with the given standard deviation visibly spreads
+func blurMargin(sigma float64) int {
+	return int(math.Ceil(3 * max(sigma, 0)))
+}
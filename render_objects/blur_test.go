@@ -0,0 +1,41 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestBlur(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// Red and blue halves with a hard edge between them
+	halves := &Row{Children: []RenderObject{
+		&ColoredBox{Width: 20, Height: 20, Color: red},
+		&ColoredBox{Width: 20, Height: 20, Color: blue},
+	}}
+	blur := &Blur{Child: halves, Radius: 3}
+
+	// Test Size method
+	size := blur.Size(canvas.Size)
+	if size.Width != 40 || size.Height != 20 {
+		t.Errorf("Expected blur to keep the child size 40x20, got %v", size)
+	}
+
+	// Test Paint method
+	blur.Paint(canvas.SubCanvas(30, 30, size, nil))
+
+	if c := canvas.Img.RGBAAt(49, 40); c.R == 255 || c.B == 0 {
+		t.Errorf("Expected the edge between the halves to be blurred, got %v", c)
+	}
+	if c := canvas.Img.RGBAAt(30, 30); c.A != 255 || c.R < 250 {
+		t.Errorf("Expected the blur to keep its edges opaque, got %v", c)
+	}
+	if a := canvas.Img.RGBAAt(29, 40).A; a != 0 {
+		t.Errorf("Expected nothing to be painted outside the child, got alpha %d", a)
+	}
+}
@@ -20,7 +20,7 @@ func (b *Border) Paint(canvas *cv.Canvas) {
 	width, height := canvas.Size.Width, canvas.Size.Height
 
 	// First paint the child
-	b.Child.Paint(canvas)
+	PaintChild(b.Child, canvas)
 
 	// Draw border inside the content bounds
 	// Top border
@@ -38,3 +38,8 @@ func (b *Border) Paint(canvas *cv.Canvas) {
 func (b *Border) Size(parentSize types.Size) types.Size {
 	return b.Child.Size(parentSize)
 }
+
+func (b *Border) MinIntrinsicWidth(height int) int { return MinIntrinsicWidth(b.Child, height) }
+func (b *Border) MaxIntrinsicWidth(height int) int { return MaxIntrinsicWidth(b.Child, height) }
+func (b *Border) MinIntrinsicHeight(width int) int { return MinIntrinsicHeight(b.Child, width) }
+func (b *Border) MaxIntrinsicHeight(width int) int { return MaxIntrinsicHeight(b.Child, width) }
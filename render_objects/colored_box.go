@@ -23,3 +23,8 @@ func (cb *ColoredBox) Size(parentSize types.Size) types.Size {
 		Height: cb.Height,
 	}
 }
+
+func (cb *ColoredBox) MinIntrinsicWidth(height int) int { return cb.Width }
+func (cb *ColoredBox) MaxIntrinsicWidth(height int) int { return cb.Width }
+func (cb *ColoredBox) MinIntrinsicHeight(width int) int { return cb.Height }
+func (cb *ColoredBox) MaxIntrinsicHeight(width int) int { return cb.Height }
@@ -96,7 +96,7 @@ func (c *Column) Paint(canvas *cv.Canvas) {
 	// Draw children at calculated positions
 	for i, child := range c.Children {
 		childCanvas := canvas.SubCanvas(0, yOffsets[i], childSizes[i], nil)
-		child.Paint(childCanvas)
+		PaintChild(child, childCanvas)
 	}
 }
 
@@ -135,3 +135,37 @@ func (c *Column) Size(parentSize types.Size) types.Size {
 
 	return size
 }
+
+// Intrinsic heights add up along the main axis, widths take the widest child
+
+func (c *Column) MinIntrinsicWidth(height int) int {
+	widest := 0
+	for _, child := range c.Children {
+		widest = max(widest, MinIntrinsicWidth(child, height))
+	}
+	return widest
+}
+
+func (c *Column) MaxIntrinsicWidth(height int) int {
+	widest := 0
+	for _, child := range c.Children {
+		widest = max(widest, MaxIntrinsicWidth(child, height))
+	}
+	return widest
+}
+
+func (c *Column) MinIntrinsicHeight(width int) int {
+	total := 0
+	for _, child := range c.Children {
+		total += MinIntrinsicHeight(child, width)
+	}
+	return total
+}
+
+func (c *Column) MaxIntrinsicHeight(width int) int {
+	total := 0
+	for _, child := range c.Children {
+		total += MaxIntrinsicHeight(child, width)
+	}
+	return total
+}
@@ -0,0 +1,58 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// ConstrainedBox keeps its child's size between a minimum and a maximum.
+// A zero maximum means unbounded.
+type ConstrainedBox struct {
+	Child     RenderObject
+	MinWidth  int
+	MaxWidth  int
+	MinHeight int
+	MaxHeight int
+}
+
+func (c *ConstrainedBox) Paint(canvas *cv.Canvas) {
+	PaintChild(c.Child, canvas)
+}
+
+func (c *ConstrainedBox) Size(parentSize types.Size) types.Size {
+	// The child can't grow past the maximum
+	available := types.Size{
+		Width:  clampDimension(parentSize.Width, 0, c.MaxWidth),
+		Height: clampDimension(parentSize.Height, 0, c.MaxHeight),
+	}
+	childSize := c.Child.Size(available)
+
+	return types.Size{
+		Width:  clampDimension(childSize.Width, c.MinWidth, c.MaxWidth),
+		Height: clampDimension(childSize.Height, c.MinHeight, c.MaxHeight),
+	}
+}
+
+// clampDimension keeps value between minValue and maxValue, a zero maxValue is unbounded
+func clampDimension(value, minValue, maxValue int) int {
+	if maxValue > 0 && value > maxValue {
+		value = maxValue
+	}
+	return max(value, minValue)
+}
+
+func (c *ConstrainedBox) MinIntrinsicWidth(height int) int {
+	return clampDimension(MinIntrinsicWidth(c.Child, height), c.MinWidth, c.MaxWidth)
+}
+
+func (c *ConstrainedBox) MaxIntrinsicWidth(height int) int {
+	return clampDimension(MaxIntrinsicWidth(c.Child, height), c.MinWidth, c.MaxWidth)
+}
+
+func (c *ConstrainedBox) MinIntrinsicHeight(width int) int {
+	return clampDimension(MinIntrinsicHeight(c.Child, width), c.MinHeight, c.MaxHeight)
+}
+
+func (c *ConstrainedBox) MaxIntrinsicHeight(width int) int {
+	return clampDimension(MaxIntrinsicHeight(c.Child, width), c.MinHeight, c.MaxHeight)
+}
@@ -0,0 +1,27 @@
+package render_objects
+
+import (
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestConstrainedBox(t *testing.T) {
+	parent := types.Size{Width: 200, Height: 200}
+
+	small := &ConstrainedBox{Child: &ColoredBox{Width: 10, Height: 10}, MinWidth: 40, MinHeight: 20}
+	if size := small.Size(parent); size.Width != 40 || size.Height != 20 {
+		t.Errorf("Expected minimum size 40x20, got %v", size)
+	}
+
+	large := &ConstrainedBox{Child: &ColoredBox{Width: 150, Height: 150}, MaxWidth: 100}
+	if size := large.Size(parent); size.Width != 100 || size.Height != 150 {
+		t.Errorf("Expected width capped at 100 with unbounded height, got %v", size)
+	}
+
+	// The maximum also limits the space the child is measured in
+	expanded := &ConstrainedBox{Child: NewExpandedBox(nil), MaxWidth: 120, MaxHeight: 80}
+	if size := expanded.Size(parent); size.Width != 120 || size.Height != 80 {
+		t.Errorf("Expected expanded child limited to 120x80, got %v", size)
+	}
+}
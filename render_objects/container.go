@@ -0,0 +1,152 @@
+package render_objects
+
+import (
+	"image"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Container combines a decorated box with margin, padding and an optional fixed size.
+// From the outside in it is made of the margin, the border, the padding and the child.
+// Outer shadows paint into the margin, which grows where they reach past it so they
+// are never clipped, like around a DecoratedBox.
+type Container struct {
+	Child      RenderObject
+	Decoration BoxDecoration
+	Padding    types.EdgeInsets
+	Margin     types.EdgeInsets
+	Width      *int      // Width of the decorated box, nil follows the child
+	Height     *int      // Height of the decorated box, nil follows the child
+	Alignment  AlignType // Alignment of the child inside the padding, top left by default
+}
+
+// outside returns the space around the decorated box: the margin, or the extent of the
+// outer shadows where they reach further
+func (c *Container) outside() types.EdgeInsets {
+	return maxInsets(c.Margin, c.Decoration.shadowInsets())
+}
+
+// insets returns the space between the decorated box and the child
+func (c *Container) insets() types.EdgeInsets {
+	border := c.Decoration.borderInsets()
+	return types.EdgeInsets{
+		Top:    border.Top + c.Padding.Top,
+		Right:  border.Right + c.Padding.Right,
+		Bottom: border.Bottom + c.Padding.Bottom,
+		Left:   border.Left + c.Padding.Left,
+	}
+}
+
+// boxSize returns the size of the decorated box for the given parent size
+func (c *Container) boxSize(parentSize types.Size) types.Size {
+	outside := c.outside()
+	available := types.Size{
+		Width:  parentSize.Width - outside.Horizontal(),
+		Height: parentSize.Height - outside.Vertical(),
+	}
+
+	// Without a child an unsized container fills the available space
+	size := available
+	if c.Child != nil {
+		insets := c.insets()
+		childAvailable := available
+		if c.Width != nil {
+			childAvailable.Width = *c.Width
+		}
+		if c.Height != nil {
+			childAvailable.Height = *c.Height
+		}
+		childSize := c.Child.Size(types.Size{
+			Width:  childAvailable.Width - insets.Horizontal(),
+			Height: childAvailable.Height - insets.Vertical(),
+		})
+		size = types.Size{
+			Width:  childSize.Width + insets.Horizontal(),
+			Height: childSize.Height + insets.Vertical(),
+		}
+	}
+
+	if c.Width != nil {
+		size.Width = *c.Width
+	}
+	if c.Height != nil {
+		size.Height = *c.Height
+	}
+	return size
+}
+
+func (c *Container) Paint(canvas *cv.Canvas) {
+	box, outside := c.boxSize(canvas.Size), c.outside()
+	c.Decoration.paint(canvas, outside.Left, outside.Top, box)
+
+	if c.Child == nil {
+		return
+	}
+
+	insets := c.insets()
+	content := types.Size{
+		Width:  box.Width - insets.Horizontal(),
+		Height: box.Height - insets.Vertical(),
+	}
+	childSize := c.Child.Size(content)
+	x, y := c.Alignment.offset(content, childSize)
+
+	childCanvas := canvas.SubCanvas(outside.Left+insets.Left+x, outside.Top+insets.Top+y, childSize, nil)
+	PaintChild(c.Child, childCanvas)
+}
+
+func (c *Container) Size(parentSize types.Size) types.Size {
+	box, outside := c.boxSize(parentSize), c.outside()
+	return types.Size{
+		Width:  box.Width + outside.Horizontal(),
+		Height: box.Height + outside.Vertical(),
+	}
+}
+
+// Guides show the margin, the border and padding together, and where the child is aligned
+func (c *Container) Guides(size types.Size) Guides {
+	guides := Guides{Margin: c.outside(), Padding: c.insets()}
+	if c.Child != nil {
+		box := c.boxSize(size)
+		content := types.Size{
+			Width:  box.Width - guides.Padding.Horizontal(),
+			Height: box.Height - guides.Padding.Vertical(),
+		}
+		anchor := c.Alignment.anchor(content)
+		guides.Anchors = []image.Point{anchor.Add(image.Pt(guides.Margin.Left+guides.Padding.Left, guides.Margin.Top+guides.Padding.Top))}
+	}
+	return guides
+}
+
+// intrinsic measures the child with the given measure, falling back to a fixed box dimension
+func (c *Container) intrinsic(fixed *int, extent, inset, otherInset, margin, otherMargin int, measure func(RenderObject, int) int) int {
+	switch {
+	case fixed != nil:
+		return *fixed + margin
+	case c.Child == nil:
+		return inset + margin
+	default:
+		return measure(c.Child, max(extent-otherInset-otherMargin, 0)) + inset + margin
+	}
+}
+
+func (c *Container) MinIntrinsicWidth(height int) int {
+	insets, outside := c.insets(), c.outside()
+	return c.intrinsic(c.Width, height, insets.Horizontal(), insets.Vertical(), outside.Horizontal(), outside.Vertical(), MinIntrinsicWidth)
+}
+
+func (c *Container) MaxIntrinsicWidth(height int) int {
+	insets, outside := c.insets(), c.outside()
+	return c.intrinsic(c.Width, height, insets.Horizontal(), insets.Vertical(), outside.Horizontal(), outside.Vertical(), MaxIntrinsicWidth)
+}
+
+func (c *Container) MinIntrinsicHeight(width int) int {
+	insets, outside := c.insets(), c.outside()
+	return c.intrinsic(c.Height, width, insets.Vertical(), insets.Horizontal(), outside.Vertical(), outside.Horizontal(), MinIntrinsicHeight)
+}
+
+func (c *Container) MaxIntrinsicHeight(width int) int {
+	insets, outside := c.insets(), c.outside()
+	return c.intrinsic(c.Height, width, insets.Vertical(), insets.Horizontal(), outside.Vertical(), outside.Horizontal(), MaxIntrinsicHeight)
+}
@@ -0,0 +1,89 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestContainer(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 200, Height: 200}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+
+	container := &Container{
+		Child: &ColoredBox{Width: 20, Height: 20, Color: blue},
+		Decoration: BoxDecoration{
+			Background: cv.SolidColor{Color: white},
+			Border:     cv.UniformBorder(cv.BorderSide{Width: 2, Color: red}),
+			Shadows:    []cv.BoxShadow{{Color: black, OffsetY: 4, Blur: 6}},
+		},
+		Padding: types.EdgeInsetsAll(8),
+		Margin:  types.EdgeInsetsAll(10),
+	}
+
+	// Margin, border, padding and child add up, and the shadow reaches 14px below the box
+	size := container.Size(canvas.Size)
+	if size.Width != 60 || size.Height != 64 {
+		t.Fatalf("Expected container size 60x64, got %v", size)
+	}
+
+	container.Paint(canvas.SubCanvas(0, 0, size, nil))
+
+	if canvas.Img.At(10, 30) != red || canvas.Img.At(49, 30) != red {
+		t.Error("Expected the border just inside the margin")
+	}
+	if canvas.Img.At(15, 30) != white {
+		t.Error("Expected the background inside the padding")
+	}
+	if canvas.Img.At(20, 20) != blue || canvas.Img.At(39, 39) != blue {
+		t.Error("Expected the child inside the padding")
+	}
+	if canvas.Img.RGBAAt(30, 52).A == 0 {
+		t.Error("Expected the shadow to paint into the bottom margin")
+	}
+	if canvas.Img.RGBAAt(30, 62).A == 0 {
+		t.Error("Expected room for the shadow below the bottom margin")
+	}
+}
+
+func TestContainerMatchesDecoratedBox(t *testing.T) {
+	decoration := BoxDecoration{
+		Background: cv.SolidColor{Color: color.RGBA{255, 255, 255, 255}},
+		Shadows:    []cv.BoxShadow{{Color: color.RGBA{0, 0, 0, 255}, OffsetX: -3, OffsetY: 4, Blur: 6, Spread: 2}},
+	}
+	child := &ColoredBox{Width: 20, Height: 20}
+	parent := types.Size{Width: 200, Height: 200}
+
+	container := (&Container{Child: child, Decoration: decoration}).Size(parent)
+	decorated := (&DecoratedBox{Child: child, Decoration: decoration}).Size(parent)
+	if container != decorated {
+		t.Errorf("Expected a Container to take the %v of a DecoratedBox with the same decoration, got %v", decorated, container)
+	}
+}
+
+func TestContainerFixedSize(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 200, Height: 200}, false)
+	blue := color.RGBA{0, 0, 255, 255}
+
+	container := &Container{
+		Child:     &ColoredBox{Width: 20, Height: 20, Color: blue},
+		Width:     Px(100),
+		Height:    Px(50),
+		Alignment: AlignCenter,
+	}
+
+	size := container.Size(canvas.Size)
+	if size.Width != 100 || size.Height != 50 {
+		t.Fatalf("Expected container size 100x50, got %v", size)
+	}
+
+	container.Paint(canvas)
+	if canvas.Img.At(50, 25) != blue || canvas.Img.At(30, 25) == blue {
+		t.Error("Expected the child to be centered in the container")
+	}
+}
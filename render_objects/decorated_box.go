@@ -0,0 +1,151 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// BoxDecoration describes the background, border and shadows of a box
+type BoxDecoration struct {
+	Background   cv.Shader // Solid color, gradient or image, nil for none
+	BorderRadius cv.Radii
+	Border       cv.BorderSides
+	Shadows      []cv.BoxShadow
+}
+
+// borderInsets returns the space taken up by the border on each side
+func (d *BoxDecoration) borderInsets() types.EdgeInsets {
+	top, right, bottom, left := d.Border.Widths()
+	return types.EdgeInsets{Top: top, Right: right, Bottom: bottom, Left: left}
+}
+
+// shadowInsets returns how far the outer shadows reach past each side of the box
+func (d *BoxDecoration) shadowInsets() types.EdgeInsets {
+	var insets types.EdgeInsets
+	for _, shadow := range d.Shadows {
+		top, right, bottom, left := shadow.Extent()
+		insets = maxInsets(insets, types.EdgeInsets{Top: top, Right: right, Bottom: bottom, Left: left})
+	}
+	return insets
+}
+
+// maxInsets returns the larger of a and b on each side
+func maxInsets(a, b types.EdgeInsets) types.EdgeInsets {
+	return types.EdgeInsets{
+		Top:    max(a.Top, b.Top),
+		Right:  max(a.Right, b.Right),
+		Bottom: max(a.Bottom, b.Bottom),
+		Left:   max(a.Left, b.Left),
+	}
+}
+
+// paint draws the decoration of a box at (x, y) from the outside in:
+// drop shadows, background, inset shadows and finally the border
+func (d *BoxDecoration) paint(canvas *cv.Canvas, x, y int, size types.Size) {
+	for _, shadow := range d.Shadows {
+		if !shadow.Inset {
+			canvas.DrawBoxShadow(x, y, size.Width, size.Height, d.BorderRadius, shadow)
+		}
+	}
+
+	canvas.FillRoundedRect(x, y, size.Width, size.Height, d.BorderRadius, d.Background)
+
+	for _, shadow := range d.Shadows {
+		if shadow.Inset {
+			canvas.DrawBoxShadow(x, y, size.Width, size.Height, d.BorderRadius, shadow)
+		}
+	}
+
+	canvas.RoundedBorder(x, y, size.Width, size.Height, d.BorderRadius, d.Border)
+}
+
+// DecoratedBox paints a decoration behind its child.
+// The child is inset by the border widths so the border never covers it, and the
+// extent of the outer shadows is added around the box so they are never clipped,
+// the same space a Container with the decoration and no margin takes.
+// Without a child the box and its shadows fill the parent.
+type DecoratedBox struct {
+	Child      RenderObject
+	Decoration BoxDecoration
+}
+
+// insets returns the space between the edge of the canvas and the child: the shadow
+// extent, then the border
+func (d *DecoratedBox) insets() types.EdgeInsets {
+	shadows, border := d.Decoration.shadowInsets(), d.Decoration.borderInsets()
+	return types.EdgeInsets{
+		Top:    shadows.Top + border.Top,
+		Right:  shadows.Right + border.Right,
+		Bottom: shadows.Bottom + border.Bottom,
+		Left:   shadows.Left + border.Left,
+	}
+}
+
+func (d *DecoratedBox) Paint(canvas *cv.Canvas) {
+	shadows := d.Decoration.shadowInsets()
+	box := types.Size{
+		Width:  canvas.Size.Width - shadows.Horizontal(),
+		Height: canvas.Size.Height - shadows.Vertical(),
+	}
+	d.Decoration.paint(canvas, shadows.Left, shadows.Top, box)
+
+	if d.Child == nil {
+		return
+	}
+
+	insets := d.insets()
+	childSize := types.Size{
+		Width:  canvas.Size.Width - insets.Horizontal(),
+		Height: canvas.Size.Height - insets.Vertical(),
+	}
+	childCanvas := canvas.SubCanvas(insets.Left, insets.Top, childSize, nil)
+	PaintChild(d.Child, childCanvas)
+}
+
+func (d *DecoratedBox) Size(parentSize types.Size) types.Size {
+	if d.Child == nil {
+		return parentSize
+	}
+
+	insets := d.insets()
+	childSize := d.Child.Size(types.Size{
+		Width:  parentSize.Width - insets.Horizontal(),
+		Height: parentSize.Height - insets.Vertical(),
+	})
+
+	return types.Size{
+		Width:  childSize.Width + insets.Horizontal(),
+		Height: childSize.Height + insets.Vertical(),
+	}
+}
+
+func (d *DecoratedBox) intrinsic(extent, inset, otherInset int, measure func(RenderObject, int) int) int {
+	if d.Child == nil {
+		return inset
+	}
+	return measure(d.Child, max(extent-otherInset, 0)) + inset
+}
+
+func (d *DecoratedBox) MinIntrinsicWidth(height int) int {
+	insets := d.insets()
+	return d.intrinsic(height, insets.Horizontal(), insets.Vertical(), MinIntrinsicWidth)
+}
+
+func (d *DecoratedBox) MaxIntrinsicWidth(height int) int {
+	insets := d.insets()
+	return d.intrinsic(height, insets.Horizontal(), insets.Vertical(), MaxIntrinsicWidth)
+}
+
+func (d *DecoratedBox) MinIntrinsicHeight(width int) int {
+	insets := d.insets()
+	return d.intrinsic(width, insets.Vertical(), insets.Horizontal(), MinIntrinsicHeight)
+}
+
+func (d *DecoratedBox) MaxIntrinsicHeight(width int) int {
+	insets := d.insets()
+	return d.intrinsic(width, insets.Vertical(), insets.Horizontal(), MaxIntrinsicHeight)
+}
+
+func (d *DecoratedBox) Guides(size types.Size) Guides {
+	return Guides{Margin: d.Decoration.shadowInsets(), Padding: d.Decoration.borderInsets()}
+}
@@ -0,0 +1,92 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestDecoratedBox(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	box := &DecoratedBox{
+		Child: &ColoredBox{Width: 40, Height: 20, Color: blue},
+		Decoration: BoxDecoration{
+			Background: cv.SolidColor{Color: white},
+			Border: cv.BorderSides{
+				Top:  cv.BorderSide{Width: 2, Color: red},
+				Left: cv.BorderSide{Width: 5, Color: red},
+			},
+		},
+	}
+
+	// Test Size method
+	size := box.Size(canvas.Size)
+	if size.Width != 45 || size.Height != 22 {
+		t.Fatalf("Expected decorated box size 45x22, got %v", size)
+	}
+
+	// Test Paint method
+	box.Paint(canvas.SubCanvas(0, 0, size, nil))
+
+	// The border sits outside the child instead of covering it
+	if canvas.Img.At(4, 10) != red || canvas.Img.At(20, 1) != red {
+		t.Error("Expected the border on the top and left sides")
+	}
+	if canvas.Img.At(5, 2) != blue || canvas.Img.At(44, 21) != blue {
+		t.Error("Expected the child to fill the area inside the border")
+	}
+}
+
+func TestDecoratedBoxRoundedBackground(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 50, Height: 50}, false)
+	white := color.RGBA{255, 255, 255, 255}
+
+	box := &DecoratedBox{Decoration: BoxDecoration{
+		Background:   cv.SolidColor{Color: white},
+		BorderRadius: cv.RadiiAll(10),
+	}}
+	box.Paint(canvas)
+
+	if canvas.Img.At(25, 25) != white {
+		t.Error("Expected the background to fill the box")
+	}
+	if canvas.Img.RGBAAt(0, 0).A != 0 {
+		t.Error("Expected the corners to be rounded")
+	}
+}
+
+func TestDecoratedBoxShadow(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	white := color.RGBA{255, 255, 255, 255}
+
+	box := &DecoratedBox{
+		Child: &SizedBox{Width: Px(40), Height: Px(40)},
+		Decoration: BoxDecoration{
+			Background: cv.SolidColor{Color: white},
+			Shadows:    []cv.BoxShadow{{Color: color.RGBA{0, 0, 0, 128}, OffsetY: 4, Blur: 8}},
+		},
+	}
+
+	// The blur reaches 13 pixels past the box, shifted down by the offset
+	size := box.Size(canvas.Size)
+	if size.Width != 66 || size.Height != 66 {
+		t.Fatalf("Expected room for the shadow around the box, 66x66, got %v", size)
+	}
+	box.Paint(canvas.SubCanvas(0, 0, size, nil))
+
+	if canvas.Img.At(13, 9) != white || canvas.Img.At(52, 48) != white {
+		t.Error("Expected the box inside the shadow extent")
+	}
+	if a := canvas.Img.RGBAAt(33, 53).A; a == 0 {
+		t.Error("Expected the shadow below the box")
+	}
+	if a := canvas.Img.RGBAAt(8, 30).A; a == 0 {
+		t.Error("Expected the shadow left of the box")
+	}
+}
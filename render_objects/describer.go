@@ -0,0 +1,8 @@
+package render_objects
+
+// Describer is implemented by render objects whose key properties aren't exported fields,
+// so inspection tools can still show them. Values are plain values such as strings,
+// numbers and colors.
+type Describer interface {
+	Describe() map[string]any
+}
@@ -0,0 +1,137 @@
+package render_objects
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// BoxFit describes how a child is scaled into the space of a FittedBox
+type BoxFit int
+
+const (
+	BoxFitContain   BoxFit = iota // As large as possible while staying fully visible
+	BoxFitCover                   // As small as possible while covering the whole box
+	BoxFitFill                    // Stretched to the box, ignoring the aspect ratio
+	BoxFitFitWidth                // Scaled to the box width
+	BoxFitFitHeight               // Scaled to the box height
+	BoxFitNone                    // Not scaled
+	BoxFitScaleDown               // Like contain, but never scaled up
+)
+
+func (f BoxFit) String() string {
+	switch f {
+	case BoxFitContain:
+		return "contain"
+	case BoxFitCover:
+		return "cover"
+	case BoxFitFill:
+		return "fill"
+	case BoxFitFitWidth:
+		return "fitWidth"
+	case BoxFitFitHeight:
+		return "fitHeight"
+	case BoxFitNone:
+		return "none"
+	case BoxFitScaleDown:
+		return "scaleDown"
+	}
+	return fmt.Sprintf("BoxFit(%d)", int(f))
+}
+
+// FittedBox renders its child at its natural size and scales the result to fit its own canvas
+type FittedBox struct {
+	Child     RenderObject
+	Fit       BoxFit
+	Alignment AlignType // Alignment of the scaled child, centered by default
+}
+
+func (f *FittedBox) Paint(canvas *cv.Canvas) {
+	childSize := f.Child.Size(canvas.Size)
+	if childSize.Width <= 0 || childSize.Height <= 0 {
+		return
+	}
+
+	scaleX, scaleY := f.scale(canvas.Size, childSize)
+	scaledSize := types.Size{
+		Width:  int(math.Round(float64(childSize.Width) * scaleX)),
+		Height: int(math.Round(float64(childSize.Height) * scaleY)),
+	}
+
+	alignment := f.Alignment
+	if alignment == "" {
+		alignment = AlignCenter
+	}
+	x, y := alignment.offset(canvas.Size, scaledSize)
+
+	// Render the child offscreen at its natural size
+	childCanvas := canvas.NewOffscreen(childSize)
+	childCanvas.FollowScaled(canvas, x, y, float64(scaledSize.Width)/float64(childSize.Width), float64(scaledSize.Height)/float64(childSize.Height))
+	PaintChild(f.Child, childCanvas)
+	canvas.DrawCanvasScaled(childCanvas, x, y, scaledSize)
+}
+
+// scale returns the horizontal and vertical scale factors for a child of childSize in a box of boxSize
+func (f *FittedBox) scale(boxSize, childSize types.Size) (float64, float64) {
+	scaleX := float64(boxSize.Width) / float64(childSize.Width)
+	scaleY := float64(boxSize.Height) / float64(childSize.Height)
+
+	switch f.Fit {
+	case BoxFitCover:
+		scale := max(scaleX, scaleY)
+		return scale, scale
+	case BoxFitFill:
+		return scaleX, scaleY
+	case BoxFitFitWidth:
+		return scaleX, scaleX
+	case BoxFitFitHeight:
+		return scaleY, scaleY
+	case BoxFitNone:
+		return 1, 1
+	case BoxFitScaleDown:
+		scale := min(scaleX, scaleY, 1)
+		return scale, scale
+	default:
+		scale := min(scaleX, scaleY)
+		return scale, scale
+	}
+}
+
+func (f *FittedBox) Size(parentSize types.Size) types.Size {
+	childSize := f.Child.Size(parentSize)
+	if childSize.Width <= 0 || childSize.Height <= 0 {
+		return childSize
+	}
+
+	// The box takes the size of the scaled child, clipped to the parent
+	scaleX, scaleY := f.scale(parentSize, childSize)
+	return types.Size{
+		Width:  min(int(math.Round(float64(childSize.Width)*scaleX)), parentSize.Width),
+		Height: min(int(math.Round(float64(childSize.Height)*scaleY)), parentSize.Height),
+	}
+}
+
+func (f *FittedBox) Guides(size types.Size) Guides {
+	alignment := f.Alignment
+	if alignment == "" {
+		alignment = AlignCenter
+	}
+	return Guides{Anchors: []image.Point{alignment.anchor(size)}}
+}
+
+// A fitted child can shrink to any size, so only the natural size is reported
+
+func (f *FittedBox) MinIntrinsicWidth(height int) int { return 0 }
+
+func (f *FittedBox) MaxIntrinsicWidth(height int) int {
+	return MaxIntrinsicWidth(f.Child, height)
+}
+
+func (f *FittedBox) MinIntrinsicHeight(width int) int { return 0 }
+
+func (f *FittedBox) MaxIntrinsicHeight(width int) int {
+	return MaxIntrinsicHeight(f.Child, width)
+}
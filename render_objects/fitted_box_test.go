@@ -0,0 +1,84 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestFittedBox(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	transparent := color.RGBA{0, 0, 0, 0}
+	box := &ColoredBox{Width: 20, Height: 10, Color: red}
+
+	// Contain scales the child up until it touches the box edges
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	contain := &FittedBox{Child: box, Fit: BoxFitContain}
+	contain.Paint(canvas)
+	if canvas.Img.At(0, 50) != red || canvas.Img.At(99, 50) != red {
+		t.Error("Expected contained child to span the full width")
+	}
+	if canvas.Img.At(50, 10) != transparent || canvas.Img.At(50, 80) != transparent {
+		t.Error("Expected contained child to be letterboxed vertically")
+	}
+
+	// Cover fills the whole box and clips the overflow
+	canvas = cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	cover := &FittedBox{Child: box, Fit: BoxFitCover}
+	cover.Paint(canvas)
+	if canvas.Img.At(50, 0) != red || canvas.Img.At(50, 99) != red || canvas.Img.At(0, 50) != red {
+		t.Error("Expected covering child to fill the box")
+	}
+
+	// ScaleDown never grows the child
+	canvas = cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	scaleDown := &FittedBox{Child: box, Fit: BoxFitScaleDown, Alignment: AlignTopLeft}
+	scaleDown.Paint(canvas)
+	if canvas.Img.At(19, 9) != red || canvas.Img.At(20, 10) != transparent {
+		t.Error("Expected child to keep its natural size")
+	}
+
+	// Size preserves the aspect ratio when the child doesn't fit
+	large := &FittedBox{Child: &ColoredBox{Width: 400, Height: 200, Color: red}}
+	if size := large.Size(types.Size{Width: 100, Height: 100}); size.Width != 100 || size.Height != 50 {
+		t.Errorf("Expected size 100x50, got %v", size)
+	}
+}
+
+func TestFittedBoxSize(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	transparent := color.RGBA{0, 0, 0, 0}
+	parent := types.Size{Width: 100, Height: 100}
+
+	tests := []struct {
+		fit  BoxFit
+		size types.Size
+	}{
+		{BoxFitContain, types.Size{Width: 100, Height: 50}},
+		{BoxFitCover, types.Size{Width: 100, Height: 100}},
+		{BoxFitFill, types.Size{Width: 100, Height: 100}},
+		{BoxFitFitWidth, types.Size{Width: 100, Height: 50}},
+		{BoxFitFitHeight, types.Size{Width: 100, Height: 100}},
+		{BoxFitNone, types.Size{Width: 20, Height: 10}},
+		{BoxFitScaleDown, types.Size{Width: 20, Height: 10}},
+	}
+	for _, test := range tests {
+		fitted := &FittedBox{Child: &ColoredBox{Width: 20, Height: 10, Color: red}, Fit: test.fit}
+		if size := fitted.Size(parent); size != test.size {
+			t.Errorf("Expected %v to size the box %v, got %v", test.fit, test.size, size)
+		}
+
+		// Inside an Align the box gets its own size, and the child is scaled up within it
+		canvas := cv.NewCanvas(parent, false)
+		(&Align{Align: AlignTopLeft, Child: fitted}).Paint(canvas)
+		last := types.Size{Width: test.size.Width - 1, Height: test.size.Height - 1}
+		if canvas.Img.At(0, 0) != red || canvas.Img.At(last.Width, last.Height) != red {
+			t.Errorf("Expected %v to paint the child across %v", test.fit, test.size)
+		}
+		if test.size.Height < 100 && canvas.Img.At(0, test.size.Height) != transparent {
+			t.Errorf("Expected %v to paint nothing below %v", test.fit, test.size)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// FractionallySizedBox sizes its child to a fraction of the parent's size.
+// A zero factor leaves that dimension to the child.
+type FractionallySizedBox struct {
+	Child        RenderObject
+	WidthFactor  float64
+	HeightFactor float64
+}
+
+func (f *FractionallySizedBox) Paint(canvas *cv.Canvas) {
+	PaintChild(f.Child, canvas)
+}
+
+func (f *FractionallySizedBox) Size(parentSize types.Size) types.Size {
+	available := parentSize
+	if f.WidthFactor > 0 {
+		available.Width = int(float64(parentSize.Width) * f.WidthFactor)
+	}
+	if f.HeightFactor > 0 {
+		available.Height = int(float64(parentSize.Height) * f.HeightFactor)
+	}
+
+	size := f.Child.Size(available)
+	if f.WidthFactor > 0 {
+		size.Width = available.Width
+	}
+	if f.HeightFactor > 0 {
+		size.Height = available.Height
+	}
+
+	return size
+}
+
+// The fraction depends on the parent, so intrinsic sizes are those of the child
+
+func (f *FractionallySizedBox) MinIntrinsicWidth(height int) int {
+	return MinIntrinsicWidth(f.Child, height)
+}
+
+func (f *FractionallySizedBox) MaxIntrinsicWidth(height int) int {
+	return MaxIntrinsicWidth(f.Child, height)
+}
+
+func (f *FractionallySizedBox) MinIntrinsicHeight(width int) int {
+	return MinIntrinsicHeight(f.Child, width)
+}
+
+func (f *FractionallySizedBox) MaxIntrinsicHeight(width int) int {
+	return MaxIntrinsicHeight(f.Child, width)
+}
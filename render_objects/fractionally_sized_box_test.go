@@ -0,0 +1,23 @@
+package render_objects
+
+import (
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestFractionallySizedBox(t *testing.T) {
+	parent := types.Size{Width: 200, Height: 100}
+	box := &ColoredBox{Width: 10, Height: 30}
+
+	half := &FractionallySizedBox{Child: box, WidthFactor: 0.5, HeightFactor: 0.25}
+	if size := half.Size(parent); size.Width != 100 || size.Height != 25 {
+		t.Errorf("Expected 100x25, got %v", size)
+	}
+
+	// A zero factor leaves the dimension to the child
+	widthOnly := &FractionallySizedBox{Child: box, WidthFactor: 0.75}
+	if size := widthOnly.Size(parent); size.Width != 150 || size.Height != 30 {
+		t.Errorf("Expected 150x30, got %v", size)
+	}
+}
@@ -0,0 +1,72 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/rendertest"
+	"github.com/hvuhsg/render/types"
+)
+
+// Anti-aliased edges may round differently between platforms
+var golden = rendertest.Options{ChannelTolerance: 8, MaxDiffRatio: 0.001}
+
+func TestGoldenCard(t *testing.T) {
+	card := &Container{
+		Padding: types.EdgeInsetsAll(16),
+		Margin:  types.EdgeInsetsAll(12),
+		Decoration: BoxDecoration{
+			Background: cv.LinearGradient{
+				Start: [2]float64{0, 0},
+				End:   [2]float64{1, 1},
+				Stops: []cv.GradientStop{{Offset: 0, Color: color.RGBA{79, 70, 229, 255}}, {Offset: 1, Color: color.RGBA{15, 23, 42, 255}}},
+			},
+			BorderRadius: cv.RadiiAll(12),
+			Border:       cv.UniformBorder(cv.BorderSide{Width: 2, Color: color.RGBA{255, 255, 255, 255}}),
+			Shadows:      []cv.BoxShadow{{Color: color.RGBA{0, 0, 0, 128}, OffsetY: 4, Blur: 8}},
+		},
+		Child: &Column{Children: []RenderObject{
+			NewText("Golden", cv.White, 24, "bold"),
+			NewWrappedText("Rendered and compared against testdata", cv.LightGray, 14, "default"),
+		}},
+	}
+	golden.AssertGolden(t, card, types.Size{Width: 240, Height: 140}, "card")
+}
+
+func TestGoldenRowAlignment(t *testing.T) {
+	boxes := func() []RenderObject {
+		return []RenderObject{
+			&ColoredBox{Width: 20, Height: 20, Color: cv.Red},
+			&ColoredBox{Width: 30, Height: 10, Color: cv.Green},
+			&ColoredBox{Width: 10, Height: 30, Color: cv.Blue},
+		}
+	}
+	var rows []RenderObject
+	for _, alignment := range []types.MainAxisAlignment{
+		types.MainAxisAlignmentStart,
+		types.MainAxisAlignmentCenter,
+		types.MainAxisAlignmentEnd,
+		types.MainAxisAlignmentSpaceBetween,
+		types.MainAxisAlignmentSpaceAround,
+		types.MainAxisAlignmentSpaceEvenly,
+	} {
+		rows = append(rows, &SizedBox{
+			Height: Px(32),
+			Child:  &Row{Children: boxes(), Alignment: alignment, Sizing: types.MainAxisSizeMax},
+		})
+	}
+	golden.AssertGolden(t, &Column{Children: rows}, types.Size{Width: 120, Height: 192}, "row_alignment")
+}
+
+func TestGoldenTransform(t *testing.T) {
+	rotated := &Align{
+		Align: AlignCenter,
+		Child: NewRotation(&Container{
+			Width:      Px(60),
+			Height:     Px(30),
+			Decoration: BoxDecoration{Background: cv.SolidColor{Color: cv.Orange}, BorderRadius: cv.RadiiAll(6)},
+		}, 30),
+	}
+	golden.AssertGolden(t, rotated, types.Size{Width: 100, Height: 100}, "transform")
+}
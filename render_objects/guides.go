@@ -0,0 +1,35 @@
+package render_objects
+
+import (
+	"image"
+
+	"github.com/hvuhsg/render/types"
+)
+
+// Guides describe how a render object lays out its content, for debugging tools.
+// Positions are relative to the top left corner of the object.
+type Guides struct {
+	Margin    types.EdgeInsets // Space around the object's box left empty
+	Padding   types.EdgeInsets // Space between the box and its content, borders included
+	Anchors   []image.Point    // Points the content is aligned to
+	Baselines []int            // Baselines of the lines of text
+}
+
+// Guider is implemented by render objects that can describe their layout when painted at size
+type Guider interface {
+	Guides(size types.Size) Guides
+}
+
+// GuidesOf returns the guides of obj painted at size, empty for objects that aren't a Guider
+func GuidesOf(obj RenderObject, size types.Size) Guides {
+	if guider, ok := obj.(Guider); ok {
+		return guider.Guides(size)
+	}
+	return Guides{}
+}
+
+// anchor returns the point of a container of size that children are aligned to
+func (align AlignType) anchor(size types.Size) image.Point {
+	x, y := align.offset(size, types.Size{})
+	return image.Pt(x, y)
+}
@@ -0,0 +1,89 @@
+package render_objects
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// paintRecorder collects the render objects painted through PaintChild
+type paintRecorder struct {
+	painted []any
+	depth   int
+	deepest int
+}
+
+func (r *paintRecorder) BeginPaint(object any, canvas *cv.Canvas) {
+	r.painted = append(r.painted, object)
+	r.depth++
+	r.deepest = max(r.deepest, r.depth)
+}
+
+func (r *paintRecorder) EndPaint(object any, canvas *cv.Canvas) {
+	r.depth--
+}
+
+func TestPaintChildTraces(t *testing.T) {
+	text := NewText("Hi", color.Black, 12, "")
+	box := &ColoredBox{Width: 10, Height: 10, Color: color.RGBA{255, 0, 0, 255}}
+	shadow := &Shadow{Child: box, Color: color.RGBA{0, 0, 0, 255}, Blur: 2}
+	row := &Row{Children: []RenderObject{&Opacity{Child: text, Opacity: 0.5}, shadow}}
+
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 40}, false)
+	rec := &paintRecorder{}
+	canvas.SetTracer(rec)
+	PaintChild(row, canvas)
+
+	// The shadow's silhouette of the box isn't reported, only the box itself
+	if len(rec.painted) != 5 {
+		t.Errorf("Expected 5 painted objects, got %d: %v", len(rec.painted), rec.painted)
+	}
+	if rec.deepest != 3 || rec.depth != 0 {
+		t.Errorf("Expected nesting 3 deep and balanced, got %d deep ending at %d", rec.deepest, rec.depth)
+	}
+}
+
+func TestContainerGuides(t *testing.T) {
+	container := &Container{
+		Child:      &ColoredBox{Width: 10, Height: 10},
+		Decoration: BoxDecoration{Border: cv.UniformBorder(cv.BorderSide{Width: 2, Color: color.RGBA{0, 0, 0, 255}})},
+		Padding:    types.EdgeInsetsAll(4),
+		Margin:     types.EdgeInsetsAll(8),
+		Alignment:  AlignCenter,
+	}
+
+	guides := container.Guides(types.Size{Width: 100, Height: 60})
+	if guides.Margin != types.EdgeInsetsAll(8) {
+		t.Errorf("Expected the margin as margin guide, got %v", guides.Margin)
+	}
+	if guides.Padding != types.EdgeInsetsAll(6) {
+		t.Errorf("Expected border and padding as padding guide, got %v", guides.Padding)
+	}
+	// The box shrinks to the child, so its content is the 10x10 child at (14, 14)
+	if len(guides.Anchors) != 1 || guides.Anchors[0] != image.Pt(19, 19) {
+		t.Errorf("Expected the anchor at the center of the content, got %v", guides.Anchors)
+	}
+}
+
+func TestTextGuides(t *testing.T) {
+	text := NewWrappedText("one two three four", color.Black, 10, "")
+	size := text.Size(types.Size{Width: 40, Height: 100})
+
+	guides := text.Guides(size)
+	lineHeight := 14
+	if len(guides.Baselines) < 2 {
+		t.Fatalf("Expected a baseline per line, got %v", guides.Baselines)
+	}
+	for i, baseline := range guides.Baselines {
+		if want := i*lineHeight + 10; baseline != want {
+			t.Errorf("Expected baseline %d at %d, got %d", i, want, baseline)
+		}
+	}
+
+	if got := GuidesOf(&ColoredBox{}, size); len(got.Anchors) != 0 || len(got.Baselines) != 0 {
+		t.Errorf("Expected no guides for objects that aren't a Guider, got %v", got)
+	}
+}
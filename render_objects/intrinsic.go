@@ -0,0 +1,110 @@
+package render_objects
+
+import (
+	"math"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// unboundedExtent is the extent offered to objects that don't implement IntrinsicSizer
+const unboundedExtent = math.MaxInt32
+
+// MinIntrinsicWidth returns the minimum intrinsic width of obj at the given height.
+// Objects that don't implement IntrinsicSizer are measured with an unbounded width.
+func MinIntrinsicWidth(obj RenderObject, height int) int {
+	if sizer, ok := obj.(IntrinsicSizer); ok {
+		return sizer.MinIntrinsicWidth(height)
+	}
+	return obj.Size(types.Size{Width: unboundedExtent, Height: height}).Width
+}
+
+// MaxIntrinsicWidth returns the maximum intrinsic width of obj at the given height
+func MaxIntrinsicWidth(obj RenderObject, height int) int {
+	if sizer, ok := obj.(IntrinsicSizer); ok {
+		return sizer.MaxIntrinsicWidth(height)
+	}
+	return obj.Size(types.Size{Width: unboundedExtent, Height: height}).Width
+}
+
+// MinIntrinsicHeight returns the minimum intrinsic height of obj at the given width
+func MinIntrinsicHeight(obj RenderObject, width int) int {
+	if sizer, ok := obj.(IntrinsicSizer); ok {
+		return sizer.MinIntrinsicHeight(width)
+	}
+	return obj.Size(types.Size{Width: width, Height: unboundedExtent}).Height
+}
+
+// MaxIntrinsicHeight returns the maximum intrinsic height of obj at the given width
+func MaxIntrinsicHeight(obj RenderObject, width int) int {
+	if sizer, ok := obj.(IntrinsicSizer); ok {
+		return sizer.MaxIntrinsicHeight(width)
+	}
+	return obj.Size(types.Size{Width: width, Height: unboundedExtent}).Height
+}
+
+// IntrinsicWidth sizes its child to the child's maximum intrinsic width.
+// Wrapping a Column lets children that expand horizontally match the widest child.
+type IntrinsicWidth struct {
+	Child RenderObject
+}
+
+func (i *IntrinsicWidth) Paint(canvas *cv.Canvas) {
+	PaintChild(i.Child, canvas)
+}
+
+func (i *IntrinsicWidth) Size(parentSize types.Size) types.Size {
+	width := min(MaxIntrinsicWidth(i.Child, parentSize.Height), parentSize.Width)
+	size := i.Child.Size(types.Size{Width: width, Height: parentSize.Height})
+	size.Width = width
+	return size
+}
+
+func (i *IntrinsicWidth) MinIntrinsicWidth(height int) int {
+	return MaxIntrinsicWidth(i.Child, height)
+}
+
+func (i *IntrinsicWidth) MaxIntrinsicWidth(height int) int {
+	return MaxIntrinsicWidth(i.Child, height)
+}
+
+func (i *IntrinsicWidth) MinIntrinsicHeight(width int) int {
+	return MinIntrinsicHeight(i.Child, width)
+}
+
+func (i *IntrinsicWidth) MaxIntrinsicHeight(width int) int {
+	return MaxIntrinsicHeight(i.Child, width)
+}
+
+// IntrinsicHeight sizes its child to the child's maximum intrinsic height.
+// Wrapping a Row lets children that expand vertically match the tallest child.
+type IntrinsicHeight struct {
+	Child RenderObject
+}
+
+func (i *IntrinsicHeight) Paint(canvas *cv.Canvas) {
+	PaintChild(i.Child, canvas)
+}
+
+func (i *IntrinsicHeight) Size(parentSize types.Size) types.Size {
+	height := min(MaxIntrinsicHeight(i.Child, parentSize.Width), parentSize.Height)
+	size := i.Child.Size(types.Size{Width: parentSize.Width, Height: height})
+	size.Height = height
+	return size
+}
+
+func (i *IntrinsicHeight) MinIntrinsicWidth(height int) int {
+	return MinIntrinsicWidth(i.Child, height)
+}
+
+func (i *IntrinsicHeight) MaxIntrinsicWidth(height int) int {
+	return MaxIntrinsicWidth(i.Child, height)
+}
+
+func (i *IntrinsicHeight) MinIntrinsicHeight(width int) int {
+	return MaxIntrinsicHeight(i.Child, width)
+}
+
+func (i *IntrinsicHeight) MaxIntrinsicHeight(width int) int {
+	return MaxIntrinsicHeight(i.Child, width)
+}
@@ -0,0 +1,96 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestIntrinsicSizes(t *testing.T) {
+	small := &ColoredBox{Width: 20, Height: 10}
+	large := &ColoredBox{Width: 50, Height: 30}
+
+	row := &Row{Children: []RenderObject{small, large}, Sizing: types.MainAxisSizeMax}
+	if w := MaxIntrinsicWidth(row, 100); w != 70 {
+		t.Errorf("Expected row intrinsic width 70, got %d", w)
+	}
+	if h := MaxIntrinsicHeight(row, 100); h != 30 {
+		t.Errorf("Expected row intrinsic height 30, got %d", h)
+	}
+
+	column := &Column{Children: []RenderObject{small, large}, Sizing: types.MainAxisSizeMax}
+	if w := MaxIntrinsicWidth(column, 100); w != 50 {
+		t.Errorf("Expected column intrinsic width 50, got %d", w)
+	}
+	if h := MaxIntrinsicHeight(column, 100); h != 40 {
+		t.Errorf("Expected column intrinsic height 40, got %d", h)
+	}
+
+	padded := NewPadding(column, 5)
+	if w := MinIntrinsicWidth(padded, 100); w != 60 {
+		t.Errorf("Expected padded intrinsic width 60, got %d", w)
+	}
+
+	// Expanding boxes have no intrinsic size of their own
+	if w := MaxIntrinsicWidth(NewExpandedBox(nil), 100); w != 0 {
+		t.Errorf("Expected expanded box intrinsic width 0, got %d", w)
+	}
+}
+
+func TestTextIntrinsicSizes(t *testing.T) {
+	text := NewWrappedText("short extraordinarily", color.Black, 16, "default")
+
+	minWidth := MinIntrinsicWidth(text, 0)
+	maxWidth := MaxIntrinsicWidth(text, 0)
+	if minWidth <= 0 || minWidth >= maxWidth {
+		t.Errorf("Expected 0 < min width (%d) < max width (%d)", minWidth, maxWidth)
+	}
+
+	// At its minimum width each word sits on its own line
+	oneLine := MaxIntrinsicHeight(text, maxWidth)
+	if twoLines := MaxIntrinsicHeight(text, minWidth); twoLines != 2*oneLine {
+		t.Errorf("Expected height %d at the minimum width, got %d", 2*oneLine, twoLines)
+	}
+}
+
+func TestIntrinsicWidth(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 200, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	transparent := color.RGBA{0, 0, 0, 0}
+
+	// The divider expands to the column width, which is the widest child
+	divider := &SizedBox{Child: &Painter{
+		Painter: func(c *cv.Canvas) { c.Rectangle(0, 0, c.Size.Width, c.Size.Height, blue, true) },
+	}, Width: Px(SizeExpand), Height: Px(4)}
+	column := &IntrinsicWidth{Child: &Column{Children: []RenderObject{
+		&ColoredBox{Width: 60, Height: 20, Color: red},
+		divider,
+		&ColoredBox{Width: 30, Height: 20, Color: red},
+	}}}
+
+	size := column.Size(canvas.Size)
+	if size.Width != 60 || size.Height != 44 {
+		t.Errorf("Expected column size 60x44, got %v", size)
+	}
+
+	column.Paint(canvas.SubCanvas(0, 0, size, nil))
+	if canvas.Img.At(59, 21) != blue || canvas.Img.At(60, 21) != transparent {
+		t.Error("Expected the divider to stretch exactly to the widest child")
+	}
+}
+
+func TestIntrinsicHeight(t *testing.T) {
+	row := &IntrinsicHeight{Child: &Row{Children: []RenderObject{
+		&ColoredBox{Width: 20, Height: 40},
+		&SizedBox{Width: Px(2), Height: Px(SizeExpand)},
+		&ColoredBox{Width: 20, Height: 25},
+	}}}
+
+	size := row.Size(types.Size{Width: 200, Height: 100})
+	if size.Width != 42 || size.Height != 40 {
+		t.Errorf("Expected row size 42x40, got %v", size)
+	}
+}
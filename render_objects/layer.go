@@ -0,0 +1,59 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// paintLayer paints child on a new offscreen layer of size, surrounded by margin
+// empty pixels on each side so effects have room to spread. The layer matches
+// the pixel ratio of the canvas it will be drawn on, and tracers see the child
+// where it would be if the layer was drawn at (-margin, -margin).
+func paintLayer(canvas *cv.Canvas, child RenderObject, size types.Size, margin int) *cv.Layer {
+	layer := canvas.NewOffscreenLayer(types.Size{Width: size.Width + 2*margin, Height: size.Height + 2*margin})
+	layer.Follow(canvas, -margin, -margin)
+	PaintChild(child, layer.SubCanvas(margin, margin, size, nil))
+	return layer
+}
+
+// Composite paints its child into an offscreen layer and composites the result
+// onto the canvas as a single image, with opacity, a blend mode and an optional mask.
+// Opacity is taken as given, so the zero value is invisible: create composites with
+// NewComposite, which starts them opaque, or set Opacity.
+type Composite struct {
+	Child     RenderObject
+	Opacity   float64 // From 0 (invisible) to 1 (opaque), 0 when left unset
+	BlendMode cv.BlendMode
+	Mask      RenderObject // Optional, painted at the child's size; its alpha scales the child
+
+	LinearBlending bool // Composite in linear light instead of gamma-encoded sRGB
+}
+
+// NewComposite creates a fully opaque Composite with normal blending
+func NewComposite(child RenderObject) *Composite {
+	return &Composite{Child: child, Opacity: 1, BlendMode: cv.BlendNormal}
+}
+
+func (c *Composite) Paint(canvas *cv.Canvas) {
+	if c.Opacity <= 0 {
+		return
+	}
+
+	if c.Mask == nil && !c.LinearBlending {
+		canvas.PaintLayer(c.Opacity, c.BlendMode, func(layer *cv.Canvas) { PaintChild(c.Child, layer) })
+		return
+	}
+
+	layer := paintLayer(canvas, c.Child, canvas.Size, 0)
+	layer.Opacity = c.Opacity
+	layer.BlendMode = c.BlendMode
+	layer.LinearBlending = c.LinearBlending
+	if c.Mask != nil {
+		layer.Mask = paintLayer(canvas, c.Mask, canvas.Size, 0).Canvas
+	}
+	canvas.DrawLayer(layer, 0, 0)
+}
+
+func (c *Composite) Size(parentSize types.Size) types.Size {
+	return c.Child.Size(parentSize)
+}
@@ -0,0 +1,62 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestCompositeOpacityDefault(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	box := &ColoredBox{Width: 10, Height: 10, Color: red}
+
+	// NewComposite starts opaque, the zero value is invisible as documented
+	canvas := cv.NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	NewComposite(box).Paint(canvas)
+	if canvas.Img.At(5, 5) != red {
+		t.Error("Expected NewComposite to paint its child opaque")
+	}
+	canvas = cv.NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	(&Composite{Child: box}).Paint(canvas)
+	if canvas.Img.RGBAAt(5, 5).A != 0 {
+		t.Error("Expected a Composite without opacity to paint nothing")
+	}
+}
+
+func TestCompositeBlendMode(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 10, Height: 10}, false)
+	gray := color.RGBA{128, 128, 128, 255}
+	canvas.Rectangle(0, 0, 10, 10, gray, true)
+
+	composite := NewComposite(&ColoredBox{Width: 10, Height: 10, Color: color.RGBA{255, 0, 0, 255}})
+	composite.BlendMode = cv.BlendMultiply
+	composite.Paint(canvas)
+
+	// Multiplying red with gray keeps only half of the red channel
+	if c := canvas.Img.RGBAAt(5, 5); c != (color.RGBA{128, 0, 0, 255}) {
+		t.Errorf("Expected dark red, got %v", c)
+	}
+}
+
+func TestCompositeMask(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 40, Height: 40}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	// A circular mask cuts the corners off the box
+	composite := NewComposite(&ColoredBox{Width: 40, Height: 40, Color: red})
+	composite.Mask = &Painter{
+		Painter: func(c *cv.Canvas) { c.Circle(20, 20, 19, color.RGBA{0, 0, 0, 255}, true) },
+		Width:   40,
+		Height:  40,
+	}
+	composite.Paint(canvas)
+
+	if canvas.Img.At(20, 20) != red {
+		t.Error("Expected the center to be visible through the mask")
+	}
+	if canvas.Img.RGBAAt(1, 1).A != 0 {
+		t.Error("Expected the corner to be masked out")
+	}
+}
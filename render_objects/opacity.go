@@ -0,0 +1,28 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Opacity fades its whole subtree as one image, so overlapping children
+// don't show through each other
+type Opacity struct {
+	Child   RenderObject
+	Opacity float64 // From 0 (invisible) to 1 (opaque)
+}
+
+func (o *Opacity) Paint(canvas *cv.Canvas) {
+	// Fully opaque subtrees don't need an offscreen layer
+	if o.Opacity >= 1 {
+		PaintChild(o.Child, canvas)
+		return
+	}
+
+	composite := &Composite{Child: o.Child, Opacity: o.Opacity}
+	composite.Paint(canvas)
+}
+
+func (o *Opacity) Size(parentSize types.Size) types.Size {
+	return o.Child.Size(parentSize)
+}
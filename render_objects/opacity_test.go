@@ -0,0 +1,48 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestOpacity(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// Overlapping boxes faded as one image
+	opacity := &Opacity{
+		Child: &Stack{Children: []RenderObject{
+			&ColoredBox{Width: 50, Height: 50, Color: red},
+			&ColoredBox{Width: 25, Height: 25, Color: blue},
+		}},
+		Opacity: 0.5,
+	}
+
+	// Test Size method
+	size := opacity.Size(canvas.Size)
+	if size.Width != 50 || size.Height != 50 {
+		t.Errorf("Expected opacity to keep the child size 50x50, got %v", size)
+	}
+
+	// Test Paint method
+	opacity.Paint(canvas.SubCanvas(0, 0, size, nil))
+
+	// The red box doesn't show through the blue one
+	if c := canvas.Img.RGBAAt(10, 10); c.R != 0 || c.B < 126 || c.B > 129 || c.A < 126 || c.A > 129 {
+		t.Errorf("Expected half transparent blue, got %v", c)
+	}
+	if c := canvas.Img.RGBAAt(40, 40); c.R < 126 || c.R > 129 || c.B != 0 {
+		t.Errorf("Expected half transparent red, got %v", c)
+	}
+
+	// Zero opacity paints nothing
+	canvas = cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	(&Opacity{Child: &ColoredBox{Width: 50, Height: 50, Color: red}}).Paint(canvas)
+	if canvas.Img.RGBAAt(10, 10).A != 0 {
+		t.Error("Expected nothing to be painted at zero opacity")
+	}
+}
@@ -45,7 +45,7 @@ func (p *Padding) Paint(canvas *cv.Canvas) {
 	childCanvas := canvas.SubCanvas(p.Left, p.Top, childSize, nil)
 
 	// Paint the child on the subcanvas
-	p.Child.Paint(childCanvas)
+	PaintChild(p.Child, childCanvas)
 }
 
 func (p *Padding) Size(parentSize types.Size) types.Size {
@@ -64,3 +64,23 @@ func (p *Padding) Size(parentSize types.Size) types.Size {
 		Height: childActualSize.Height + p.Top + p.Bottom,
 	}
 }
+
+func (p *Padding) MinIntrinsicWidth(height int) int {
+	return MinIntrinsicWidth(p.Child, max(height-p.Top-p.Bottom, 0)) + p.Left + p.Right
+}
+
+func (p *Padding) MaxIntrinsicWidth(height int) int {
+	return MaxIntrinsicWidth(p.Child, max(height-p.Top-p.Bottom, 0)) + p.Left + p.Right
+}
+
+func (p *Padding) MinIntrinsicHeight(width int) int {
+	return MinIntrinsicHeight(p.Child, max(width-p.Left-p.Right, 0)) + p.Top + p.Bottom
+}
+
+func (p *Padding) MaxIntrinsicHeight(width int) int {
+	return MaxIntrinsicHeight(p.Child, max(width-p.Left-p.Right, 0)) + p.Top + p.Bottom
+}
+
+func (p *Padding) Guides(size types.Size) Guides {
+	return Guides{Padding: types.EdgeInsets{Top: p.Top, Right: p.Right, Bottom: p.Bottom, Left: p.Left}}
+}
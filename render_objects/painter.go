@@ -18,3 +18,8 @@ func (p *Painter) Paint(canvas *cv.Canvas) {
 func (p *Painter) Size(parentSize types.Size) types.Size {
 	return types.Size{Width: p.Width, Height: p.Height}
 }
+
+func (p *Painter) MinIntrinsicWidth(height int) int { return p.Width }
+func (p *Painter) MaxIntrinsicWidth(height int) int { return p.Width }
+func (p *Painter) MinIntrinsicHeight(width int) int { return p.Height }
+func (p *Painter) MaxIntrinsicHeight(width int) int { return p.Height }
@@ -9,3 +9,33 @@ type RenderObject interface {
 	Paint(canvas *canvas.Canvas)
 	Size(parentSize types.Size) types.Size
 }
+
+// IntrinsicSizer is implemented by render objects that can report their natural
+// extent along one axis given a fixed extent along the other, independently of
+// the space their parent offers.
+type IntrinsicSizer interface {
+	// MinIntrinsicWidth is the smallest width the object can be painted in without overflowing at the given height
+	MinIntrinsicWidth(height int) int
+	// MaxIntrinsicWidth is the width beyond which growing wider doesn't reduce the height
+	MaxIntrinsicWidth(height int) int
+	// MinIntrinsicHeight is the smallest height the object can be painted in at the given width
+	MinIntrinsicHeight(width int) int
+	// MaxIntrinsicHeight is the height beyond which growing taller doesn't reduce the width
+	MaxIntrinsicHeight(width int) int
+}
+
+// PaintChild paints child on canvas. Render objects with children paint them through it
+// rather than calling Paint directly, so the tracer of the canvas sees every child and
+// painting stops once the deadline of the canvas has passed.
+func PaintChild(child RenderObject, canvas *canvas.Canvas) {
+	canvas.CheckDeadline()
+	tracer := canvas.Tracer()
+	if tracer == nil {
+		child.Paint(canvas)
+		return
+	}
+
+	tracer.BeginPaint(child, canvas)
+	child.Paint(canvas)
+	tracer.EndPaint(child, canvas)
+}
@@ -96,7 +96,7 @@ func (r *Row) Paint(canvas *cv.Canvas) {
 	// Draw children at calculated positions
 	for i, child := range r.Children {
 		childCanvas := canvas.SubCanvas(xOffsets[i], 0, childSizes[i], nil)
-		child.Paint(childCanvas)
+		PaintChild(child, childCanvas)
 	}
 }
 
@@ -135,3 +135,37 @@ func (r *Row) Size(parentSize types.Size) types.Size {
 
 	return size
 }
+
+// Intrinsic widths add up along the main axis, heights take the tallest child
+
+func (r *Row) MinIntrinsicWidth(height int) int {
+	total := 0
+	for _, child := range r.Children {
+		total += MinIntrinsicWidth(child, height)
+	}
+	return total
+}
+
+func (r *Row) MaxIntrinsicWidth(height int) int {
+	total := 0
+	for _, child := range r.Children {
+		total += MaxIntrinsicWidth(child, height)
+	}
+	return total
+}
+
+func (r *Row) MinIntrinsicHeight(width int) int {
+	tallest := 0
+	for _, child := range r.Children {
+		tallest = max(tallest, MinIntrinsicHeight(child, width))
+	}
+	return tallest
+}
+
+func (r *Row) MaxIntrinsicHeight(width int) int {
+	tallest := 0
+	for _, child := range r.Children {
+		tallest = max(tallest, MaxIntrinsicHeight(child, width))
+	}
+	return tallest
+}
@@ -0,0 +1,32 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Semantic names the region its child paints, so the region can be found by hit testing
+// and exported as an image map. The child is painted at its own size in the top left
+// corner, which is the region, rather than across all the space the parent offered,
+// and is clipped to that space when it is larger.
+type Semantic struct {
+	Child RenderObject
+	ID    string
+	Link  string // URL the region leads to, optional
+	Label string // Text describing the region, such as the alt text of an image map area
+}
+
+func (s *Semantic) Paint(canvas *cv.Canvas) {
+	size := s.Child.Size(canvas.Size)
+	size = types.Size{Width: min(size.Width, canvas.Size.Width), Height: min(size.Height, canvas.Size.Height)}
+	PaintChild(s.Child, canvas.SubCanvas(0, 0, size, nil))
+}
+
+func (s *Semantic) Size(parentSize types.Size) types.Size {
+	return s.Child.Size(parentSize)
+}
+
+func (s *Semantic) MinIntrinsicWidth(height int) int { return MinIntrinsicWidth(s.Child, height) }
+func (s *Semantic) MaxIntrinsicWidth(height int) int { return MaxIntrinsicWidth(s.Child, height) }
+func (s *Semantic) MinIntrinsicHeight(width int) int { return MinIntrinsicHeight(s.Child, width) }
+func (s *Semantic) MaxIntrinsicHeight(width int) int { return MaxIntrinsicHeight(s.Child, width) }
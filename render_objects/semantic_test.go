@@ -0,0 +1,27 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestSemanticClipsToParent(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 40, Height: 20}, false)
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// A box larger than the space offered stays inside it, as it does unwrapped
+	width, height := 40, 30
+	fill := &DecoratedBox{Decoration: BoxDecoration{Background: cv.SolidColor{Color: blue}}}
+	semantic := &Semantic{ID: "box", Child: &SizedBox{Width: &width, Height: &height, Child: fill}}
+	semantic.Paint(canvas.SubCanvas(0, 0, types.Size{Width: 20, Height: 20}, nil))
+
+	if c := canvas.Img.RGBAAt(10, 10); c != blue {
+		t.Errorf("Expected the region to be painted blue, got %v", c)
+	}
+	if c := canvas.Img.RGBAAt(30, 10); c.A != 0 {
+		t.Errorf("Expected nothing painted past the parent, got %v", c)
+	}
+}
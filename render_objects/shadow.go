@@ -0,0 +1,64 @@
+package render_objects
+
+import (
+	"image/color"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Shadow paints a blurred drop shadow following the shape of its child's opaque pixels,
+// then the child on top of it. The shadow's extent is added around the child so it is
+// never clipped by the parent.
+type Shadow struct {
+	Child   RenderObject
+	Color   color.RGBA
+	OffsetX int
+	OffsetY int
+	Blur    float64 // Standard deviation of the shadow blur in pixels
+}
+
+// insets returns the space reserved on each side of the child for the shadow
+func (s *Shadow) insets() types.EdgeInsets {
+	margin := blurMargin(s.Blur)
+	return types.EdgeInsets{
+		Top:    max(margin-s.OffsetY, 0),
+		Right:  max(margin+s.OffsetX, 0),
+		Bottom: max(margin+s.OffsetY, 0),
+		Left:   max(margin-s.OffsetX, 0),
+	}
+}
+
+func (s *Shadow) Paint(canvas *cv.Canvas) {
+	insets := s.insets()
+	childSize := types.Size{
+		Width:  canvas.Size.Width - insets.Horizontal(),
+		Height: canvas.Size.Height - insets.Vertical(),
+	}
+
+	// The silhouette is painted without tracing, tracers only see the child on top of it
+	margin := blurMargin(s.Blur)
+	layer := canvas.NewOffscreenLayer(types.Size{Width: childSize.Width + 2*margin, Height: childSize.Height + 2*margin})
+	s.Child.Paint(layer.SubCanvas(margin, margin, childSize, nil))
+	layer.Colorize(s.Color)
+	layer.Blur(s.Blur)
+	canvas.DrawLayer(layer, insets.Left+s.OffsetX-margin, insets.Top+s.OffsetY-margin)
+
+	PaintChild(s.Child, canvas.SubCanvas(insets.Left, insets.Top, childSize, nil))
+}
+
+func (s *Shadow) Size(parentSize types.Size) types.Size {
+	insets := s.insets()
+	childSize := s.Child.Size(types.Size{
+		Width:  parentSize.Width - insets.Horizontal(),
+		Height: parentSize.Height - insets.Vertical(),
+	})
+	return types.Size{
+		Width:  childSize.Width + insets.Horizontal(),
+		Height: childSize.Height + insets.Vertical(),
+	}
+}
+
+func (s *Shadow) Guides(size types.Size) Guides {
+	return Guides{Padding: s.insets()}
+}
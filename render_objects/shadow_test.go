@@ -0,0 +1,45 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestShadow(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	black := color.RGBA{0, 0, 0, 255}
+
+	shadow := &Shadow{
+		Child:   &ColoredBox{Width: 20, Height: 20, Color: red},
+		Color:   black,
+		OffsetX: 6,
+		OffsetY: 6,
+		Blur:    2,
+	}
+
+	// Room is reserved below and to the right for the offset shadow and its blur
+	size := shadow.Size(canvas.Size)
+	if size.Width != 20+6+6 || size.Height != 20+6+6 {
+		t.Fatalf("Expected shadow size 32x32, got %v", size)
+	}
+
+	shadow.Paint(canvas.SubCanvas(10, 10, size, nil))
+
+	// The child is painted over its shadow
+	if canvas.Img.At(10, 10) != red || canvas.Img.At(29, 29) != red {
+		t.Error("Expected the child to be drawn on top of the shadow")
+	}
+
+	// The shadow shows up below and to the right of the child
+	below := canvas.Img.RGBAAt(20, 37)
+	if below.A == 0 || below.R != 0 {
+		t.Errorf("Expected a black shadow below the child, got %v", below)
+	}
+	if a := canvas.Img.RGBAAt(9, 9).A; a != 0 {
+		t.Errorf("Expected no shadow above and to the left, got alpha %d", a)
+	}
+}
@@ -0,0 +1,85 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// SizeExpand can be used as a SizedBox dimension to take the parent's full extent
+const SizeExpand = -1
+
+// SizedBox forces its child to a given size.
+// A nil dimension follows the child, SizeExpand follows the parent.
+// Without a child it is an empty box, useful as a spacer.
+type SizedBox struct {
+	Child  RenderObject
+	Width  *int
+	Height *int
+}
+
+// NewSizedBox creates a SizedBox with a fixed width and height
+func NewSizedBox(child RenderObject, width, height int) *SizedBox {
+	return &SizedBox{Child: child, Width: Px(width), Height: Px(height)}
+}
+
+// NewExpandedBox creates a SizedBox that fills its parent
+func NewExpandedBox(child RenderObject) *SizedBox {
+	return &SizedBox{Child: child, Width: Px(SizeExpand), Height: Px(SizeExpand)}
+}
+
+func (s *SizedBox) Paint(canvas *cv.Canvas) {
+	if s.Child == nil {
+		return
+	}
+	PaintChild(s.Child, canvas)
+}
+
+func (s *SizedBox) Size(parentSize types.Size) types.Size {
+	var size types.Size
+	if s.Child != nil && (s.Width == nil || s.Height == nil) {
+		size = s.Child.Size(parentSize)
+	}
+
+	size.Width = resolveSizedDimension(s.Width, size.Width, parentSize.Width)
+	size.Height = resolveSizedDimension(s.Height, size.Height, parentSize.Height)
+	return size
+}
+
+func resolveSizedDimension(value *int, childValue, parentValue int) int {
+	switch {
+	case value == nil:
+		return childValue
+	case *value == SizeExpand:
+		return parentValue
+	default:
+		return *value
+	}
+}
+
+// Fixed dimensions are their own intrinsic size, expanding dimensions defer to the child
+
+func (s *SizedBox) intrinsic(value *int, measure func(child RenderObject) int) int {
+	if value != nil && *value != SizeExpand {
+		return *value
+	}
+	if s.Child == nil {
+		return 0
+	}
+	return measure(s.Child)
+}
+
+func (s *SizedBox) MinIntrinsicWidth(height int) int {
+	return s.intrinsic(s.Width, func(child RenderObject) int { return MinIntrinsicWidth(child, height) })
+}
+
+func (s *SizedBox) MaxIntrinsicWidth(height int) int {
+	return s.intrinsic(s.Width, func(child RenderObject) int { return MaxIntrinsicWidth(child, height) })
+}
+
+func (s *SizedBox) MinIntrinsicHeight(width int) int {
+	return s.intrinsic(s.Height, func(child RenderObject) int { return MinIntrinsicHeight(child, width) })
+}
+
+func (s *SizedBox) MaxIntrinsicHeight(width int) int {
+	return s.intrinsic(s.Height, func(child RenderObject) int { return MaxIntrinsicHeight(child, width) })
+}
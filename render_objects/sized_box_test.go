@@ -0,0 +1,34 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hvuhsg/render/types"
+)
+
+func TestSizedBox(t *testing.T) {
+	parent := types.Size{Width: 200, Height: 100}
+	box := &ColoredBox{Width: 50, Height: 30, Color: color.RGBA{255, 0, 0, 255}}
+
+	fixed := NewSizedBox(box, 80, 40)
+	if size := fixed.Size(parent); size.Width != 80 || size.Height != 40 {
+		t.Errorf("Expected fixed size 80x40, got %v", size)
+	}
+
+	expanded := NewExpandedBox(box)
+	if size := expanded.Size(parent); size != parent {
+		t.Errorf("Expected expanded box to fill %v, got %v", parent, size)
+	}
+
+	// Only the width is forced, the height follows the child
+	widthOnly := &SizedBox{Child: box, Width: Px(120)}
+	if size := widthOnly.Size(parent); size.Width != 120 || size.Height != 30 {
+		t.Errorf("Expected size 120x30, got %v", size)
+	}
+
+	spacer := &SizedBox{Height: Px(16)}
+	if size := spacer.Size(parent); size.Width != 0 || size.Height != 16 {
+		t.Errorf("Expected spacer size 0x16, got %v", size)
+	}
+}
@@ -1,33 +1,81 @@
 package render_objects
 
 import (
+	"fmt"
+	"image"
+
 	cv "github.com/hvuhsg/render/canvas"
 	types "github.com/hvuhsg/render/types"
 )
 
+// StackFit controls how non-positioned children of a Stack are sized
+type StackFit int
+
+const (
+	StackFitLoose       StackFit = iota // Children keep their own size
+	StackFitExpand                      // Children and the stack fill the parent
+	StackFitPassthrough                 // Children receive the stack's full canvas, the stack keeps its own size
+)
+
+func (f StackFit) String() string {
+	switch f {
+	case StackFitLoose:
+		return "loose"
+	case StackFitExpand:
+		return "expand"
+	case StackFitPassthrough:
+		return "passthrough"
+	}
+	return fmt.Sprintf("StackFit(%d)", int(f))
+}
+
+// Stack paints its children on top of each other, the first child at the bottom.
+// Positioned children are placed relative to the stack's edges and do not affect its size.
 type Stack struct {
-	Children   []RenderObject
-	cachedSize *types.Size
+	Children       []RenderObject
+	Alignment      AlignType // Alignment of non-positioned children, top left by default
+	Fit            StackFit
+	cachedSize     *types.Size
+	lastParentSize types.Size
 }
 
 func (s *Stack) Paint(canvas *cv.Canvas) {
 	for _, child := range s.Children {
+		if positioned, ok := child.(*Positioned); ok {
+			x, y, size := positioned.layout(canvas.Size, s.Alignment)
+			PaintChild(positioned, canvas.SubCanvas(x, y, size, nil))
+			continue
+		}
+
+		if s.Fit != StackFitLoose {
+			PaintChild(child, canvas.SubCanvas(0, 0, canvas.Size, nil))
+			continue
+		}
+
 		childSize := child.Size(canvas.Size)
-		childCanvas := canvas.SubCanvas(0, 0, childSize, nil)
-		child.Paint(childCanvas)
+		x, y := s.Alignment.offset(canvas.Size, childSize)
+		childCanvas := canvas.SubCanvas(x, y, childSize, nil)
+		PaintChild(child, childCanvas)
 	}
 }
 
 func (s *Stack) Size(parentSize types.Size) types.Size {
-	if s.cachedSize != nil {
+	// Check if we can use cached size
+	if s.cachedSize != nil && s.lastParentSize == parentSize {
 		return *s.cachedSize
 	}
 
 	maxHeight := 0
 	maxWidth := 0
+	hasNonPositioned := false
 
-	// Calculate sizes in a single pass
+	// Calculate sizes in a single pass, positioned children don't contribute
 	for _, child := range s.Children {
+		if _, ok := child.(*Positioned); ok {
+			continue
+		}
+		hasNonPositioned = true
+
 		size := child.Size(parentSize)
 		if size.Height > maxHeight {
 			maxHeight = size.Height
@@ -38,7 +86,120 @@ func (s *Stack) Size(parentSize types.Size) types.Size {
 	}
 
 	size := types.Size{Width: maxWidth, Height: maxHeight}
+	if s.Fit == StackFitExpand || !hasNonPositioned {
+		size = parentSize
+	}
+
 	s.cachedSize = &size
+	s.lastParentSize = parentSize
+
+	return size
+}
+
+func (s *Stack) Guides(size types.Size) Guides {
+	return Guides{Anchors: []image.Point{s.Alignment.anchor(size)}}
+}
+
+// Intrinsic sizes of a stack are those of its largest non-positioned child
+
+func (s *Stack) intrinsic(measure func(child RenderObject) int) int {
+	largest := 0
+	for _, child := range s.Children {
+		if _, ok := child.(*Positioned); ok {
+			continue
+		}
+		largest = max(largest, measure(child))
+	}
+	return largest
+}
+
+func (s *Stack) MinIntrinsicWidth(height int) int {
+	return s.intrinsic(func(child RenderObject) int { return MinIntrinsicWidth(child, height) })
+}
+
+func (s *Stack) MaxIntrinsicWidth(height int) int {
+	return s.intrinsic(func(child RenderObject) int { return MaxIntrinsicWidth(child, height) })
+}
+
+func (s *Stack) MinIntrinsicHeight(width int) int {
+	return s.intrinsic(func(child RenderObject) int { return MinIntrinsicHeight(child, width) })
+}
+
+func (s *Stack) MaxIntrinsicHeight(width int) int {
+	return s.intrinsic(func(child RenderObject) int { return MaxIntrinsicHeight(child, width) })
+}
+
+// Positioned places its child inside a Stack relative to the stack's edges.
+// Unset edges fall back to the stack's alignment; setting both opposite edges stretches the child.
+type Positioned struct {
+	Child  RenderObject
+	Left   *int
+	Top    *int
+	Right  *int
+	Bottom *int
+	Width  *int
+	Height *int
+}
+
+// Px returns a pointer to v, for use with the optional fields of Positioned
+func Px(v int) *int {
+	return &v
+}
+
+// layout returns the offset and size of the child inside a stack of stackSize
+func (p *Positioned) layout(stackSize types.Size, alignment AlignType) (x, y int, size types.Size) {
+	// Space the child is allowed to measure itself in
+	available := stackSize
+	if p.Left != nil {
+		available.Width -= *p.Left
+	}
+	if p.Right != nil {
+		available.Width -= *p.Right
+	}
+	if p.Top != nil {
+		available.Height -= *p.Top
+	}
+	if p.Bottom != nil {
+		available.Height -= *p.Bottom
+	}
+
+	size = p.Child.Size(available)
+	switch {
+	case p.Width != nil:
+		size.Width = *p.Width
+	case p.Left != nil && p.Right != nil:
+		size.Width = available.Width
+	}
+	switch {
+	case p.Height != nil:
+		size.Height = *p.Height
+	case p.Top != nil && p.Bottom != nil:
+		size.Height = available.Height
+	}
+
+	// Edges that aren't pinned take their position from the stack's alignment
+	x, y = alignment.offset(stackSize, size)
+	switch {
+	case p.Left != nil:
+		x = *p.Left
+	case p.Right != nil:
+		x = stackSize.Width - *p.Right - size.Width
+	}
+	switch {
+	case p.Top != nil:
+		y = *p.Top
+	case p.Bottom != nil:
+		y = stackSize.Height - *p.Bottom - size.Height
+	}
+
+	return x, y, size
+}
+
+func (p *Positioned) Paint(canvas *cv.Canvas) {
+	PaintChild(p.Child, canvas)
+}
 
+func (p *Positioned) Size(parentSize types.Size) types.Size {
+	_, _, size := p.layout(parentSize, AlignTopLeft)
 	return size
 }
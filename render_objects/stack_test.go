@@ -52,3 +52,111 @@ func TestStack(t *testing.T) {
 		t.Error("Expected blue pixels to be drawn for the top box")
 	}
 }
+
+func TestStackAlignment(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	stack := &Stack{
+		Children: []RenderObject{
+			&ColoredBox{Width: 100, Height: 100, Color: red},
+			&ColoredBox{Width: 20, Height: 20, Color: blue},
+		},
+		Alignment: AlignCenter,
+	}
+	stack.Paint(canvas)
+
+	if canvas.Img.At(50, 50) != blue {
+		t.Error("Expected the small box to be centered in the stack")
+	}
+	if canvas.Img.At(10, 10) != red {
+		t.Error("Expected the large box to show outside the centered box")
+	}
+}
+
+func TestStackPositioned(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	green := color.RGBA{0, 255, 0, 255}
+
+	stack := &Stack{
+		Children: []RenderObject{
+			&ColoredBox{Width: 80, Height: 80, Color: red},
+			// Badge pinned to the top right corner
+			&Positioned{
+				Child: &ColoredBox{Width: 10, Height: 10, Color: blue},
+				Top:   Px(0),
+				Right: Px(0),
+			},
+			// Stretched between the left and right edges
+			&Positioned{
+				Child: &Painter{
+					Painter: func(c *cv.Canvas) { c.Rectangle(0, 0, c.Size.Width, c.Size.Height, green, true) },
+					Width:   1,
+					Height:  5,
+				},
+				Left:   Px(10),
+				Right:  Px(10),
+				Bottom: Px(0),
+			},
+		},
+	}
+
+	// Positioned children don't affect the stack's size
+	size := stack.Size(types.Size{Width: 80, Height: 80})
+	if size.Width != 80 || size.Height != 80 {
+		t.Errorf("Expected stack size 80x80, got %v", size)
+	}
+
+	sub := canvas.SubCanvas(0, 0, size, nil)
+	stack.Paint(sub)
+
+	if canvas.Img.At(75, 0) != blue || canvas.Img.At(69, 0) != red {
+		t.Error("Expected the badge to be drawn in the top right corner")
+	}
+	if canvas.Img.At(10, 79) != green || canvas.Img.At(69, 79) != green || canvas.Img.At(9, 79) != red {
+		t.Error("Expected the bar to stretch between the left and right insets")
+	}
+}
+
+func TestStackFit(t *testing.T) {
+	box := &ColoredBox{Width: 20, Height: 20, Color: color.RGBA{255, 0, 0, 255}}
+
+	loose := &Stack{Children: []RenderObject{box}}
+	if size := loose.Size(types.Size{Width: 100, Height: 100}); size.Width != 20 || size.Height != 20 {
+		t.Errorf("Expected loose stack to size to its child, got %v", size)
+	}
+
+	expand := &Stack{Children: []RenderObject{box}, Fit: StackFitExpand}
+	if size := expand.Size(types.Size{Width: 100, Height: 100}); size.Width != 100 || size.Height != 100 {
+		t.Errorf("Expected expanded stack to fill its parent, got %v", size)
+	}
+
+	// Children painted with passthrough receive the whole stack canvas
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	var received types.Size
+	passthrough := &Stack{
+		Children: []RenderObject{&Painter{
+			Painter: func(c *cv.Canvas) { received = c.Size },
+			Width:   10,
+			Height:  10,
+		}},
+		Fit: StackFitPassthrough,
+	}
+	passthrough.Paint(canvas)
+	if received != canvas.Size {
+		t.Errorf("Expected passthrough child to receive canvas size %v, got %v", canvas.Size, received)
+	}
+}
+
+func TestStackSizeCacheInvalidation(t *testing.T) {
+	stack := &Stack{Fit: StackFitExpand, Children: []RenderObject{&ColoredBox{Width: 10, Height: 10}}}
+
+	first := stack.Size(types.Size{Width: 100, Height: 100})
+	second := stack.Size(types.Size{Width: 50, Height: 60})
+	if first == second {
+		t.Errorf("Expected stack size to follow the parent size, got %v both times", first)
+	}
+}
@@ -0,0 +1,380 @@
+package render_objects
+
+import (
+	"image/color"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// TableColumnWidthType selects how the width of a table column is computed
+type TableColumnWidthType int
+
+const (
+	TableColumnFlex      TableColumnWidthType = iota // Shares the remaining width proportionally to Value
+	TableColumnFixed                                 // Exactly Value pixels wide
+	TableColumnIntrinsic                             // As wide as the widest cell in the column, wrapping cells that don't fit
+)
+
+// TableColumnWidth describes the sizing strategy of a single table column
+type TableColumnWidth struct {
+	Type  TableColumnWidthType
+	Value float64
+}
+
+// FixedColumnWidth returns a column width of exactly width pixels
+func FixedColumnWidth(width int) TableColumnWidth {
+	return TableColumnWidth{Type: TableColumnFixed, Value: float64(width)}
+}
+
+// FlexColumnWidth returns a column width that takes a share of the remaining space
+func FlexColumnWidth(flex float64) TableColumnWidth {
+	return TableColumnWidth{Type: TableColumnFlex, Value: flex}
+}
+
+// IntrinsicColumnWidth returns a column width that fits the widest cell
+func IntrinsicColumnWidth() TableColumnWidth {
+	return TableColumnWidth{Type: TableColumnIntrinsic}
+}
+
+// TableRule describes a line drawn between or around table cells. Styles are those
+// of borders, solid by default; the zero rule has no width and draws nothing.
+type TableRule struct {
+	Style cv.BorderStyle
+	Width int
+	Color color.RGBA
+}
+
+func (r TableRule) thickness() int {
+	if r.Style == cv.BorderStyleNone {
+		return 0
+	}
+	return r.Width
+}
+
+// Table lays out cells in a grid of header rows followed by body rows.
+// Rules take up their own space between cells so they never cover cell content.
+type Table struct {
+	HeaderRows   [][]RenderObject
+	Rows         [][]RenderObject
+	ColumnWidths []TableColumnWidth // Columns without an entry use FlexColumnWidth(1)
+	CellPadding  types.EdgeInsets
+
+	HeaderColor color.RGBA   // Background of the header rows
+	RowColors   []color.RGBA // Backgrounds of the body rows, cycled; two colors give zebra striping
+
+	HorizontalRule TableRule // Drawn between rows
+	VerticalRule   TableRule // Drawn between columns
+	HeaderRule     TableRule // Drawn below the header rows, defaults to HorizontalRule when zero
+	Border         TableRule // Drawn around the whole table
+
+	cachedLayout   *tableLayout
+	lastParentSize types.Size
+}
+
+type tableLayout struct {
+	columnWidths []int
+	rowHeights   []int
+	size         types.Size
+}
+
+// NewTextTable creates a table of wrapping text cells with the given header and body rows
+func NewTextTable(header []string, rows [][]string, textColor color.Color, fontSize float64) *Table {
+	toCells := func(values []string) []RenderObject {
+		cells := make([]RenderObject, len(values))
+		for i, value := range values {
+			cells[i] = NewWrappedText(value, textColor, fontSize, "default")
+		}
+		return cells
+	}
+
+	table := &Table{CellPadding: types.EdgeInsetsSymmetric(4, 8)}
+	if header != nil {
+		table.HeaderRows = [][]RenderObject{toCells(header)}
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, toCells(row))
+	}
+	return table
+}
+
+func (t *Table) allRows() [][]RenderObject {
+	rows := make([][]RenderObject, 0, len(t.HeaderRows)+len(t.Rows))
+	rows = append(rows, t.HeaderRows...)
+	return append(rows, t.Rows...)
+}
+
+func (t *Table) columnCount() int {
+	count := 0
+	for _, row := range t.allRows() {
+		if len(row) > count {
+			count = len(row)
+		}
+	}
+	return count
+}
+
+func (t *Table) columnWidth(i int) TableColumnWidth {
+	if i < len(t.ColumnWidths) {
+		return t.ColumnWidths[i]
+	}
+	return FlexColumnWidth(1)
+}
+
+func (t *Table) headerRule() TableRule {
+	if t.HeaderRule == (TableRule{}) {
+		return t.HorizontalRule
+	}
+	return t.HeaderRule
+}
+
+// ruleBelow returns the rule drawn below the row at index i of allRows
+func (t *Table) ruleBelow(i int) TableRule {
+	if len(t.HeaderRows) > 0 && i == len(t.HeaderRows)-1 {
+		return t.headerRule()
+	}
+	return t.HorizontalRule
+}
+
+func (t *Table) layout(parentSize types.Size) *tableLayout {
+	if t.cachedLayout != nil && t.lastParentSize == parentSize {
+		return t.cachedLayout
+	}
+
+	rows := t.allRows()
+	columns := t.columnCount()
+	border := t.Border.thickness()
+	verticalRule := t.VerticalRule.thickness()
+
+	// Width left for the columns once the border and vertical rules are taken out
+	available := parentSize.Width - 2*border
+	if columns > 1 {
+		available -= (columns - 1) * verticalRule
+	}
+	available = max(available, 0)
+
+	// Resolve fixed and intrinsic columns first, flex columns share what is left
+	widths := make([]int, columns)
+	used := 0
+	totalFlex := 0.0
+	for i := range columns {
+		width := t.columnWidth(i)
+		switch width.Type {
+		case TableColumnFixed:
+			widths[i] = int(width.Value)
+		case TableColumnIntrinsic:
+			// Fit the cells on one line if there is room, wrap them otherwise but never below their widest word
+			widest := t.intrinsicColumnWidth(rows, i, parentSize.Height, MaxIntrinsicWidth)
+			narrowest := t.intrinsicColumnWidth(rows, i, parentSize.Height, MinIntrinsicWidth)
+			widths[i] = max(min(widest, available-used), narrowest)
+		case TableColumnFlex:
+			totalFlex += width.Value
+			continue
+		}
+		used += widths[i]
+	}
+
+	if totalFlex > 0 {
+		remaining := max(available-used, 0)
+		distributed := 0
+		lastFlex := -1
+		for i := range columns {
+			width := t.columnWidth(i)
+			if width.Type != TableColumnFlex {
+				continue
+			}
+			widths[i] = int(float64(remaining) * width.Value / totalFlex)
+			distributed += widths[i]
+			lastFlex = i
+		}
+		// Give the rounding remainder to the last flex column so the table fills its parent
+		widths[lastFlex] += remaining - distributed
+	}
+
+	// Each row is as tall as its tallest cell laid out at its column's width
+	heights := make([]int, len(rows))
+	for r, row := range rows {
+		for i, cell := range row {
+			if cell == nil {
+				continue
+			}
+			childParent := types.Size{
+				Width:  max(widths[i]-t.CellPadding.Horizontal(), 0),
+				Height: parentSize.Height,
+			}
+			heights[r] = max(heights[r], cell.Size(childParent).Height+t.CellPadding.Vertical())
+		}
+	}
+
+	width := 2 * border
+	for i, w := range widths {
+		width += w
+		if i > 0 {
+			width += verticalRule
+		}
+	}
+	height := 2 * border
+	for r, h := range heights {
+		height += h
+		if r < len(heights)-1 {
+			height += t.ruleBelow(r).thickness()
+		}
+	}
+
+	t.cachedLayout = &tableLayout{
+		columnWidths: widths,
+		rowHeights:   heights,
+		size:         types.Size{Width: width, Height: height},
+	}
+	t.lastParentSize = parentSize
+
+	return t.cachedLayout
+}
+
+func (t *Table) Paint(canvas *cv.Canvas) {
+	layout := t.layout(canvas.Size)
+
+	switch {
+	case t.Border.thickness() == 0:
+		t.paintGrid(canvas, layout)
+	case t.Border.Style == cv.BorderStyleSolid:
+		box := canvas.SubCanvas(0, 0, layout.size, nil)
+		t.paintGrid(box, layout)
+		w, h, b := layout.size.Width, layout.size.Height, t.Border.Width
+		box.Rectangle(0, 0, w, b, t.Border.Color, true)
+		box.Rectangle(0, h-b, w, b, t.Border.Color, true)
+		box.Rectangle(0, b, b, h-2*b, t.Border.Color, true)
+		box.Rectangle(w-b, b, b, h-2*b, t.Border.Color, true)
+	default:
+		t.paintGrid(canvas, layout)
+		w, h, b := layout.size.Width, layout.size.Height, t.Border.Width
+		drawTableRule(canvas, 0, 0, w, b, t.Border)
+		drawTableRule(canvas, 0, h-b, w, b, t.Border)
+		drawTableRule(canvas, 0, b, b, h-2*b, t.Border)
+		drawTableRule(canvas, w-b, b, b, h-2*b, t.Border)
+	}
+}
+
+func (t *Table) paintGrid(canvas *cv.Canvas, layout *tableLayout) {
+	rows := t.allRows()
+	border := t.Border.thickness()
+	verticalRule := t.VerticalRule.thickness()
+	innerWidth := layout.size.Width - 2*border
+
+	y := border
+	for r, row := range rows {
+		rowHeight := layout.rowHeights[r]
+
+		// Row background
+		if background, ok := t.rowColor(r); ok {
+			canvas.Rectangle(border, y, innerWidth, rowHeight, background, true)
+		}
+
+		// Cells, each padded inside its own sub canvas
+		x := border
+		for i, width := range layout.columnWidths {
+			if i < len(row) && row[i] != nil {
+				cellSize := types.Size{Width: width - t.CellPadding.Horizontal(), Height: rowHeight - t.CellPadding.Vertical()}
+				PaintChild(row[i], canvas.SubCanvas(x+t.CellPadding.Left, y+t.CellPadding.Top, cellSize, nil))
+			}
+			x += width
+			// Vertical rule after every column but the last
+			if i < len(layout.columnWidths)-1 {
+				drawTableRule(canvas, x, y, verticalRule, rowHeight, t.VerticalRule)
+				x += verticalRule
+			}
+		}
+		y += rowHeight
+
+		// Horizontal rule after every row but the last
+		if r < len(rows)-1 {
+			rule := t.ruleBelow(r)
+			drawTableRule(canvas, border, y, innerWidth, rule.thickness(), rule)
+			y += rule.thickness()
+		}
+	}
+}
+
+// rowColor returns the background of the row at index r of allRows, if it has one
+func (t *Table) rowColor(r int) (color.RGBA, bool) {
+	if r < len(t.HeaderRows) {
+		return t.HeaderColor, t.HeaderColor.A > 0
+	}
+	if len(t.RowColors) == 0 {
+		return color.RGBA{}, false
+	}
+	background := t.RowColors[(r-len(t.HeaderRows))%len(t.RowColors)]
+	return background, background.A > 0
+}
+
+// drawTableRule fills the given rectangle with the rule's style along its longer side
+func drawTableRule(canvas *cv.Canvas, x, y, w, h int, rule TableRule) {
+	if w <= 0 || h <= 0 || rule.thickness() == 0 {
+		return
+	}
+
+	dash, gap := rule.Style.Dashes(rule.Width)
+	if dash == 0 {
+		canvas.Rectangle(x, y, w, h, rule.Color, true)
+		return
+	}
+
+	if w >= h {
+		for i := x; i < x+w; i += dash + gap {
+			canvas.Rectangle(i, y, min(dash, x+w-i), h, rule.Color, true)
+		}
+	} else {
+		for j := y; j < y+h; j += dash + gap {
+			canvas.Rectangle(x, j, w, min(dash, y+h-j), rule.Color, true)
+		}
+	}
+}
+
+func (t *Table) Size(parentSize types.Size) types.Size {
+	return t.layout(parentSize).size
+}
+
+// intrinsicColumnWidth measures the widest cell of column i, including the cell padding
+func (t *Table) intrinsicColumnWidth(rows [][]RenderObject, i, height int, measure func(RenderObject, int) int) int {
+	widest := 0
+	for _, row := range rows {
+		if i < len(row) && row[i] != nil {
+			widest = max(widest, measure(row[i], height-t.CellPadding.Vertical())+t.CellPadding.Horizontal())
+		}
+	}
+	return widest
+}
+
+// intrinsicWidth adds up the columns, fixed columns keep their width and all others fit their cells
+func (t *Table) intrinsicWidth(height int, measure func(RenderObject, int) int) int {
+	rows := t.allRows()
+	columns := t.columnCount()
+	width := 2 * t.Border.thickness()
+	for i := range columns {
+		if column := t.columnWidth(i); column.Type == TableColumnFixed {
+			width += int(column.Value)
+		} else {
+			width += t.intrinsicColumnWidth(rows, i, height, measure)
+		}
+		if i > 0 {
+			width += t.VerticalRule.thickness()
+		}
+	}
+	return width
+}
+
+func (t *Table) MinIntrinsicWidth(height int) int {
+	return t.intrinsicWidth(height, MinIntrinsicWidth)
+}
+
+func (t *Table) MaxIntrinsicWidth(height int) int {
+	return t.intrinsicWidth(height, MaxIntrinsicWidth)
+}
+
+func (t *Table) MinIntrinsicHeight(width int) int {
+	return t.MaxIntrinsicHeight(width)
+}
+
+func (t *Table) MaxIntrinsicHeight(width int) int {
+	return t.layout(types.Size{Width: width, Height: unboundedExtent}).size.Height
+}
@@ -0,0 +1,160 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestTableColumnWidths(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+
+	table := &Table{
+		Rows: [][]RenderObject{
+			{&ColoredBox{Width: 10, Height: 10, Color: red}, &ColoredBox{Width: 30, Height: 20, Color: red}, nil},
+			{&ColoredBox{Width: 10, Height: 15, Color: red}, &ColoredBox{Width: 40, Height: 10, Color: red}, nil},
+		},
+		ColumnWidths: []TableColumnWidth{
+			FixedColumnWidth(50),
+			IntrinsicColumnWidth(),
+			FlexColumnWidth(1),
+		},
+		CellPadding:  types.EdgeInsetsAll(2),
+		VerticalRule: TableRule{Width: 1, Color: red},
+	}
+
+	size := table.Size(types.Size{Width: 200, Height: 200})
+	if size.Width != 200 {
+		t.Errorf("Expected flex column to fill the table to 200px, got %d", size.Width)
+	}
+	if size.Height != 24+19 {
+		t.Errorf("Expected table height 43, got %d", size.Height)
+	}
+
+	layout := table.layout(types.Size{Width: 200, Height: 200})
+	expected := []int{50, 44, 200 - 50 - 44 - 2}
+	for i, width := range expected {
+		if layout.columnWidths[i] != width {
+			t.Errorf("Expected column %d to be %dpx wide, got %d", i, width, layout.columnWidths[i])
+		}
+	}
+}
+
+func TestTablePaint(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	red := color.RGBA{255, 0, 0, 255}
+	gray := color.RGBA{128, 128, 128, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+
+	cell := func() RenderObject { return &ColoredBox{Width: 10, Height: 10, Color: red} }
+	table := &Table{
+		HeaderRows:     [][]RenderObject{{cell(), cell()}},
+		Rows:           [][]RenderObject{{cell(), cell()}, {cell(), cell()}},
+		ColumnWidths:   []TableColumnWidth{FixedColumnWidth(20), FixedColumnWidth(20)},
+		CellPadding:    types.EdgeInsetsAll(5),
+		HeaderColor:    black,
+		RowColors:      []color.RGBA{white, gray},
+		HorizontalRule: TableRule{Width: 1, Color: red},
+		Border:         TableRule{Width: 2, Color: red},
+	}
+
+	size := table.Size(canvas.Size)
+	if size.Width != 2+20+20+2 || size.Height != 2+20+1+20+1+20+2 {
+		t.Fatalf("Expected table size 44x66, got %v", size)
+	}
+
+	table.Paint(canvas)
+
+	// Header background, then zebra striped body rows
+	if canvas.Img.At(3, 3) != black {
+		t.Errorf("Expected header background at (3,3), got %v", canvas.Img.At(3, 3))
+	}
+	if canvas.Img.At(3, 24) != white {
+		t.Errorf("Expected first body row background at (3,24), got %v", canvas.Img.At(3, 24))
+	}
+	if canvas.Img.At(3, 45) != gray {
+		t.Errorf("Expected second body row background at (3,45), got %v", canvas.Img.At(3, 45))
+	}
+
+	// Cell content inside the padding
+	if canvas.Img.At(2+5, 2+5) != red {
+		t.Error("Expected cell content at (7,7)")
+	}
+
+	// Rule between the header and the first body row
+	if canvas.Img.At(10, 22) != red {
+		t.Error("Expected header rule at (10,22)")
+	}
+
+	// Outer border
+	if canvas.Img.At(0, 0) != red || canvas.Img.At(43, 65) != red {
+		t.Error("Expected border at the table corners")
+	}
+}
+
+func TestTableWrapsTextCells(t *testing.T) {
+	table := NewTextTable(
+		[]string{"Name", "Description"},
+		[][]string{{"Widget", "A fairly long description that will not fit on one line"}},
+		color.Black,
+		12,
+	)
+	table.ColumnWidths = []TableColumnWidth{IntrinsicColumnWidth(), FixedColumnWidth(120)}
+
+	size := table.Size(types.Size{Width: 400, Height: 400})
+	lineHeight := (&cv.Canvas{}).MeasureText("", cv.NewTextPainter()).Height
+	header := lineHeight + table.CellPadding.Vertical()
+	if size.Height <= header*2 {
+		t.Errorf("Expected the description cell to wrap onto several lines, table height %d", size.Height)
+	}
+
+	canvas := cv.NewCanvas(types.Size{Width: 400, Height: 400}, false)
+	table.Paint(canvas)
+}
+
+func TestTableIntrinsicColumnWraps(t *testing.T) {
+	table := NewTextTable(nil, [][]string{{"A fairly long description that will not fit on one line"}}, color.Black, 12)
+	table.ColumnWidths = []TableColumnWidth{IntrinsicColumnWidth()}
+
+	parent := types.Size{Width: 200, Height: 400}
+	size := table.Size(parent)
+	if size.Width != 200 {
+		t.Errorf("Expected the intrinsic column to be capped at the 200px parent, got %d", size.Width)
+	}
+	lineHeight := (&cv.Canvas{}).MeasureText("", cv.NewTextPainter()).Height
+	if size.Height <= lineHeight+table.CellPadding.Vertical() {
+		t.Errorf("Expected the cell to wrap onto several lines, table height %d", size.Height)
+	}
+
+	// Cells never get narrower than their widest word
+	narrowest := table.intrinsicColumnWidth(table.allRows(), 0, parent.Height, MinIntrinsicWidth)
+	if size := table.Size(types.Size{Width: 10, Height: 400}); size.Width != narrowest {
+		t.Errorf("Expected the column to keep its %dpx minimum width, got %d", narrowest, size.Width)
+	}
+}
+
+func TestTableRuleStyles(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	cell := func() RenderObject { return &ColoredBox{Width: 10, Height: 10, Color: color.RGBA{0, 0, 255, 255}} }
+	table := &Table{
+		HeaderRows:     [][]RenderObject{{cell()}},
+		Rows:           [][]RenderObject{{cell()}},
+		ColumnWidths:   []TableColumnWidth{FixedColumnWidth(20)},
+		HorizontalRule: TableRule{Style: cv.BorderStyleDashed, Width: 2, Color: red},
+	}
+
+	// The zero header rule falls back to the dashed horizontal rule: 6px dashes, 4px gaps
+	canvas := cv.NewCanvas(types.Size{Width: 20, Height: 30}, false)
+	table.Paint(canvas)
+	if canvas.Img.At(0, 10) != red || canvas.Img.At(5, 11) != red || canvas.Img.At(7, 10) == red || canvas.Img.At(10, 10) != red {
+		t.Error("Expected a dashed rule below the header")
+	}
+
+	table.HeaderRule = TableRule{Style: cv.BorderStyleNone}
+	if size := table.Size(types.Size{Width: 20, Height: 40}); size.Height != 20 {
+		t.Errorf("Expected no room for a header rule of style none, got height %d", size.Height)
+	}
+}
@@ -2,17 +2,22 @@ package render_objects
 
 import (
 	"image/color"
+	"strings"
 
 	"github.com/hvuhsg/render/canvas"
 	"github.com/hvuhsg/render/types"
 )
 
 type Text struct {
-	text     string
-	color    color.Color
-	fontSize float64
-	fontName string
-	size     types.Size
+	text           string
+	color          color.Color
+	fontSize       float64
+	fontName       string
+	wrap           bool
+	align          TextAlign
+	lines          []string
+	size           types.Size
+	lastParentSize types.Size
 }
 
 func NewText(text string, color color.Color, fontSize float64, fontName string) *Text {
@@ -25,25 +30,190 @@ func NewText(text string, color color.Color, fontSize float64, fontName string)
 	}
 }
 
-func (t *Text) Paint(c *canvas.Canvas) {
-	// Create a text painter with the text properties
+// NewWrappedText creates a Text that breaks onto multiple lines to fit the width of its parent
+func NewWrappedText(text string, color color.Color, fontSize float64, fontName string) *Text {
+	t := NewText(text, color, fontSize, fontName)
+	t.wrap = true
+	return t
+}
+
+// TextAlign places each line of wrapped text across the width of the canvas it is painted on
+type TextAlign string
+
+const (
+	TextAlignLeft   TextAlign = "left"
+	TextAlignCenter TextAlign = "center"
+	TextAlignRight  TextAlign = "right"
+)
+
+// offset returns how far right a line of width is moved in a canvas of width available
+func (a TextAlign) offset(available, width int) int {
+	switch a {
+	case TextAlignCenter:
+		return (available - width) / 2
+	case TextAlignRight:
+		return available - width
+	default:
+		return 0
+	}
+}
+
+// NewAlignedText creates wrapped text whose lines are aligned, centered lines stay
+// centered however the text wraps
+func NewAlignedText(text string, color color.Color, fontSize float64, fontName string, align TextAlign) *Text {
+	t := NewWrappedText(text, color, fontSize, fontName)
+	t.align = align
+	return t
+}
+
+func (t *Text) painter() *canvas.TextPainter {
 	painter := canvas.NewTextPainter()
 	painter.TextColor = t.color
 	painter.FontSize = t.fontSize
-	// Note: font name is ignored for now as we only support the default font
+	// Unknown font names keep the default font
+	if font, data, ok := canvas.LookupFont(t.fontName); ok {
+		painter.Font, painter.FontData = font, data
+	}
+	return painter
+}
 
-	// Draw the text
-	c.DrawText(t.text, 0, 0, painter)
+func (t *Text) Paint(c *canvas.Canvas) {
+	// Create a text painter with the text properties
+	painter := t.painter()
+
+	if !t.wrap {
+		// Draw the text
+		c.DrawText(t.text, 0, 0, painter)
+		return
+	}
+
+	lines := t.wrappedLines(c.Size.Width, painter)
+	lineHeight := c.MeasureText("", painter).Height
+	for i, line := range lines {
+		x := 0
+		if t.align != "" && t.align != TextAlignLeft {
+			x = t.align.offset(c.Size.Width, c.MeasureText(line, painter).Width)
+		}
+		c.DrawText(line, x, i*lineHeight, painter)
+	}
 }
 
 func (t *Text) Size(parentSize types.Size) types.Size {
+	if t.wrap {
+		return t.wrappedSize(parentSize)
+	}
+
 	if t.size.Width == 0 || t.size.Height == 0 {
 		// Create a temporary canvas for measurement
 		tempCanvas := canvas.NewCanvas(types.Size{Width: 1000, Height: 1000}, false)
-		painter := canvas.NewTextPainter()
-		painter.TextColor = t.color
-		painter.FontSize = t.fontSize
-		t.size = tempCanvas.MeasureText(t.text, painter)
+		t.size = tempCanvas.MeasureText(t.text, t.painter())
 	}
 	return t.size
 }
+
+func (t *Text) wrappedSize(parentSize types.Size) types.Size {
+	// Wrapped text depends on the available width, so only reuse the last layout for the same parent
+	if t.lines != nil && t.lastParentSize == parentSize {
+		return t.size
+	}
+
+	measureCanvas := &canvas.Canvas{}
+	painter := t.painter()
+	t.lines = measureCanvas.WrapText(t.text, parentSize.Width, painter)
+
+	width := 0
+	for _, line := range t.lines {
+		if w := measureCanvas.MeasureText(line, painter).Width; w > width {
+			width = w
+		}
+	}
+	lineHeight := measureCanvas.MeasureText("", painter).Height
+
+	t.size = types.Size{Width: width, Height: lineHeight * len(t.lines)}
+	t.lastParentSize = parentSize
+	return t.size
+}
+
+// wrappedLines returns the text wrapped to width, reusing the lines of the last layout when
+// they wrap the same: at any width from the widest line up to the width laid out at
+func (t *Text) wrappedLines(width int, painter *canvas.TextPainter) []string {
+	if t.lines != nil && width >= t.size.Width && width <= t.lastParentSize.Width {
+		return t.lines
+	}
+	return (&canvas.Canvas{}).WrapText(t.text, width, painter)
+}
+
+func (t *Text) Describe() map[string]any {
+	props := map[string]any{
+		"Text":     t.text,
+		"Color":    t.color,
+		"FontSize": t.fontSize,
+		"Font":     t.fontName,
+		"Wrap":     t.wrap,
+	}
+	// Left is the default alignment, however it was asked for
+	if t.align != TextAlignLeft {
+		props["Align"] = t.align
+	}
+	return props
+}
+
+// Guides mark the baseline of every line, where DrawText puts it
+func (t *Text) Guides(size types.Size) Guides {
+	lines := 1
+	if t.wrap {
+		lines = len(t.wrappedLines(size.Width, t.painter()))
+	}
+
+	lineHeight := (&canvas.Canvas{}).MeasureText("", t.painter()).Height
+	baselines := make([]int, lines)
+	for i := range baselines {
+		baselines[i] = i*lineHeight + int(t.fontSize)
+	}
+	return Guides{Baselines: baselines}
+}
+
+// MinIntrinsicWidth is the widest word for wrapped text, and the whole text otherwise
+func (t *Text) MinIntrinsicWidth(height int) int {
+	if !t.wrap {
+		return t.MaxIntrinsicWidth(height)
+	}
+
+	measureCanvas := &canvas.Canvas{}
+	painter := t.painter()
+	widest := 0
+	for _, word := range strings.Fields(t.text) {
+		widest = max(widest, measureCanvas.MeasureText(word, painter).Width)
+	}
+	return widest
+}
+
+// MaxIntrinsicWidth is the width of the text without any wrapping
+func (t *Text) MaxIntrinsicWidth(height int) int {
+	measureCanvas := &canvas.Canvas{}
+	painter := t.painter()
+	if !t.wrap {
+		return measureCanvas.MeasureText(t.text, painter).Width
+	}
+
+	widest := 0
+	for _, line := range measureCanvas.WrapText(t.text, 0, painter) {
+		widest = max(widest, measureCanvas.MeasureText(line, painter).Width)
+	}
+	return widest
+}
+
+func (t *Text) MinIntrinsicHeight(width int) int {
+	return t.MaxIntrinsicHeight(width)
+}
+
+// MaxIntrinsicHeight is the height of the text when wrapped to width
+func (t *Text) MaxIntrinsicHeight(width int) int {
+	measureCanvas := &canvas.Canvas{}
+	painter := t.painter()
+	lineHeight := measureCanvas.MeasureText("", painter).Height
+	if !t.wrap {
+		return lineHeight
+	}
+	return lineHeight * len(measureCanvas.WrapText(t.text, width, painter))
+}
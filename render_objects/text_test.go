@@ -41,3 +41,131 @@ func TestText(t *testing.T) {
 		t.Error("Expected some red pixels to be drawn for the text")
 	}
 }
+
+func TestWrappedText(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	content := "The quick brown fox jumps over the lazy dog"
+
+	single := NewText(content, red, 16, "default").Size(types.Size{Width: 100, Height: 200})
+	wrapped := NewWrappedText(content, red, 16, "default")
+
+	// Test Size method
+	size := wrapped.Size(types.Size{Width: 100, Height: 200})
+	if size.Width > 100 {
+		t.Errorf("Expected wrapped text to fit in 100px, got width %d", size.Width)
+	}
+	if size.Height <= single.Height {
+		t.Errorf("Expected wrapped text to be taller than a single line (%d), got %d", single.Height, size.Height)
+	}
+
+	// A wider parent needs fewer lines
+	wider := wrapped.Size(types.Size{Width: 1000, Height: 200})
+	if wider.Height != single.Height {
+		t.Errorf("Expected a single line in a wide parent, got height %d", wider.Height)
+	}
+
+	// Test Paint method
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 200}, false)
+	wrapped.Size(canvas.Size)
+	wrapped.Paint(canvas)
+
+	lastLine := false
+	for x := 0; x < 100; x++ {
+		for y := single.Height; y < size.Height; y++ {
+			if canvas.Img.At(x, y) == red {
+				lastLine = true
+			}
+		}
+	}
+	if !lastLine {
+		t.Error("Expected red pixels below the first line of wrapped text")
+	}
+}
+
+func TestWrappedTextReusesLayout(t *testing.T) {
+	const text = "The quick brown fox jumps over the lazy dog"
+	wrapped := NewWrappedText(text, color.Black, 16, "default")
+	size := wrapped.Size(types.Size{Width: 100, Height: 200})
+
+	// Painting at the layout width or at the width of the widest line draws the same lines
+	atLayout := cv.NewCanvas(types.Size{Width: 100, Height: size.Height}, false)
+	wrapped.Paint(atLayout)
+	atLine := cv.NewCanvas(size, false)
+	wrapped.Paint(atLine)
+	for y := range size.Height {
+		for x := range size.Width {
+			if atLayout.Img.At(x, y) != atLine.Img.At(x, y) {
+				t.Fatalf("Expected the same text at the layout width and at the widest line, differs at (%d,%d)", x, y)
+			}
+		}
+	}
+
+	// Text that was laid out doesn't wrap again, so it allocates less than text that wasn't
+	fresh := NewWrappedText(text, color.Black, 16, "default")
+	for _, width := range []int{100, size.Width} {
+		guides := types.Size{Width: width, Height: size.Height}
+		if len(wrapped.Guides(guides).Baselines) != len(fresh.Guides(guides).Baselines) {
+			t.Errorf("Expected the same lines as freshly wrapped text at width %d", width)
+		}
+		reused := testing.AllocsPerRun(10, func() { wrapped.Guides(guides) })
+		wrappedAgain := testing.AllocsPerRun(10, func() { fresh.Guides(guides) })
+		if reused >= wrappedAgain {
+			t.Errorf("Expected the lines of the layout to be reused at width %d, got %v allocations against %v", width, reused, wrappedAgain)
+		}
+	}
+	if lines := len(wrapped.Guides(types.Size{Width: 1000, Height: size.Height}).Baselines); lines != 1 {
+		t.Errorf("Expected the text to be wrapped again in a wider canvas, got %d lines", lines)
+	}
+}
+
+func TestAlignedText(t *testing.T) {
+	const text = "The quick brown fox jumps over the lazy dog and runs far away"
+	measure, painter := &cv.Canvas{}, cv.NewTextPainter()
+	painter.FontSize = 16
+	lineHeight := measure.MeasureText("", painter).Height
+	lines := measure.WrapText(text, 150, painter)
+	if len(lines) < 3 {
+		t.Fatalf("Expected the text to wrap onto several lines, got %q", lines)
+	}
+
+	// leftInk returns the leftmost painted column between the rows top and bottom
+	leftInk := func(canvas *cv.Canvas, top, bottom int) int {
+		left := canvas.Size.Width
+		for y := top; y < bottom; y++ {
+			for x := range canvas.Size.Width {
+				if canvas.Img.RGBAAt(x, y).A > 0 {
+					left = min(left, x)
+				}
+			}
+		}
+		return left
+	}
+
+	for _, align := range []TextAlign{TextAlignLeft, TextAlignCenter, TextAlignRight} {
+		aligned := NewAlignedText(text, color.Black, 16, "default", align)
+		canvas := cv.NewCanvas(types.Size{Width: 150, Height: 200}, false)
+		aligned.Size(canvas.Size)
+		aligned.Paint(canvas)
+
+		for i, line := range lines {
+			// Glyphs may reach left of where a line starts, so find where they start
+			// on a canvas with room for them. The middle of a line is compared so
+			// descenders of the line above don't count.
+			alone := cv.NewCanvas(types.Size{Width: 200, Height: lineHeight}, false)
+			alone.DrawText(line, 20, 0, painter)
+			start := leftInk(alone, lineHeight/3, 2*lineHeight/3) - 20
+
+			// Each line moves right by the room it leaves in the canvas, or half of it.
+			// Glyphs cut by the left edge are drawn shifted, so those lines aren't compared.
+			room := 150 - measure.MeasureText(line, painter).Width
+			shift := map[TextAlign]int{TextAlignLeft: 0, TextAlignCenter: room / 2, TextAlignRight: room}[align]
+			if leftInk(alone, 0, lineHeight)-20+shift < 0 {
+				continue
+			}
+			top := i * lineHeight
+			if got, expected := leftInk(canvas, top+lineHeight/3, top+2*lineHeight/3), start+shift; got != expected {
+				t.Errorf("Expected %s line %d to start at %d, got %d", align, i, expected, got)
+			}
+		}
+	}
+}
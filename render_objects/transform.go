@@ -0,0 +1,130 @@
+package render_objects
+
+import (
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Transform paints its child through an affine transformation applied about an origin.
+// The transformation is visual only: the child is laid out at its normal size and
+// anything transformed outside the canvas is clipped.
+type Transform struct {
+	Child  RenderObject
+	Matrix cv.Matrix // The zero matrix leaves the child unchanged, like the identity
+	Origin AlignType // Point of the child the transformation is applied about, centered by default
+}
+
+// NewRotation creates a Transform rotating its child clockwise by degrees about its center
+func NewRotation(child RenderObject, degrees float64) *Transform {
+	return &Transform{Child: child, Matrix: cv.Identity().Rotate(degrees)}
+}
+
+// NewScale creates a Transform scaling its child about its center
+func NewScale(child RenderObject, scaleX, scaleY float64) *Transform {
+	return &Transform{Child: child, Matrix: cv.Identity().Scale(scaleX, scaleY)}
+}
+
+// NewTranslation creates a Transform moving its child by (dx, dy)
+func NewTranslation(child RenderObject, dx, dy float64) *Transform {
+	return &Transform{Child: child, Matrix: cv.Identity().Translate(dx, dy)}
+}
+
+// NewSkew creates a Transform skewing its child about its center, in degrees
+func NewSkew(child RenderObject, degreesX, degreesY float64) *Transform {
+	return &Transform{Child: child, Matrix: cv.Identity().Skew(degreesX, degreesY)}
+}
+
+func (t *Transform) Paint(canvas *cv.Canvas) {
+	if t.Matrix.IsIdentity() || t.Matrix == (cv.Matrix{}) {
+		PaintChild(t.Child, canvas)
+		return
+	}
+
+	origin := t.Origin
+	if origin == "" {
+		origin = AlignCenter
+	}
+	ox, oy := origin.offset(canvas.Size, types.Size{})
+
+	// Move the origin to (0, 0), transform, and move it back
+	matrix := t.Matrix.Multiply(cv.Identity().Translate(-float64(ox), -float64(oy))).
+		Translate(float64(ox), float64(oy))
+
+	canvas.PaintTransformed(canvas.Size, matrix, func(c *cv.Canvas) { PaintChild(t.Child, c) })
+}
+
+func (t *Transform) Size(parentSize types.Size) types.Size {
+	return t.Child.Size(parentSize)
+}
+
+// RotatedBox rotates its child by a number of quarter turns clockwise.
+// Unlike Transform the rotation affects layout, so a quarter turn swaps the width and height.
+type RotatedBox struct {
+	Child        RenderObject
+	QuarterTurns int
+}
+
+func (r *RotatedBox) turns() int {
+	return ((r.QuarterTurns % 4) + 4) % 4
+}
+
+func (r *RotatedBox) Paint(canvas *cv.Canvas) {
+	turns := r.turns()
+	if turns == 0 {
+		PaintChild(r.Child, canvas)
+		return
+	}
+
+	// Lay the child out unrotated, then rotate it into the canvas
+	childSize := canvas.Size
+	if turns%2 == 1 {
+		childSize = types.Size{Width: canvas.Size.Height, Height: canvas.Size.Width}
+	}
+	w, h := float64(childSize.Width), float64(childSize.Height)
+	matrix := cv.Identity().Rotate(float64(90 * turns))
+	switch turns {
+	case 1:
+		matrix = matrix.Translate(h, 0)
+	case 2:
+		matrix = matrix.Translate(w, h)
+	case 3:
+		matrix = matrix.Translate(0, w)
+	}
+	canvas.PaintTransformed(childSize, matrix, func(c *cv.Canvas) { PaintChild(r.Child, c) })
+}
+
+func (r *RotatedBox) Size(parentSize types.Size) types.Size {
+	if r.turns()%2 == 0 {
+		return r.Child.Size(parentSize)
+	}
+	size := r.Child.Size(types.Size{Width: parentSize.Height, Height: parentSize.Width})
+	return types.Size{Width: size.Height, Height: size.Width}
+}
+
+func (r *RotatedBox) MinIntrinsicWidth(height int) int {
+	if r.turns()%2 == 0 {
+		return MinIntrinsicWidth(r.Child, height)
+	}
+	return MinIntrinsicHeight(r.Child, height)
+}
+
+func (r *RotatedBox) MaxIntrinsicWidth(height int) int {
+	if r.turns()%2 == 0 {
+		return MaxIntrinsicWidth(r.Child, height)
+	}
+	return MaxIntrinsicHeight(r.Child, height)
+}
+
+func (r *RotatedBox) MinIntrinsicHeight(width int) int {
+	if r.turns()%2 == 0 {
+		return MinIntrinsicHeight(r.Child, width)
+	}
+	return MinIntrinsicWidth(r.Child, width)
+}
+
+func (r *RotatedBox) MaxIntrinsicHeight(width int) int {
+	if r.turns()%2 == 0 {
+		return MaxIntrinsicHeight(r.Child, width)
+	}
+	return MaxIntrinsicWidth(r.Child, width)
+}
@@ -0,0 +1,100 @@
+package render_objects
+
+import (
+	"image/color"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestTransformRotation(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 40, Height: 40}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	// A horizontal bar rotated a quarter turn about the center becomes vertical
+	bar := &Painter{
+		Painter: func(c *cv.Canvas) { c.Rectangle(0, 18, 40, 4, red, true) },
+		Width:   40,
+		Height:  40,
+	}
+	rotation := NewRotation(bar, 90)
+
+	// The transformation doesn't affect layout
+	if size := rotation.Size(canvas.Size); size.Width != 40 || size.Height != 40 {
+		t.Errorf("Expected size 40x40, got %v", size)
+	}
+
+	rotation.Paint(canvas)
+
+	if canvas.Img.At(20, 2) != red || canvas.Img.At(20, 37) != red {
+		t.Error("Expected a vertical bar through the center")
+	}
+	if canvas.Img.RGBAAt(2, 20).A != 0 {
+		t.Error("Expected the horizontal bar to be gone")
+	}
+}
+
+func TestTransformScaleOrigin(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 40, Height: 40}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	box := &ColoredBox{Width: 40, Height: 40, Color: red}
+	scale := NewScale(box, 0.5, 0.5)
+	scale.Origin = AlignTopLeft
+	scale.Paint(canvas)
+
+	if canvas.Img.At(5, 5) != red || canvas.Img.At(19, 19) != red {
+		t.Error("Expected the scaled box in the top left quarter")
+	}
+	if canvas.Img.RGBAAt(25, 25).A != 0 {
+		t.Error("Expected nothing outside the scaled box")
+	}
+}
+
+func TestTransformZeroMatrix(t *testing.T) {
+	canvas := cv.NewCanvas(types.Size{Width: 20, Height: 20}, false)
+	red := color.RGBA{255, 0, 0, 255}
+
+	// A Transform without a matrix paints its child untransformed instead of collapsing it
+	transform := &Transform{Child: &ColoredBox{Width: 10, Height: 10, Color: red}}
+	transform.Paint(canvas)
+	if canvas.Img.At(0, 0) != red || canvas.Img.At(9, 9) != red {
+		t.Error("Expected the zero matrix to paint the child as is")
+	}
+}
+
+func TestRotatedBox(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// 30x10, red on the left and blue on the right
+	bar := &Row{Children: []RenderObject{
+		&ColoredBox{Width: 15, Height: 10, Color: red},
+		&ColoredBox{Width: 15, Height: 10, Color: blue},
+	}}
+	rotated := &RotatedBox{Child: bar, QuarterTurns: 1}
+
+	size := rotated.Size(types.Size{Width: 100, Height: 100})
+	if size.Width != 10 || size.Height != 30 {
+		t.Fatalf("Expected the rotated box to swap to 10x30, got %v", size)
+	}
+
+	canvas := cv.NewCanvas(types.Size{Width: 100, Height: 100}, false)
+	rotated.Paint(canvas.SubCanvas(0, 0, size, nil))
+
+	// A clockwise quarter turn puts the left end at the top
+	if canvas.Img.At(0, 0) != red || canvas.Img.At(9, 14) != red {
+		t.Error("Expected the red half at the top")
+	}
+	if canvas.Img.At(0, 15) != blue || canvas.Img.At(9, 29) != blue {
+		t.Error("Expected the blue half at the bottom")
+	}
+	if canvas.Img.RGBAAt(10, 0).A != 0 {
+		t.Error("Expected nothing outside the rotated box")
+	}
+
+	if w := MaxIntrinsicWidth(rotated, 100); w != 10 {
+		t.Errorf("Expected intrinsic width 10, got %d", w)
+	}
+}
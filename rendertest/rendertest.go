@@ -0,0 +1,271 @@
+// Package rendertest compares rendered trees against golden PNG images.
+//
+//	func TestCard(t *testing.T) {
+//		rendertest.AssertGolden(t, card, types.Size{Width: 400, Height: 180}, "card")
+//	}
+//
+// Goldens live in testdata/<name>.png. Run the tests with RENDERTEST_UPDATE=1 to write
+// or refresh them:
+//
+//	RENDERTEST_UPDATE=1 go test ./...
+//
+// The -update flag does the same, but only packages that import rendertest accept it:
+//
+//	go test ./render_objects/ -update
+//
+// When an image doesn't match, the rendered image and a diff highlighting the changed
+// pixels are written to testdata/failures, so they can be inspected and then discarded.
+package rendertest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/types"
+)
+
+var update = flag.Bool("update", false, "write the rendered images as the new golden images")
+
+// updating reports whether goldens should be written rather than compared
+func updating() bool {
+	return *update || os.Getenv("RENDERTEST_UPDATE") == "1"
+}
+
+// RenderObject is anything that can be laid out and painted, such as the render objects
+// of the render_objects package
+type RenderObject interface {
+	Paint(canvas *cv.Canvas)
+	Size(parentSize types.Size) types.Size
+}
+
+// Options set how much an image may differ from its golden and still match.
+// The zero value requires an exact match.
+type Options struct {
+	// Largest difference of any channel, from 0 to 255, for a pixel to count as unchanged
+	ChannelTolerance uint8
+	// When set, pixels are compared by perceptual distance instead: the OKLab distance
+	// scaled to 0-100, where about 2 is the smallest difference people notice
+	Perceptual float64
+	// Number of changed pixels allowed
+	MaxDiffPixels int
+	// Fraction of changed pixels allowed, from 0 to 1
+	MaxDiffRatio float64
+	// Device pixels per logical pixel the tree is rendered at, 1 by default
+	PixelRatio float64
+	// Directory of the golden images, testdata by default
+	Dir string
+}
+
+// Diff is the result of comparing two images
+type Diff struct {
+	Pixels      int         // Number of changed pixels
+	Total       int         // Number of pixels compared
+	MaxDelta    uint8       // Largest channel difference
+	MaxDistance float64     // Largest perceptual distance
+	Image       *image.RGBA // The golden faded to gray with changed pixels in red, nil when sizes differ
+	SizeChanged bool
+}
+
+// Render paints a tree onto a transparent canvas and returns its pixels
+func Render(obj RenderObject, size types.Size, pixelRatio float64) *image.RGBA {
+	if pixelRatio <= 0 {
+		pixelRatio = 1
+	}
+	canvas := cv.NewScaledCanvas(size, pixelRatio, false)
+	obj.Paint(canvas)
+	return toRGBA(canvas.Image())
+}
+
+// AssertGolden renders obj and fails the test unless it matches the golden image
+// testdata/<name>.png exactly
+func AssertGolden(t testing.TB, obj RenderObject, size types.Size, name string) {
+	t.Helper()
+	Options{}.AssertGolden(t, obj, size, name)
+}
+
+// AssertGolden renders obj and fails the test unless it matches the golden image
+// <Dir>/<name>.png within the tolerances of the options
+func (o Options) AssertGolden(t testing.TB, obj RenderObject, size types.Size, name string) {
+	t.Helper()
+	dir := o.Dir
+	if dir == "" {
+		dir = "testdata"
+	}
+	path := filepath.Join(dir, name+".png")
+	got := Render(obj, size, o.PixelRatio)
+
+	if updating() {
+		if err := writePNG(path, got); err != nil {
+			t.Fatalf("Writing golden %s: %v", path, err)
+		}
+		t.Logf("Updated golden %s", path)
+		return
+	}
+
+	want, err := readPNG(path)
+	if os.IsNotExist(err) {
+		t.Errorf("Golden %s doesn't exist, run the test with RENDERTEST_UPDATE=1 to create it", path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("Reading golden %s: %v", path, err)
+	}
+
+	diff := o.Compare(got, want)
+	if o.Passes(diff) {
+		return
+	}
+
+	failures := filepath.Join(dir, "failures")
+	actualPath := filepath.Join(failures, name+".actual.png")
+	if err := writePNG(actualPath, got); err != nil {
+		t.Logf("Writing %s: %v", actualPath, err)
+	}
+	if diff.SizeChanged {
+		t.Errorf("Expected %s to be %v, got %v; rendered image written to %s",
+			path, want.Bounds().Size(), got.Bounds().Size(), actualPath)
+		return
+	}
+	diffPath := filepath.Join(failures, name+".diff.png")
+	if err := writePNG(diffPath, diff.Image); err != nil {
+		t.Logf("Writing %s: %v", diffPath, err)
+	}
+	t.Errorf("Expected the render to match %s, got %s; see %s and %s",
+		path, diff, actualPath, diffPath)
+}
+
+// Compare finds the pixels of got that differ from want beyond the tolerance of the options
+func (o Options) Compare(got, want image.Image) *Diff {
+	g, w := toRGBA(got), toRGBA(want)
+	if g.Bounds().Size() != w.Bounds().Size() {
+		return &Diff{SizeChanged: true}
+	}
+
+	size := g.Bounds().Size()
+	diff := &Diff{Total: size.X * size.Y, Image: image.NewRGBA(image.Rect(0, 0, size.X, size.Y))}
+	for y := range size.Y {
+		for x := range size.X {
+			a := g.RGBAAt(g.Rect.Min.X+x, g.Rect.Min.Y+y)
+			b := w.RGBAAt(w.Rect.Min.X+x, w.Rect.Min.Y+y)
+
+			delta := max(absDiff(a.R, b.R), absDiff(a.G, b.G), absDiff(a.B, b.B), absDiff(a.A, b.A))
+			diff.MaxDelta = max(diff.MaxDelta, delta)
+			changed := delta > o.ChannelTolerance
+			if o.Perceptual > 0 {
+				distance := perceptualDistance(a, b)
+				diff.MaxDistance = max(diff.MaxDistance, distance)
+				changed = distance > o.Perceptual
+			}
+
+			if changed {
+				diff.Pixels++
+				// Stronger red for larger differences, so small drifts stay visible
+				diff.Image.SetRGBA(x, y, color.RGBA{R: 255, G: 0, B: 0, A: uint8(128 + int(delta)/2)})
+				continue
+			}
+			// Unchanged pixels show the golden as faint gray for context, composited over white
+			gray := (int(b.R) + int(b.G) + int(b.B)) / 3
+			faded := uint8(255 - (int(b.A)-gray)/4)
+			diff.Image.SetRGBA(x, y, color.RGBA{faded, faded, faded, 255})
+		}
+	}
+	return diff
+}
+
+// Passes reports whether a diff is within the allowed number of changed pixels
+func (o Options) Passes(diff *Diff) bool {
+	if diff.SizeChanged {
+		return false
+	}
+	allowed := max(o.MaxDiffPixels, int(o.MaxDiffRatio*float64(diff.Total)))
+	return diff.Pixels <= allowed
+}
+
+func (d *Diff) String() string {
+	if d.SizeChanged {
+		return "a different size"
+	}
+	s := fmt.Sprintf("%d of %d pixels changed (%.2f%%), largest channel difference %d",
+		d.Pixels, d.Total, 100*float64(d.Pixels)/float64(max(d.Total, 1)), d.MaxDelta)
+	if d.MaxDistance > 0 {
+		s += fmt.Sprintf(", largest perceptual distance %.2f", d.MaxDistance)
+	}
+	return s
+}
+
+// perceptualDistance is the OKLab distance of two colors scaled to 0-100.
+// Translucent colors are compared over both black and white, so alpha changes count.
+func perceptualDistance(a, b color.RGBA) float64 {
+	distance := 0.0
+	for _, background := range []color.RGBA{{0, 0, 0, 255}, {255, 255, 255, 255}} {
+		la, lb := colors.ToOKLab(over(a, background)), colors.ToOKLab(over(b, background))
+		d := math.Sqrt((la.L-lb.L)*(la.L-lb.L) + (la.A-lb.A)*(la.A-lb.A) + (la.B-lb.B)*(la.B-lb.B))
+		distance = max(distance, 100*d)
+	}
+	return distance
+}
+
+// over composites a premultiplied color onto an opaque background
+func over(c, background color.RGBA) color.RGBA {
+	inverse := 255 - int(c.A)
+	return color.RGBA{
+		R: uint8(int(c.R) + int(background.R)*inverse/255),
+		G: uint8(int(c.G) + int(background.G)*inverse/255),
+		B: uint8(int(c.B) + int(background.B)*inverse/255),
+		A: 255,
+	}
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := range bounds.Dy() {
+		for x := range bounds.Dx() {
+			rgba.Set(x, y, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return rgba
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
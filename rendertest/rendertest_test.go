@@ -0,0 +1,159 @@
+package rendertest
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// box fills its canvas with a color
+type box struct {
+	color color.RGBA
+}
+
+func (b box) Paint(canvas *cv.Canvas) {
+	canvas.Rectangle(0, 0, canvas.Size.Width, canvas.Size.Height, b.color, true)
+}
+
+func (b box) Size(parentSize types.Size) types.Size {
+	return parentSize
+}
+
+// recorder is a testing.TB that records failures instead of failing
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Logf(format string, args ...any) {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func solid(c color.RGBA, w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	want := solid(color.RGBA{100, 100, 100, 255}, 10, 10)
+	got := solid(color.RGBA{100, 100, 100, 255}, 10, 10)
+	got.SetRGBA(0, 0, color.RGBA{103, 100, 100, 255})
+	got.SetRGBA(1, 0, color.RGBA{255, 0, 0, 255})
+
+	diff := Options{}.Compare(got, want)
+	if diff.Pixels != 2 || diff.MaxDelta != 155 || diff.Total != 100 {
+		t.Errorf("Expected 2 changed pixels with a largest delta of 155, got %s", diff)
+	}
+	if (Options{}).Passes(diff) {
+		t.Error("Expected an exact comparison to fail")
+	}
+	if got := diff.Image.RGBAAt(1, 0); got.R != 255 || got.G != 0 {
+		t.Errorf("Expected the changed pixel to be red in the diff, got %v", got)
+	}
+
+	tolerant := Options{ChannelTolerance: 5}
+	if diff := tolerant.Compare(got, want); diff.Pixels != 1 {
+		t.Errorf("Expected the small change to be tolerated, got %s", diff)
+	}
+	if !(Options{ChannelTolerance: 5, MaxDiffPixels: 1}).Passes(tolerant.Compare(got, want)) {
+		t.Error("Expected one changed pixel to be allowed")
+	}
+	if !(Options{MaxDiffRatio: 0.02}).Passes(diff) {
+		t.Error("Expected 2% of changed pixels to be allowed")
+	}
+
+	perceptual := Options{Perceptual: 2}
+	if diff := perceptual.Compare(got, want); diff.Pixels != 1 || diff.MaxDistance < 2 {
+		t.Errorf("Expected only the red pixel to be noticeable, got %s", diff)
+	}
+
+	if diff := (Options{}).Compare(solid(color.RGBA{}, 4, 4), want); !diff.SizeChanged || (Options{MaxDiffRatio: 1}).Passes(diff) {
+		t.Error("Expected images of different sizes to never match")
+	}
+}
+
+func TestPerceptualAlpha(t *testing.T) {
+	// Transparent and opaque black look the same over black but not over white
+	if d := perceptualDistance(color.RGBA{0, 0, 0, 0}, color.RGBA{0, 0, 0, 255}); d < 50 {
+		t.Errorf("Expected an alpha change to be noticeable, got %.2f", d)
+	}
+	if d := perceptualDistance(color.RGBA{10, 20, 30, 255}, color.RGBA{10, 20, 30, 255}); d != 0 {
+		t.Errorf("Expected equal colors to have no distance, got %.2f", d)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir := t.TempDir()
+	options := Options{Dir: dir}
+	size := types.Size{Width: 8, Height: 4}
+	red := box{color.RGBA{255, 0, 0, 255}}
+
+	// Missing goldens fail with a hint
+	r := &recorder{}
+	options.AssertGolden(r, red, size, "box")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "RENDERTEST_UPDATE=1") {
+		t.Errorf("Expected a missing golden error, got %v", r.errors)
+	}
+
+	t.Setenv("RENDERTEST_UPDATE", "1")
+	options.AssertGolden(t, red, size, "box")
+	os.Unsetenv("RENDERTEST_UPDATE")
+	if _, err := os.Stat(filepath.Join(dir, "box.png")); err != nil {
+		t.Fatalf("Expected RENDERTEST_UPDATE to write the golden, got %v", err)
+	}
+
+	*update = true
+	options.AssertGolden(t, red, size, "flag")
+	*update = false
+	if _, err := os.Stat(filepath.Join(dir, "flag.png")); err != nil {
+		t.Fatalf("Expected -update to write the golden, got %v", err)
+	}
+
+	r = &recorder{}
+	options.AssertGolden(r, red, size, "box")
+	if len(r.errors) != 0 {
+		t.Errorf("Expected the render to match its golden, got %v", r.errors)
+	}
+
+	// A change fails and leaves the rendered image and diff behind
+	options.AssertGolden(r, box{color.RGBA{0, 0, 255, 255}}, size, "box")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "32 of 32 pixels changed") {
+		t.Errorf("Expected every pixel to change, got %v", r.errors)
+	}
+	for _, name := range []string{"box.actual.png", "box.diff.png"} {
+		if _, err := os.Stat(filepath.Join(dir, "failures", name)); err != nil {
+			t.Errorf("Expected %s to be written", name)
+		}
+	}
+
+	r = &recorder{}
+	options.AssertGolden(r, red, types.Size{Width: 4, Height: 4}, "box")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "Expected") || !strings.Contains(r.errors[0], "(8,4)") {
+		t.Errorf("Expected a size mismatch, got %v", r.errors)
+	}
+
+	// A pixel ratio renders more device pixels than the golden has
+	r = &recorder{}
+	Options{Dir: dir, PixelRatio: 2}.AssertGolden(r, red, size, "box")
+	if len(r.errors) != 1 {
+		t.Errorf("Expected a 2x render not to match the 1x golden, got %v", r.errors)
+	}
+}
@@ -0,0 +1,353 @@
+package scene
+
+import (
+	"image/color"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+var (
+	alignments = map[string]render_objects.AlignType{
+		"topLeft":      render_objects.AlignTopLeft,
+		"topCenter":    render_objects.AlignTopCenter,
+		"topRight":     render_objects.AlignTopRight,
+		"leftCenter":   render_objects.AlignLeftCenter,
+		"center":       render_objects.AlignCenter,
+		"rightCenter":  render_objects.AlignRightCenter,
+		"bottomLeft":   render_objects.AlignBottomLeft,
+		"bottomCenter": render_objects.AlignBottomCenter,
+		"bottomRight":  render_objects.AlignBottomRight,
+	}
+	mainAxisAlignments = map[string]types.MainAxisAlignment{
+		"start":        types.MainAxisAlignmentStart,
+		"center":       types.MainAxisAlignmentCenter,
+		"end":          types.MainAxisAlignmentEnd,
+		"spaceBetween": types.MainAxisAlignmentSpaceBetween,
+		"spaceAround":  types.MainAxisAlignmentSpaceAround,
+		"spaceEvenly":  types.MainAxisAlignmentSpaceEvenly,
+	}
+	mainAxisSizes = map[string]types.MainAxisSize{
+		"min": types.MainAxisSizeMin,
+		"max": types.MainAxisSizeMax,
+	}
+	stackFits = map[string]render_objects.StackFit{
+		"loose":       render_objects.StackFitLoose,
+		"expand":      render_objects.StackFitExpand,
+		"passthrough": render_objects.StackFitPassthrough,
+	}
+	boxFits = map[string]render_objects.BoxFit{
+		"contain":   render_objects.BoxFitContain,
+		"cover":     render_objects.BoxFitCover,
+		"fill":      render_objects.BoxFitFill,
+		"fitWidth":  render_objects.BoxFitFitWidth,
+		"fitHeight": render_objects.BoxFitFitHeight,
+		"none":      render_objects.BoxFitNone,
+		"scaleDown": render_objects.BoxFitScaleDown,
+	}
+	textAligns = map[string]render_objects.TextAlign{
+		"left":   render_objects.TextAlignLeft,
+		"center": render_objects.TextAlignCenter,
+		"right":  render_objects.TextAlignRight,
+	}
+	borderStyles = map[string]cv.BorderStyle{
+		"solid":  cv.BorderStyleSolid,
+		"dashed": cv.BorderStyleDashed,
+		"dotted": cv.BorderStyleDotted,
+		"none":   cv.BorderStyleNone,
+	}
+	colorSpaces = map[string]colors.Space{
+		"srgb":   colors.SpaceSRGB,
+		"linear": colors.SpaceLinear,
+		"oklab":  colors.SpaceOKLab,
+	}
+)
+
+var black = color.RGBA{0, 0, 0, 255}
+
+// MaxEffect bounds blur radii and shadow offsets and spreads, whose cost grows with their size
+const MaxEffect = 1000
+
+func registerBuiltins(r *Registry) {
+	r.Register("Row", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Row{
+			Children:  n.Children("children"),
+			Alignment: Enum(n, "mainAxisAlignment", mainAxisAlignments, types.MainAxisAlignmentStart),
+			Sizing:    Enum(n, "mainAxisSize", mainAxisSizes, types.MainAxisSizeMin),
+		}, n.Err()
+	})
+	r.Register("Column", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Column{
+			Children:  n.Children("children"),
+			Alignment: Enum(n, "mainAxisAlignment", mainAxisAlignments, types.MainAxisAlignmentStart),
+			Sizing:    Enum(n, "mainAxisSize", mainAxisSizes, types.MainAxisSizeMin),
+		}, n.Err()
+	})
+	r.Register("Stack", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Stack{
+			Children:  n.Children("children"),
+			Alignment: Enum(n, "alignment", alignments, render_objects.AlignTopLeft),
+			Fit:       Enum(n, "fit", stackFits, render_objects.StackFitLoose),
+		}, n.Err()
+	})
+	r.Register("Positioned", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Positioned{
+			Child:  n.Child("child"),
+			Left:   n.IntPtr("left"),
+			Top:    n.IntPtr("top"),
+			Right:  n.IntPtr("right"),
+			Bottom: n.IntPtr("bottom"),
+			Width:  n.IntPtr("width"),
+			Height: n.IntPtr("height"),
+		}, n.Err()
+	})
+	r.Register("Align", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Align{
+			Child: n.Child("child"),
+			Align: Enum(n, "alignment", alignments, render_objects.AlignCenter),
+		}, n.Err()
+	})
+	r.Register("Padding", func(n *Node) (render_objects.RenderObject, error) {
+		insets := n.Insets("padding")
+		return render_objects.NewPaddingWithSides(n.Child("child"), insets.Top, insets.Right, insets.Bottom, insets.Left), n.Err()
+	})
+	r.Register("Border", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Border{
+			Child: n.Child("child"),
+			Width: n.Int("width", 1),
+			Color: n.Color("color", black),
+		}, n.Err()
+	})
+	r.Register("ColoredBox", func(n *Node) (render_objects.RenderObject, error) {
+		n.Require("width", "height")
+		return &render_objects.ColoredBox{
+			Color:  n.Color("color", black),
+			Width:  n.Int("width", 0),
+			Height: n.Int("height", 0),
+		}, n.Err()
+	})
+	r.Register("Text", func(n *Node) (render_objects.RenderObject, error) {
+		n.Require("text")
+		text, textColor := n.String("text", ""), n.Color("color", black)
+		fontSize, font := n.Float("fontSize", 16), n.String("font", cv.DefaultFont)
+		if _, _, ok := cv.LookupFont(font); !ok {
+			n.Errorf("font", "unknown font %q, register it with canvas.RegisterFont", font)
+		}
+		if n.Bool("wrap", false) {
+			align := Enum(n, "align", textAligns, render_objects.TextAlignLeft)
+			return render_objects.NewAlignedText(text, textColor, fontSize, font, align), n.Err()
+		}
+		return render_objects.NewText(text, textColor, fontSize, font), n.Err()
+	})
+	r.Register("SizedBox", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.SizedBox{
+			Child:  n.OptionalChild("child"),
+			Width:  n.IntPtr("width"),
+			Height: n.IntPtr("height"),
+		}, n.Err()
+	})
+	r.Register("ConstrainedBox", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.ConstrainedBox{
+			Child:     n.Child("child"),
+			MinWidth:  n.Int("minWidth", 0),
+			MaxWidth:  n.Int("maxWidth", 0),
+			MinHeight: n.Int("minHeight", 0),
+			MaxHeight: n.Int("maxHeight", 0),
+		}, n.Err()
+	})
+	r.Register("AspectRatio", func(n *Node) (render_objects.RenderObject, error) {
+		n.Require("ratio")
+		return &render_objects.AspectRatio{Child: n.Child("child"), Ratio: n.Float("ratio", 1)}, n.Err()
+	})
+	r.Register("FractionallySizedBox", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.FractionallySizedBox{
+			Child:        n.Child("child"),
+			WidthFactor:  n.Float("widthFactor", 0),
+			HeightFactor: n.Float("heightFactor", 0),
+		}, n.Err()
+	})
+	r.Register("FittedBox", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.FittedBox{
+			Child:     n.Child("child"),
+			Fit:       Enum(n, "fit", boxFits, render_objects.BoxFitContain),
+			Alignment: Enum(n, "alignment", alignments, render_objects.AlignCenter),
+		}, n.Err()
+	})
+	r.Register("IntrinsicWidth", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.IntrinsicWidth{Child: n.Child("child")}, n.Err()
+	})
+	r.Register("IntrinsicHeight", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.IntrinsicHeight{Child: n.Child("child")}, n.Err()
+	})
+	r.Register("Container", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Container{
+			Child:      n.OptionalChild("child"),
+			Decoration: decoration(n.Object("decoration")),
+			Padding:    n.Insets("padding"),
+			Margin:     n.Insets("margin"),
+			Width:      n.IntPtr("width"),
+			Height:     n.IntPtr("height"),
+			Alignment:  Enum(n, "alignment", alignments, render_objects.AlignTopLeft),
+		}, n.Err()
+	})
+	r.Register("DecoratedBox", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.DecoratedBox{
+			Child:      n.Child("child"),
+			Decoration: decoration(n.Object("decoration")),
+		}, n.Err()
+	})
+	r.Register("Opacity", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Opacity{Child: n.Child("child"), Opacity: n.Float("opacity", 1)}, n.Err()
+	})
+	r.Register("Blur", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Blur{Child: n.Child("child"), Radius: effect(n, "radius", n.Float("radius", 0))}, n.Err()
+	})
+	r.Register("Shadow", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Shadow{
+			Child:   n.Child("child"),
+			Color:   n.Color("color", color.RGBA{0, 0, 0, 128}),
+			OffsetX: effect(n, "offsetX", n.Int("offsetX", 0)),
+			OffsetY: effect(n, "offsetY", n.Int("offsetY", 0)),
+			Blur:    effect(n, "blur", n.Float("blur", 0)),
+		}, n.Err()
+	})
+	r.Register("RotatedBox", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.RotatedBox{Child: n.Child("child"), QuarterTurns: n.Int("quarterTurns", 0)}, n.Err()
+	})
+	r.Register("Transform", func(n *Node) (render_objects.RenderObject, error) {
+		// Applied in the order scale, skew, rotate, translate
+		m := cv.Identity()
+		if scale := n.Floats("scale"); len(scale) == 1 {
+			m = m.Scale(scale[0], scale[0])
+		} else if len(scale) == 2 {
+			m = m.Scale(scale[0], scale[1])
+		} else if scale != nil {
+			n.Errorf("scale", "expected a number or a list of two numbers")
+		}
+		if n.Has("skew") {
+			skew := n.Point("skew", [2]float64{})
+			m = m.Skew(skew[0], skew[1])
+		}
+		m = m.Rotate(n.Float("rotate", 0))
+		if n.Has("translate") {
+			translate := n.Point("translate", [2]float64{})
+			m = m.Translate(translate[0], translate[1])
+		}
+		return &render_objects.Transform{
+			Child:  n.Child("child"),
+			Matrix: m,
+			Origin: Enum(n, "origin", alignments, render_objects.AlignCenter),
+		}, n.Err()
+	})
+	r.Register("Semantic", func(n *Node) (render_objects.RenderObject, error) {
+		return &render_objects.Semantic{
+			Child: n.Child("child"),
+			ID:    n.String("id", ""),
+			Link:  n.String("link", ""),
+			Label: n.String("label", ""),
+		}, n.Err()
+	})
+}
+
+// decoration reads the background, corners, border and shadows of a box
+func decoration(n *Node) render_objects.BoxDecoration {
+	var d render_objects.BoxDecoration
+	if n == nil {
+		return d
+	}
+
+	if n.Has("color") {
+		d.Background = cv.SolidColor{Color: n.Color("color", black)}
+	}
+	if g := n.Object("gradient"); g != nil {
+		d.Background = gradient(g)
+	}
+
+	if radius, ok := n.props["borderRadius"].(map[string]any); ok && radius != nil {
+		o := n.Object("borderRadius")
+		d.BorderRadius = cv.Radii{
+			TopLeft:     o.Int("topLeft", 0),
+			TopRight:    o.Int("topRight", 0),
+			BottomRight: o.Int("bottomRight", 0),
+			BottomLeft:  o.Int("bottomLeft", 0),
+		}
+	} else {
+		d.BorderRadius = cv.RadiiAll(n.Int("borderRadius", 0))
+	}
+
+	if b := n.Object("border"); b != nil {
+		if b.Has("top") || b.Has("right") || b.Has("bottom") || b.Has("left") {
+			d.Border = cv.BorderSides{
+				Top:    borderSide(b.Object("top")),
+				Right:  borderSide(b.Object("right")),
+				Bottom: borderSide(b.Object("bottom")),
+				Left:   borderSide(b.Object("left")),
+			}
+		} else {
+			d.Border = cv.UniformBorder(borderSide(b))
+		}
+	}
+
+	for _, s := range n.Objects("shadows") {
+		d.Shadows = append(d.Shadows, cv.BoxShadow{
+			Color:   s.Color("color", color.RGBA{0, 0, 0, 128}),
+			OffsetX: effect(s, "offsetX", s.Int("offsetX", 0)),
+			OffsetY: effect(s, "offsetY", s.Int("offsetY", 0)),
+			Blur:    effect(s, "blur", s.Int("blur", 0)),
+			Spread:  effect(s, "spread", s.Int("spread", 0)),
+			Inset:   s.Bool("inset", false),
+		})
+	}
+	return d
+}
+
+// effect records an error for a blur or shadow value further than MaxEffect from zero
+func effect[T int | float64](n *Node, key string, value T) T {
+	if value < -MaxEffect || value > MaxEffect {
+		n.Errorf(key, "expected a value from -%d to %d, got %v", MaxEffect, MaxEffect, value)
+		return 0
+	}
+	return value
+}
+
+func borderSide(n *Node) cv.BorderSide {
+	if n == nil {
+		return cv.BorderSide{Style: cv.BorderStyleNone}
+	}
+	return cv.BorderSide{
+		Width: n.Int("width", 1),
+		Color: n.Color("color", black),
+		Style: Enum(n, "style", borderStyles, cv.BorderStyleSolid),
+	}
+}
+
+func gradient(n *Node) cv.Shader {
+	var stops []cv.GradientStop
+	for _, s := range n.Objects("stops") {
+		s.Require("offset", "color")
+		stops = append(stops, cv.GradientStop{Offset: s.Float("offset", 0), Color: s.Color("color", black)})
+	}
+	interpolation := Enum(n, "interpolation", colorSpaces, colors.SpaceSRGB)
+
+	switch n.String("type", "linear") {
+	case "linear":
+		return cv.LinearGradient{
+			Start:         n.Point("start", [2]float64{0, 0}),
+			End:           n.Point("end", [2]float64{1, 0}),
+			Stops:         stops,
+			Interpolation: interpolation,
+		}
+	case "radial":
+		return cv.RadialGradient{
+			Center:        n.Point("center", [2]float64{0.5, 0.5}),
+			Radius:        n.Float("radius", 0.5),
+			Stops:         stops,
+			Interpolation: interpolation,
+		}
+	default:
+		n.Errorf("type", "unknown gradient type %q, expected \"linear\" or \"radial\"", n.String("type", ""))
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+// Package scene builds render trees from JSON or YAML documents, so layouts can be
+// written and edited without Go code.
+//
+// Every render object is an object with a "type" and the properties of that type.
+// Properties left out take their default; unknown properties, wrong value types and
+// unknown enum values are errors reported with the JSON path of the value, such as
+//
+//	$.children[1].child.color: invalid color "#12": expected 3, 4, 6 or 8 hex digits, got 2
+//
+// # Values
+//
+//   - Colors are CSS color strings, see colors.Parse: "#1e90ff", "rgb(0 0 0 / 50%)", "tomato".
+//   - Insets are a number for every side, a [vertical, horizontal] or [top, right, bottom, left]
+//     list, or an object with any of top, right, bottom, left, horizontal and vertical.
+//   - Alignments are topLeft, topCenter, topRight, leftCenter, center, rightCenter,
+//     bottomLeft, bottomCenter and bottomRight.
+//
+// # Types
+//
+//	Row, Column       children, mainAxisAlignment (start, center, end, spaceBetween,
+//	                  spaceAround, spaceEvenly), mainAxisSize (min, max)
+//	Stack             children, alignment (topLeft), fit (loose, expand, passthrough)
+//	Positioned        child, left, top, right, bottom, width, height
+//	Align             child, alignment (center)
+//	Padding           child, padding (insets)
+//	Border            child, width (1), color (black)
+//	ColoredBox        width, height, color (black)
+//	Text              text, color (black), fontSize (16), font (default, bold, italic,
+//	                  bold-italic, mono or any registered font), wrap (false),
+//	                  align of wrapped lines (left, center, right)
+//	SizedBox          child (optional), width, height
+//	ConstrainedBox    child, minWidth, maxWidth, minHeight, maxHeight
+//	AspectRatio       child, ratio
+//	FractionallySizedBox  child, widthFactor, heightFactor
+//	FittedBox         child, fit (contain, cover, fill, fitWidth, fitHeight, none, scaleDown),
+//	                  alignment (center)
+//	IntrinsicWidth, IntrinsicHeight  child
+//	Container         child (optional), decoration, padding, margin, width, height,
+//	                  alignment (topLeft)
+//	DecoratedBox      child, decoration
+//	Opacity           child, opacity (1)
+//	Blur              child, radius
+//	Shadow            child, color, offsetX, offsetY, blur
+//	RotatedBox        child, quarterTurns
+//	Transform         child, scale (number or [x, y]), skew ([x, y] degrees), rotate (degrees),
+//	                  translate ([x, y]), origin (center); applied in that order
+//	Semantic          child, id, link, label
+//
+// A decoration has these properties, all optional:
+//
+//	color         background color
+//	gradient      {type: linear or radial, start, end, center, radius,
+//	              stops: [{offset, color}], interpolation: srgb, linear or oklab}
+//	borderRadius  number, or {topLeft, topRight, bottomRight, bottomLeft}
+//	border        {width, color, style: solid, dashed, dotted or none},
+//	              or {top, right, bottom, left} each with those properties
+//	shadows       [{color, offsetX, offsetY, blur, spread, inset}]
+//
+// Blur radii and shadow offsets and spreads range from -MaxEffect to MaxEffect.
+//
+// # Custom types
+//
+// Register adds Go render objects to the schema. The factory reads properties through
+// the Node and returns Node.Err, which holds the first problem found:
+//
+//	scene.Register("Badge", func(n *scene.Node) (render_objects.RenderObject, error) {
+//		n.Require("label")
+//		return NewBadge(n.String("label", ""), n.Color("color", canvas.Red)), n.Err()
+//	})
+package scene
@@ -0,0 +1,435 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expression is a parsed template expression: a condition or value, optionally piped through formatters
+type expression interface {
+	eval(scope *scope) (any, error)
+}
+
+// missing is the value of a variable that isn't in the data
+type missing struct{ name string }
+
+type literal struct{ value any }
+
+type variable struct{ path []any } // Keys and indexes, such as user, friends, 0, name
+
+type not struct{ operand expression }
+
+type logical struct {
+	and         bool
+	left, right expression
+}
+
+type comparison struct {
+	op          string
+	left, right expression
+}
+
+type pipe struct {
+	input     expression
+	formatter string
+	args      []expression
+}
+
+func (l literal) eval(*scope) (any, error) { return l.value, nil }
+
+func (v variable) eval(s *scope) (any, error) { return s.lookup(v.path), nil }
+
+func (n not) eval(s *scope) (any, error) {
+	v, err := n.operand.eval(s)
+	return !truthy(v), err
+}
+
+func (l logical) eval(s *scope) (any, error) {
+	left, err := l.left.eval(s)
+	if err != nil || truthy(left) != l.and {
+		return left, err
+	}
+	return l.right.eval(s)
+}
+
+func (c comparison) eval(s *scope) (any, error) {
+	left, err := c.left.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if c.op == "==" || c.op == "!=" {
+		return equal(left, right) == (c.op == "=="), nil
+	}
+
+	var order int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("can't compare a number with %s", kindOf(right))
+		}
+		order = compare(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("can't compare a string with %s", kindOf(right))
+		}
+		order = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("can't order %s", kindOf(left))
+	}
+	switch c.op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+func (p pipe) eval(s *scope) (any, error) {
+	input, err := p.input.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]any, len(p.args))
+	for i, arg := range p.args {
+		if args[i], err = arg.eval(s); err != nil {
+			return nil, err
+		}
+	}
+	format, ok := formatters[p.formatter]
+	if !ok {
+		return nil, fmt.Errorf("unknown formatter %q", p.formatter)
+	}
+	// Only default may be given a missing variable
+	if m, isMissing := input.(missing); isMissing && p.formatter != "default" {
+		return nil, fmt.Errorf("unknown variable %q", m.name)
+	}
+	for _, arg := range args {
+		if m, isMissing := arg.(missing); isMissing {
+			return nil, fmt.Errorf("unknown variable %q", m.name)
+		}
+	}
+	value, err := format(input, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.formatter, err)
+	}
+	return value, nil
+}
+
+func compare(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func equal(a, b any) bool {
+	if _, ok := a.(missing); ok {
+		a = nil
+	}
+	if _, ok := b.(missing); ok {
+		b = nil
+	}
+	switch a.(type) {
+	case nil, bool, float64, string:
+		return a == b
+	}
+	return false
+}
+
+// truthy is false for null, false, 0, "", empty lists and objects and missing variables
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil, missing:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
+
+func kindOf(v any) string {
+	if _, ok := v.(missing); ok {
+		return "a missing value"
+	}
+	return kind(v)
+}
+
+// Tokens
+
+type token struct {
+	kind  byte // 'i' identifier path, 'n' number, 's' string, 'o' operator or punctuation
+	text  string
+	value any
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, errors.New("unterminated string")
+			}
+			text := src[i+1 : end]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(src[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid string %s", src[i:end+1])
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, token{kind: 's', text: src[i : end+1], value: text})
+			i = end + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9' && !endsOperand(tokens):
+			end := i + 1
+			for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.') {
+				end++
+			}
+			n, err := strconv.ParseFloat(src[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", src[i:end])
+			}
+			tokens = append(tokens, token{kind: 'n', text: src[i:end], value: n})
+			i = end
+		case c == '_' || c == '$' || unicode.IsLetter(rune(c)):
+			end := i
+			for end < len(src) {
+				r := src[end]
+				if r == '_' || r == '$' || r == '.' || r == '[' || r == ']' || r >= '0' && r <= '9' || unicode.IsLetter(rune(r)) {
+					end++
+					continue
+				}
+				break
+			}
+			tokens = append(tokens, token{kind: 'i', text: src[i:end]})
+			i = end
+		default:
+			op := string(c)
+			if i+1 < len(src) {
+				if two := src[i : i+2]; two == "==" || two == "!=" || two == "<=" || two == ">=" || two == "&&" || two == "||" {
+					op = two
+				}
+			}
+			if !strings.Contains("== != <= >= && || < > ! | : ( )", op) {
+				return nil, fmt.Errorf("unexpected %q", op)
+			}
+			tokens = append(tokens, token{kind: 'o', text: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// endsOperand reports whether the last token ends an operand, making a following - a minus sign
+func endsOperand(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	return last.kind != 'o' || last.text == ")"
+}
+
+// Parser
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseExpression parses a template expression such as `user.followers | number` or `score >= 10 and premium`
+func parseExpression(src string) (expression, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+	p := &exprParser{tokens: tokens}
+	e, err := p.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return e, nil
+}
+
+func (p *exprParser) peek(texts ...string) bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	t := p.tokens[p.pos]
+	for _, text := range texts {
+		if (t.kind == 'o' || t.kind == 'i') && t.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *exprParser) pipeline() (expression, error) {
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("|") {
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != 'i' {
+			return nil, errors.New("expected a formatter name after |")
+		}
+		stage := pipe{input: e, formatter: p.tokens[p.pos].text}
+		p.pos++
+		for p.peek(":") {
+			p.pos++
+			arg, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			stage.args = append(stage.args, arg)
+		}
+		e = stage
+	}
+	return e, nil
+}
+
+func (p *exprParser) or() (expression, error) {
+	left, err := p.and()
+	for err == nil && p.peek("||", "or") {
+		p.pos++
+		var right expression
+		right, err = p.and()
+		left = logical{and: false, left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *exprParser) and() (expression, error) {
+	left, err := p.unary()
+	for err == nil && p.peek("&&", "and") {
+		p.pos++
+		var right expression
+		right, err = p.unary()
+		left = logical{and: true, left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *exprParser) unary() (expression, error) {
+	if p.peek("!", "not") {
+		p.pos++
+		operand, err := p.unary()
+		return not{operand: operand}, err
+	}
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek("==", "!=", "<", "<=", ">", ">=") {
+		op := p.tokens[p.pos].text
+		p.pos++
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return comparison{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) operand() (expression, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case 'n', 's':
+		return literal{value: t.value}, nil
+	case 'i':
+		switch t.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "null":
+			return literal{value: nil}, nil
+		}
+		path, err := parsePath(t.text)
+		if err != nil {
+			return nil, err
+		}
+		return variable{path: path}, nil
+	}
+	if t.text == "(" {
+		e, err := p.pipeline()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return e, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// parsePath splits a variable path such as users[0].name into keys and indexes
+func parsePath(text string) ([]any, error) {
+	var path []any
+	for _, part := range strings.Split(text, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" && len(path) == 0 {
+			return nil, fmt.Errorf("invalid variable %q", text)
+		}
+		if name != "" {
+			path = append(path, name)
+		}
+		for rest != "" {
+			index, after, ok := strings.Cut(rest, "]")
+			n, err := strconv.Atoi(index)
+			if !ok || err != nil || (after != "" && after[0] != '[') {
+				return nil, fmt.Errorf("invalid variable %q", text)
+			}
+			path = append(path, n)
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+	return path, nil
+}
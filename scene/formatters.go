@@ -0,0 +1,295 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formatter converts a value inside a template placeholder, such as number in
+// {{followers | number}}. Arguments follow the name separated by colons.
+type Formatter func(value any, args ...any) (any, error)
+
+var formatters = map[string]Formatter{
+	"number":   formatNumber,
+	"compact":  formatCompact,
+	"percent":  formatPercent,
+	"currency": formatCurrency,
+	"date":     formatDate,
+	"upper":    stringFormatter(strings.ToUpper),
+	"lower":    stringFormatter(strings.ToLower),
+	"title":    stringFormatter(title),
+	"trim":     stringFormatter(strings.TrimSpace),
+	"truncate": formatTruncate,
+	"default":  formatDefault,
+	"join":     formatJoin,
+	"length":   formatLength,
+}
+
+// RegisterFormatter adds a formatter usable in every template, replacing any of the same name
+func RegisterFormatter(name string, formatter Formatter) {
+	formatters[name] = formatter
+}
+
+// Named date layouts accepted by the date formatter besides Go layouts
+var dateLayouts = map[string]string{
+	"short":    "Jan 2",
+	"medium":   "Jan 2, 2006",
+	"long":     "January 2, 2006",
+	"iso":      "2006-01-02",
+	"time":     "15:04",
+	"datetime": "Jan 2, 2006 15:04",
+}
+
+// Layouts date strings in data are parsed with
+var dateInputs = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+func toNumber(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", v)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %s", kindOf(value))
+}
+
+// intArg reads an optional whole number argument
+func intArg(args []any, i, def int) (int, error) {
+	if len(args) <= i {
+		return def, nil
+	}
+	n, ok := args[i].(float64)
+	if !ok || n != math.Trunc(n) || n < 0 {
+		return 0, fmt.Errorf("argument %d must be a whole number", i+1)
+	}
+	return int(n), nil
+}
+
+func stringArg(args []any, i int, def string) (string, error) {
+	if len(args) <= i {
+		return def, nil
+	}
+	s, ok := args[i].(string)
+	if !ok {
+		return "", fmt.Errorf("argument %d must be a string", i+1)
+	}
+	return s, nil
+}
+
+// formatNumber writes a number with thousands separators: number, number:2
+func formatNumber(value any, args ...any) (any, error) {
+	n, err := toNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return groupThousands(strconv.FormatFloat(n, 'f', decimals, 64)), nil
+}
+
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteString("." + fraction)
+	}
+	return sign + b.String()
+}
+
+// formatCompact abbreviates large numbers: 1234 gives 1.2K, 5600000 gives 5.6M
+func formatCompact(value any, args ...any) (any, error) {
+	n, err := toNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	units := []struct {
+		size   float64
+		suffix string
+	}{{1e12, "T"}, {1e9, "B"}, {1e6, "M"}, {1e3, "K"}}
+	for i, unit := range units {
+		if math.Abs(n) < unit.size {
+			continue
+		}
+		// Round before settling on the unit, so 999950 gives 1M rather than 1000K
+		scaled := math.Round(n/unit.size*10) / 10
+		if math.Abs(scaled) >= 1000 && i > 0 {
+			unit = units[i-1]
+			scaled = math.Round(n/unit.size*10) / 10
+		}
+		return strconv.FormatFloat(scaled, 'f', -1, 64) + unit.suffix, nil
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64), nil
+}
+
+// formatPercent writes a fraction as a percentage: 0.25 gives 25%, percent:1 keeps a decimal
+func formatPercent(value any, args ...any) (any, error) {
+	n, err := toNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.FormatFloat(n*100, 'f', decimals, 64) + "%", nil
+}
+
+// formatCurrency writes an amount with two decimals after a symbol: currency, currency:"€"
+func formatCurrency(value any, args ...any) (any, error) {
+	n, err := toNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := stringArg(args, 0, "$")
+	if err != nil {
+		return nil, err
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	return sign + symbol + groupThousands(strconv.FormatFloat(n, 'f', 2, 64)), nil
+}
+
+// formatDate formats an RFC 3339 or yyyy-mm-dd string, or Unix seconds, with a named or
+// Go layout: date, date:"long", date:"Mon Jan 2"
+func formatDate(value any, args ...any) (any, error) {
+	var t time.Time
+	switch v := value.(type) {
+	case float64:
+		t = time.Unix(int64(v), 0).UTC()
+	case string:
+		var err error
+		for _, layout := range dateInputs {
+			if t, err = time.Parse(layout, v); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date", v)
+		}
+	default:
+		return nil, fmt.Errorf("expected a date, got %s", kindOf(value))
+	}
+
+	layout, err := stringArg(args, 0, "medium")
+	if err != nil {
+		return nil, err
+	}
+	if named, ok := dateLayouts[layout]; ok {
+		layout = named
+	}
+	return t.Format(layout), nil
+}
+
+func stringFormatter(convert func(string) string) Formatter {
+	return func(value any, args ...any) (any, error) {
+		return convert(stringify(value)), nil
+	}
+}
+
+func title(s string) string {
+	start := true
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			start = true
+			return r
+		}
+		if start {
+			start = false
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
+
+// formatTruncate shortens text to a number of characters, ending with an ellipsis: truncate:40
+func formatTruncate(value any, args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, errors.New("expected the maximum length, like truncate:40")
+	}
+	limit, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	text := stringify(value)
+	if utf8.RuneCountInString(text) <= limit {
+		return text, nil
+	}
+	runes := []rune(text)
+	return strings.TrimRight(string(runes[:max(limit-1, 0)]), " ") + "…", nil
+}
+
+// formatDefault replaces missing, null and empty values: default:"Anonymous"
+func formatDefault(value any, args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New(`expected the default value, like default:"none"`)
+	}
+	switch v := value.(type) {
+	case nil, missing:
+		return args[0], nil
+	case string:
+		if v == "" {
+			return args[0], nil
+		}
+	case []any:
+		if len(v) == 0 {
+			return args[0], nil
+		}
+	}
+	return value, nil
+}
+
+// formatJoin joins the elements of a list: join, join:" · "
+func formatJoin(value any, args ...any) (any, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %s", kindOf(value))
+	}
+	separator, err := stringArg(args, 0, ", ")
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]string, len(list))
+	for i, item := range list {
+		parts[i] = stringify(item)
+	}
+	return strings.Join(parts, separator), nil
+}
+
+// formatLength is the number of elements of a list or object, or characters of a string
+func formatLength(value any, args ...any) (any, error) {
+	switch v := value.(type) {
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	case nil:
+		return 0.0, nil
+	}
+	return nil, fmt.Errorf("expected a list, object or string, got %s", kindOf(value))
+}
@@ -0,0 +1,371 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Node is an object of the scene being built, either a render object or a nested
+// property object such as a decoration. Its accessors read properties with a default,
+// recording the first invalid one so factories can check Err once at the end.
+// Properties that no accessor reads are reported as unknown.
+type Node struct {
+	Type  string // Type name of a render object node, empty for nested objects
+	Path  string // JSON path of the node, such as $.children[0]
+	props map[string]any
+	used  map[string]bool
+	state *buildState
+	nodes []*Node // Nested objects read through Object and Objects
+}
+
+func newNode(state *buildState, typeName, path string, props map[string]any) *Node {
+	return &Node{Type: typeName, Path: path, props: props, used: map[string]bool{}, state: state}
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// path returns the JSON path of a property of the node
+func (n *Node) path(key string) string {
+	return propertyPath(n.Path, key)
+}
+
+// Errorf records an error about a property of the node
+func (n *Node) Errorf(key, format string, args ...any) {
+	n.state.fail(n.path(key), fmt.Errorf(format, args...))
+}
+
+// Err returns the first error recorded while building the scene
+func (n *Node) Err() error {
+	return n.state.err
+}
+
+// Has reports whether the property is set
+func (n *Node) Has(key string) bool {
+	_, ok := n.props[key]
+	return ok
+}
+
+// Value returns the raw decoded value of a property
+func (n *Node) Value(key string) (any, bool) {
+	value, ok := n.props[key]
+	n.used[key] = true
+	return value, ok
+}
+
+// Require records an error for each of the properties that is missing
+func (n *Node) Require(keys ...string) {
+	for _, key := range keys {
+		if !n.Has(key) {
+			n.state.fail(n.Path, fmt.Errorf("missing required property %q", key))
+		}
+	}
+}
+
+func (n *Node) typeError(key, expected string, value any) {
+	n.Errorf(key, "expected %s, got %s", expected, kind(value))
+}
+
+// String reads a string property
+func (n *Node) String(key, def string) string {
+	value, ok := n.Value(key)
+	if !ok {
+		return def
+	}
+	s, ok := value.(string)
+	if !ok {
+		n.typeError(key, "a string", value)
+		return def
+	}
+	return s
+}
+
+// Float reads a number property
+func (n *Node) Float(key string, def float64) float64 {
+	value, ok := n.Value(key)
+	if !ok {
+		return def
+	}
+	f, ok := value.(float64)
+	if !ok {
+		n.typeError(key, "a number", value)
+		return def
+	}
+	return f
+}
+
+// Int reads a whole number property
+func (n *Node) Int(key string, def int) int {
+	value, ok := n.Value(key)
+	if !ok {
+		return def
+	}
+	f, ok := value.(float64)
+	if !ok || f != math.Trunc(f) {
+		n.typeError(key, "a whole number", value)
+		return def
+	}
+	return int(f)
+}
+
+// IntPtr reads an optional whole number property, nil when it isn't set
+func (n *Node) IntPtr(key string) *int {
+	if !n.Has(key) {
+		return nil
+	}
+	v := n.Int(key, 0)
+	return &v
+}
+
+// Bool reads a boolean property
+func (n *Node) Bool(key string, def bool) bool {
+	value, ok := n.Value(key)
+	if !ok {
+		return def
+	}
+	b, ok := value.(bool)
+	if !ok {
+		n.typeError(key, "true or false", value)
+		return def
+	}
+	return b
+}
+
+// Floats reads a list of numbers, a single number giving a list of one
+func (n *Node) Floats(key string) []float64 {
+	value, ok := n.Value(key)
+	if !ok {
+		return nil
+	}
+	if f, ok := value.(float64); ok {
+		return []float64{f}
+	}
+	list, ok := value.([]any)
+	if !ok {
+		n.typeError(key, "a number or a list of numbers", value)
+		return nil
+	}
+	floats := make([]float64, len(list))
+	for i, item := range list {
+		f, ok := item.(float64)
+		if !ok {
+			n.state.fail(fmt.Sprintf("%s[%d]", n.path(key), i), fmt.Errorf("expected a number, got %s", kind(item)))
+			return nil
+		}
+		floats[i] = f
+	}
+	return floats
+}
+
+// Point reads an [x, y] pair
+func (n *Node) Point(key string, def [2]float64) [2]float64 {
+	if !n.Has(key) {
+		return def
+	}
+	floats := n.Floats(key)
+	if len(floats) != 2 {
+		if n.Err() == nil {
+			n.Errorf(key, "expected a list of two numbers, got %d", len(floats))
+		}
+		return def
+	}
+	return [2]float64{floats[0], floats[1]}
+}
+
+// Color reads a CSS color string, see colors.Parse
+func (n *Node) Color(key string, def color.RGBA) color.RGBA {
+	value, ok := n.Value(key)
+	if !ok {
+		return def
+	}
+	s, ok := value.(string)
+	if !ok {
+		if value == nil {
+			// A common YAML mistake: an unquoted #rrggbb starts a comment
+			n.Errorf(key, "expected a color string, got null (quote colors starting with # in YAML)")
+		} else {
+			n.typeError(key, "a color string", value)
+		}
+		return def
+	}
+	c, err := colors.Parse(s)
+	if err != nil {
+		n.state.fail(n.path(key), err)
+		return def
+	}
+	return c
+}
+
+// Insets reads edge insets given as a number for all sides, a [vertical, horizontal]
+// or [top, right, bottom, left] list, or an object with top, right, bottom, left,
+// horizontal and vertical properties
+func (n *Node) Insets(key string) types.EdgeInsets {
+	value, ok := n.Value(key)
+	if !ok {
+		return types.EdgeInsets{}
+	}
+	switch value.(type) {
+	case float64, []any:
+		v := n.Floats(key)
+		for i, f := range v {
+			if f != math.Trunc(f) {
+				n.state.fail(fmt.Sprintf("%s[%d]", n.path(key), i), errors.New("expected a whole number"))
+				return types.EdgeInsets{}
+			}
+		}
+		switch len(v) {
+		case 1:
+			return types.EdgeInsetsAll(int(v[0]))
+		case 2:
+			return types.EdgeInsetsSymmetric(int(v[0]), int(v[1]))
+		case 4:
+			return types.EdgeInsets{Top: int(v[0]), Right: int(v[1]), Bottom: int(v[2]), Left: int(v[3])}
+		}
+		if n.Err() == nil {
+			n.Errorf(key, "expected 1, 2 or 4 numbers, got %d", len(v))
+		}
+		return types.EdgeInsets{}
+	case map[string]any:
+		o := n.Object(key)
+		horizontal, vertical := o.Int("horizontal", 0), o.Int("vertical", 0)
+		return types.EdgeInsets{
+			Top:    o.Int("top", vertical),
+			Right:  o.Int("right", horizontal),
+			Bottom: o.Int("bottom", vertical),
+			Left:   o.Int("left", horizontal),
+		}
+	default:
+		n.typeError(key, "a number, a list or an object", value)
+		return types.EdgeInsets{}
+	}
+}
+
+// Enum reads a string property that must be one of the keys of values
+func Enum[T any](n *Node, key string, values map[string]T, def T) T {
+	name := n.String(key, "")
+	if name == "" {
+		return def
+	}
+	v, ok := values[name]
+	if !ok {
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+		slices.Sort(names)
+		n.Errorf(key, "unknown value %q, expected one of %s", name, strings.Join(names, ", "))
+		return def
+	}
+	return v
+}
+
+// Child reads a required render object property
+func (n *Node) Child(key string) render_objects.RenderObject {
+	value, ok := n.Value(key)
+	if !ok {
+		n.state.fail(n.Path, fmt.Errorf("missing required property %q", key))
+		return nil
+	}
+	return n.state.build(value, n.path(key))
+}
+
+// OptionalChild reads a render object property that may be left out
+func (n *Node) OptionalChild(key string) render_objects.RenderObject {
+	value, ok := n.Value(key)
+	if !ok || value == nil {
+		return nil
+	}
+	return n.state.build(value, n.path(key))
+}
+
+// Children reads a list of render objects
+func (n *Node) Children(key string) []render_objects.RenderObject {
+	value, ok := n.Value(key)
+	if !ok {
+		return nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		n.typeError(key, "a list of render objects", value)
+		return nil
+	}
+	children := make([]render_objects.RenderObject, 0, len(list))
+	for i, item := range list {
+		if child := n.state.build(item, fmt.Sprintf("%s[%d]", n.path(key), i)); child != nil {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Object reads a nested property object, nil when it isn't set
+func (n *Node) Object(key string) *Node {
+	value, ok := n.Value(key)
+	if !ok {
+		return nil
+	}
+	props, ok := value.(map[string]any)
+	if !ok {
+		n.typeError(key, "an object", value)
+		return nil
+	}
+	o := newNode(n.state, "", n.path(key), props)
+	n.nodes = append(n.nodes, o)
+	return o
+}
+
+// Objects reads a list of nested property objects
+func (n *Node) Objects(key string) []*Node {
+	value, ok := n.Value(key)
+	if !ok {
+		return nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		n.typeError(key, "a list of objects", value)
+		return nil
+	}
+	objects := make([]*Node, 0, len(list))
+	for i, item := range list {
+		path := fmt.Sprintf("%s[%d]", n.path(key), i)
+		props, ok := item.(map[string]any)
+		if !ok {
+			n.state.fail(path, fmt.Errorf("expected an object, got %s", kind(item)))
+			continue
+		}
+		o := newNode(n.state, "", path, props)
+		n.nodes = append(n.nodes, o)
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+// checkUnused reports the first property no accessor read, in alphabetical order
+func (n *Node) checkUnused() {
+	keys := make([]string, 0, len(n.props))
+	for key := range n.props {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !n.used[key] {
+			if n.Type != "" {
+				n.Errorf(key, "unknown property %q for %s", key, n.Type)
+			} else {
+				n.Errorf(key, "unknown property %q", key)
+			}
+			return
+		}
+	}
+	for _, o := range n.nodes {
+		o.checkUnused()
+	}
+}
@@ -0,0 +1,226 @@
+package scene
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hvuhsg/render/render_objects"
+)
+
+var ErrUnknownFormat = errors.New("unknown scene format")
+
+// Error reports a problem with the value at Path, a JSON path such as $.children[2].color
+type Error struct {
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Factory builds a render object from a node of the scene.
+// Problems with properties are collected by the node and returned by Node.Err.
+type Factory func(n *Node) (render_objects.RenderObject, error)
+
+// Registry maps the type names used in scenes to the factories building them
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates a registry knowing the built in render objects
+func NewRegistry() *Registry {
+	r := &Registry{factories: map[string]Factory{}}
+	registerBuiltins(r)
+	return r
+}
+
+// Default is the registry used by the package level functions
+var Default = NewRegistry()
+
+// Register adds a type to the schema, replacing any type of the same name
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[name] = factory
+}
+
+// Types returns the names of the registered types in alphabetical order
+func (r *Registry) Types() []string {
+	return slices.Sorted(maps.Keys(r.factories))
+}
+
+// Build creates the render tree described by a decoded JSON value,
+// made of map[string]any, []any, string, float64, bool and nil
+func (r *Registry) Build(value any) (render_objects.RenderObject, error) {
+	state := &buildState{registry: r}
+	object := state.build(value, "$")
+	if state.err != nil {
+		return nil, state.err
+	}
+	return object, nil
+}
+
+// ParseJSON builds the render tree described by a JSON document
+func (r *Registry) ParseJSON(data []byte) (render_objects.RenderObject, error) {
+	value, err := DecodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return r.Build(value)
+}
+
+// ParseYAML builds the render tree described by a YAML document
+func (r *Registry) ParseYAML(data []byte) (render_objects.RenderObject, error) {
+	value, err := DecodeYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	return r.Build(value)
+}
+
+// LoadFile builds the render tree described by a .json, .yaml or .yml file
+func (r *Registry) LoadFile(path string) (render_objects.RenderObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return r.ParseJSON(data)
+	case ".yaml", ".yml":
+		return r.ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+	}
+}
+
+// Register adds a type to the default registry
+func Register(name string, factory Factory) {
+	Default.Register(name, factory)
+}
+
+// Build creates a render tree from a decoded JSON value using the default registry
+func Build(value any) (render_objects.RenderObject, error) {
+	return Default.Build(value)
+}
+
+// ParseJSON builds a render tree from a JSON document using the default registry
+func ParseJSON(data []byte) (render_objects.RenderObject, error) {
+	return Default.ParseJSON(data)
+}
+
+// ParseYAML builds a render tree from a YAML document using the default registry
+func ParseYAML(data []byte) (render_objects.RenderObject, error) {
+	return Default.ParseYAML(data)
+}
+
+// LoadFile builds a render tree from a .json, .yaml or .yml file using the default registry
+func LoadFile(path string) (render_objects.RenderObject, error) {
+	return Default.LoadFile(path)
+}
+
+// DecodeJSON decodes a JSON document into generic values, reporting syntax errors by line and column
+func DecodeJSON(data []byte) (any, error) {
+	var value any
+	err := json.Unmarshal(data, &value)
+	var syntax *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntax):
+		line, column := position(data, syntax.Offset)
+		return nil, fmt.Errorf("invalid JSON at line %d, column %d: %v", line, column, syntax)
+	case errors.As(err, &typeErr):
+		line, column := position(data, typeErr.Offset)
+		return nil, fmt.Errorf("invalid JSON at line %d, column %d: %v", line, column, typeErr)
+	case err != nil:
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return value, nil
+}
+
+// position converts a byte offset into a line and column, both starting at 1
+func position(data []byte, offset int64) (line, column int) {
+	before := data[:min(int(offset), len(data))]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// buildState is shared by the nodes of one build and keeps the first error
+type buildState struct {
+	registry *Registry
+	err      error
+}
+
+func (s *buildState) fail(path string, err error) {
+	if s.err == nil {
+		s.err = &Error{Path: path, Err: err}
+	}
+}
+
+// build creates the render object described by value, located at path
+func (s *buildState) build(value any, path string) render_objects.RenderObject {
+	props, ok := value.(map[string]any)
+	if !ok {
+		s.fail(path, fmt.Errorf("expected an object describing a render object, got %s", kind(value)))
+		return nil
+	}
+	typeName, ok := props["type"].(string)
+	if !ok {
+		if _, present := props["type"]; present {
+			s.fail(path+".type", fmt.Errorf("expected a string, got %s", kind(props["type"])))
+		} else {
+			s.fail(path, errors.New(`missing "type"`))
+		}
+		return nil
+	}
+	factory, ok := s.registry.factories[typeName]
+	if !ok {
+		s.fail(path+".type", fmt.Errorf("unknown type %q, expected one of %s", typeName, strings.Join(s.registry.Types(), ", ")))
+		return nil
+	}
+
+	n := newNode(s, typeName, path, props)
+	n.used["type"] = true
+	object, err := factory(n)
+	if err != nil {
+		var pathErr *Error
+		if errors.As(err, &pathErr) {
+			s.fail(pathErr.Path, pathErr.Err)
+		} else {
+			s.fail(path, err)
+		}
+	}
+	n.checkUnused()
+	return object
+}
+
+// kind describes the JSON type of a decoded value for error messages
+func kind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,194 @@
+package scene
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/colors"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+const card = `{
+	"type": "Container",
+	"padding": [10, 20],
+	"decoration": {"color": "#ffffff", "borderRadius": 8, "border": {"width": 2, "color": "navy"}},
+	"child": {
+		"type": "Column",
+		"mainAxisAlignment": "center",
+		"children": [
+			{"type": "Text", "text": "Hello", "color": "red", "fontSize": 20},
+			{"type": "ColoredBox", "width": 40, "height": 10, "color": "rgb(0 128 0)"}
+		]
+	}
+}`
+
+func TestParseJSON(t *testing.T) {
+	root, err := ParseJSON([]byte(card))
+	if err != nil {
+		t.Fatalf("Expected the card to parse, got %v", err)
+	}
+
+	container, ok := root.(*render_objects.Container)
+	if !ok {
+		t.Fatalf("Expected a Container, got %T", root)
+	}
+	if container.Padding != types.EdgeInsetsSymmetric(10, 20) {
+		t.Errorf("Expected symmetric padding, got %+v", container.Padding)
+	}
+	if container.Decoration.BorderRadius != cv.RadiiAll(8) {
+		t.Errorf("Expected radius 8, got %+v", container.Decoration.BorderRadius)
+	}
+	if container.Decoration.Border.Left.Color != (color.RGBA{0, 0, 128, 255}) {
+		t.Errorf("Expected a navy border, got %+v", container.Decoration.Border)
+	}
+
+	column := container.Child.(*render_objects.Column)
+	if column.Alignment != types.MainAxisAlignmentCenter || len(column.Children) != 2 {
+		t.Errorf("Expected a centered column of two children, got %+v", column)
+	}
+	box := column.Children[1].(*render_objects.ColoredBox)
+	if box.Width != 40 || box.Color != (color.RGBA{0, 128, 0, 255}) {
+		t.Errorf("Expected a green 40 pixel box, got %+v", box)
+	}
+
+	canvas := cv.NewCanvas(types.Size{Width: 200, Height: 100}, false)
+	root.Paint(canvas)
+	if canvas.Img.RGBAAt(21, 40) == (color.RGBA{}) {
+		t.Error("Expected the card to paint")
+	}
+}
+
+func TestErrorPaths(t *testing.T) {
+	tests := []struct {
+		json string
+		err  string
+	}{
+		{`{"type": "Row", "children": [{"type": "Text", "text": "a"}, {"type": "Text", "text": "b", "color": "#12"}]}`,
+			`$.children[1].color: invalid color "#12": expected 3, 4, 6 or 8 hex digits, got 2`},
+		{`{"type": "Align", "child": {"type": "Text"}}`, `$.child: missing required property "text"`},
+		{`{"type": "Align", "alignment": "middle", "child": {"type": "Text", "text": "a"}}`,
+			`$.alignment: unknown value "middle", expected one of "bottomCenter", "bottomLeft", "bottomRight", "center", "leftCenter", "rightCenter", "topCenter", "topLeft", "topRight"`},
+		{`{"type": "Padding", "padding": 4, "child": {"type": "Text", "text": "a", "colour": "red"}}`,
+			`$.child.colour: unknown property "colour" for Text`},
+		{`{"type": "ColoredBox", "width": 10.5, "height": 2}`, `$.width: expected a whole number, got number`},
+		{`{"type": "Blur", "radius": 1e9, "child": {"type": "Text", "text": "a"}}`, `$.radius: expected a value from -1000 to 1000, got 1e+09`},
+		{`{"type": "Container", "decoration": {"shadows": [{"spread": -5000}]}}`, `$.decoration.shadows[0].spread: expected a value from -1000 to 1000, got -5000`},
+		{`{"type": "Column", "children": {}}`, `$.children: expected a list of render objects, got object`},
+		{`{"type": "Container", "decoration": {"shadows": [{"blur": 2, "blurr": 4}]}}`, `$.decoration.shadows[0].blurr: unknown property "blurr"`},
+		{`{"type": "Widget"}`, `$.type: unknown type "Widget"`},
+		{`{"type": "Text", "text": "a", "font": "comic"}`, `$.font: unknown font "comic", register it with canvas.RegisterFont`},
+		{`[1, 2]`, `$: expected an object describing a render object, got array`},
+		{`{"child": {}}`, `$: missing "type"`},
+	}
+
+	for _, test := range tests {
+		_, err := ParseJSON([]byte(test.json))
+		if err == nil {
+			t.Errorf("Expected %s to fail", test.json)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("Expected error %q, got %q", test.err, err)
+		}
+		var pathErr *Error
+		if !errors.As(err, &pathErr) {
+			t.Errorf("Expected a *scene.Error, got %T", err)
+		}
+	}
+}
+
+func TestInvalidJSON(t *testing.T) {
+	_, err := ParseJSON([]byte("{\n  \"type\": \"Text\",\n  \"text\": \"a\",,\n}"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected a syntax error on line 3, got %v", err)
+	}
+}
+
+func TestGradientDecoration(t *testing.T) {
+	root, err := ParseJSON([]byte(`{"type": "DecoratedBox", "child": {"type": "SizedBox", "width": 10, "height": 10},
+		"decoration": {"gradient": {"type": "radial", "radius": 1, "interpolation": "oklab",
+			"stops": [{"offset": 0, "color": "white"}, {"offset": 1, "color": "black"}]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gradient, ok := root.(*render_objects.DecoratedBox).Decoration.Background.(cv.RadialGradient)
+	if !ok || gradient.Radius != 1 || gradient.Interpolation != colors.SpaceOKLab || len(gradient.Stops) != 2 {
+		t.Errorf("Expected a radial OKLab gradient, got %+v", gradient)
+	}
+}
+
+func TestAlignedText(t *testing.T) {
+	root, err := ParseJSON([]byte(`{"type": "Text", "text": "Centered lines", "wrap": true, "align": "center"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, ok := root.(*render_objects.Text)
+	if !ok || text.Describe()["Align"] != render_objects.TextAlignCenter || text.Describe()["Wrap"] != true {
+		t.Errorf("Expected wrapped text with centered lines, got %+v", root)
+	}
+}
+
+type badge struct {
+	render_objects.ColoredBox
+	label string
+}
+
+func TestSemantic(t *testing.T) {
+	root, err := ParseJSON([]byte(`{"type": "Semantic", "id": "cta", "link": "https://example.com", "child": {"type": "ColoredBox", "width": 10, "height": 10}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	semantic, ok := root.(*render_objects.Semantic)
+	if !ok || semantic.ID != "cta" || semantic.Link != "https://example.com" || semantic.Child == nil {
+		t.Errorf("Expected a semantic region linking to example.com, got %+v", root)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("Badge", func(n *Node) (render_objects.RenderObject, error) {
+		n.Require("label")
+		return &badge{ColoredBox: render_objects.ColoredBox{Color: n.Color("color", cv.Red), Width: 10, Height: 10}, label: n.String("label", "")}, n.Err()
+	})
+
+	root, err := registry.ParseJSON([]byte(`{"type": "Row", "children": [{"type": "Badge", "label": "new"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := root.(*render_objects.Row).Children[0].(*badge); b.label != "new" || b.Color != cv.Red {
+		t.Errorf("Expected a red badge labeled new, got %+v", b)
+	}
+
+	if _, err := ParseJSON([]byte(`{"type": "Badge", "label": "new"}`)); err == nil {
+		t.Error("Expected custom types to stay out of the default registry")
+	}
+	if _, err := registry.ParseJSON([]byte(`{"type": "Badge"}`)); err == nil || err.Error() != `$: missing required property "label"` {
+		t.Errorf("Expected a missing label error, got %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "card.json")
+	yamlPath := filepath.Join(dir, "card.yaml")
+	os.WriteFile(jsonPath, []byte(card), 0o644)
+	os.WriteFile(yamlPath, []byte("type: Text\ntext: Hi\n"), 0o644)
+	tomlPath := filepath.Join(dir, "card.toml")
+	os.WriteFile(tomlPath, []byte(card), 0o644)
+
+	if _, err := LoadFile(jsonPath); err != nil {
+		t.Errorf("Expected the JSON file to load, got %v", err)
+	}
+	if _, err := LoadFile(yamlPath); err != nil {
+		t.Errorf("Expected the YAML file to load, got %v", err)
+	}
+	if _, err := LoadFile(tomlPath); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected an error for unknown formats, got %v", err)
+	}
+}
@@ -0,0 +1,453 @@
+package scene
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hvuhsg/render/render_objects"
+)
+
+// Template is a scene with placeholders, built into a new render tree for each data record.
+//
+// Strings may contain {{expression}} placeholders. A string that is a single placeholder
+// takes the value's type, so {"width": "{{bar.width}}"} gives a number; otherwise values are
+// formatted into the text. Expressions are variable paths like user.name or items[0].title,
+// literals, comparisons (== != < <= > >=), and, or, not, and formatter pipes such as
+// {{price | number:2}} or {{joined | date:"Jan 2006"}}.
+//
+// Objects may carry directives, which are removed before the scene is built:
+//
+//	"$if": "expression"   drops the object when the expression is false, null, 0, "" or empty
+//	"$repeat": "list"     repeats a list item once per element of the list, or count times
+//	                      for a whole number count, up to MaxRepeat items in total
+//	"$as": "name"         names the element inside a repeat, item by default
+//
+// Inside a repeat, loop.index (from 0), loop.number (from 1), loop.first and loop.last
+// describe the position. A Template is safe for concurrent use.
+type Template struct {
+	Source   any       // Decoded JSON or YAML of the template
+	Registry *Registry // Registry used by Build, Default when nil
+
+	// Escape, when set, is applied to every value placed into a string, which then always
+	// gives a string. Templates of markup set it to html.EscapeString so data stays text.
+	Escape func(string) string
+
+	expressions sync.Map // Parsed expressions by source text
+}
+
+// MaxRepeat is the largest count a $repeat takes, and the most items all the repeats of
+// one expansion create together, so a template can't ask for unbounded work
+const MaxRepeat = 10000
+
+// NewTemplate creates a template from a decoded JSON or YAML value
+func NewTemplate(source any) *Template {
+	return &Template{Source: source}
+}
+
+// ParseTemplateJSON reads a template from a JSON document
+func ParseTemplateJSON(data []byte) (*Template, error) {
+	source, err := DecodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewTemplate(source), nil
+}
+
+// ParseTemplateYAML reads a template from a YAML document
+func ParseTemplateYAML(data []byte) (*Template, error) {
+	source, err := DecodeYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewTemplate(source), nil
+}
+
+// LoadTemplate reads a template from a .json, .yaml or .yml file
+func LoadTemplate(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseTemplateJSON(data)
+	case ".yaml", ".yml":
+		return ParseTemplateYAML(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+	}
+}
+
+// Build expands the template with data and builds the resulting render tree
+func (t *Template) Build(data any) (render_objects.RenderObject, error) {
+	value, err := t.Expand(data)
+	if err != nil {
+		return nil, err
+	}
+	registry := t.Registry
+	if registry == nil {
+		registry = Default
+	}
+	return registry.Build(value)
+}
+
+// Expand replaces the placeholders and applies the directives of the template, returning
+// a plain scene value. Data is usually a decoded JSON object.
+func (t *Template) Expand(data any) (any, error) {
+	e := &expander{template: t}
+	value, _, err := e.expand(t.Source, "$", &scope{values: map[string]any{}, data: normalize(data)})
+	return value, err
+}
+
+// DecodeRecords reads data records from a JSON array, a single JSON object or JSON lines
+func DecodeRecords(data []byte) ([]any, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		value, err := DecodeJSON(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return value.([]any), nil
+	}
+
+	var records []any
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	for decoder.More() {
+		var record any
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("record %d: invalid JSON: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// scope resolves variables, looking at loop variables before the data
+type scope struct {
+	parent *scope
+	values map[string]any
+	data   any
+}
+
+func (s *scope) lookup(path []any) any {
+	name := path[0].(string)
+	var value any
+	found := false
+	for current := s; current != nil && !found; current = current.parent {
+		value, found = current.values[name]
+	}
+	if !found {
+		root := s
+		for root.parent != nil {
+			root = root.parent
+		}
+		object, _ := root.data.(map[string]any)
+		if value, found = object[name]; !found {
+			return missing{name: formatPath(path)}
+		}
+	}
+
+	for i, step := range path[1:] {
+		switch key := step.(type) {
+		case string:
+			object, ok := value.(map[string]any)
+			if value, found = object[key]; !ok || !found {
+				return missing{name: formatPath(path[:i+2])}
+			}
+		case int:
+			list, ok := value.([]any)
+			if !ok || key < 0 || key >= len(list) {
+				return missing{name: formatPath(path[:i+2])}
+			}
+			value = list[key]
+		}
+	}
+	return value
+}
+
+func formatPath(path []any) string {
+	var b strings.Builder
+	for i, step := range path {
+		switch step := step.(type) {
+		case string:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(step)
+		case int:
+			fmt.Fprintf(&b, "[%d]", step)
+		}
+	}
+	return b.String()
+}
+
+// normalize converts Go data such as []string or map[string]int to the generic JSON values
+// templates work with, encoding and decoding the parts that aren't already generic
+func normalize(data any) any {
+	switch v := data.(type) {
+	case nil, string, float64, bool:
+		return data
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[key] = normalize(item)
+		}
+		return object
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = normalize(item)
+		}
+		return list
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var value any
+	if json.Unmarshal(encoded, &value) != nil {
+		return data
+	}
+	return value
+}
+
+type expander struct {
+	template *Template
+	repeated int // Items created by repeats so far
+}
+
+func (e *expander) expression(src string) (expression, error) {
+	if cached, ok := e.template.expressions.Load(src); ok {
+		return cached.(expression), nil
+	}
+	parsed, err := parseExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	e.template.expressions.Store(src, parsed)
+	return parsed, nil
+}
+
+// directive evaluates the expression of a $if or $repeat, with or without {{ }}
+func (e *expander) directive(src string, s *scope) (any, error) {
+	src = strings.TrimSpace(src)
+	if strings.HasPrefix(src, "{{") && strings.HasSuffix(src, "}}") {
+		src = src[2 : len(src)-2]
+	}
+	parsed, err := e.expression(src)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.eval(s)
+}
+
+// expand returns the expanded value, and false when a $if removed it
+func (e *expander) expand(value any, path string, s *scope) (any, bool, error) {
+	switch v := value.(type) {
+	case string:
+		expanded, err := e.interpolate(v, s)
+		if err != nil {
+			return nil, false, &Error{Path: path, Err: err}
+		}
+		return expanded, true, nil
+	case []any:
+		list := make([]any, 0, len(v))
+		for i, item := range v {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			object, ok := item.(map[string]any)
+			if !ok || object["$repeat"] == nil {
+				expanded, keep, err := e.expand(item, itemPath, s)
+				if err != nil {
+					return nil, false, err
+				}
+				if keep {
+					list = append(list, expanded)
+				}
+				continue
+			}
+			repeated, err := e.repeat(object, itemPath, s)
+			if err != nil {
+				return nil, false, err
+			}
+			list = append(list, repeated...)
+		}
+		return list, true, nil
+	case map[string]any:
+		if _, ok := v["$repeat"]; ok {
+			return nil, false, &Error{Path: path + ".$repeat", Err: fmt.Errorf("$repeat can only be used on list items")}
+		}
+		if _, ok := v["$as"]; ok {
+			return nil, false, &Error{Path: path + ".$as", Err: fmt.Errorf("$as can only be used with $repeat")}
+		}
+		if condition, ok := v["$if"]; ok {
+			src, isString := condition.(string)
+			if !isString {
+				return nil, false, &Error{Path: path + ".$if", Err: fmt.Errorf("expected an expression string, got %s", kind(condition))}
+			}
+			result, err := e.directive(src, s)
+			if err != nil {
+				return nil, false, &Error{Path: path + ".$if", Err: err}
+			}
+			if !truthy(result) {
+				return nil, false, nil
+			}
+		}
+
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			if key == "$if" {
+				continue
+			}
+			expanded, keep, err := e.expand(item, propertyPath(path, key), s)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				object[key] = expanded
+			}
+		}
+		return object, true, nil
+	default:
+		return value, true, nil
+	}
+}
+
+// repeat expands a list item once for each element of its $repeat list
+func (e *expander) repeat(object map[string]any, path string, s *scope) ([]any, error) {
+	src, ok := object["$repeat"].(string)
+	if !ok {
+		return nil, &Error{Path: path + ".$repeat", Err: fmt.Errorf("expected an expression string, got %s", kind(object["$repeat"]))}
+	}
+	name := "item"
+	if as, ok := object["$as"]; ok {
+		if name, ok = as.(string); !ok || name == "" {
+			return nil, &Error{Path: path + ".$as", Err: fmt.Errorf("expected a variable name")}
+		}
+	}
+
+	value, err := e.directive(src, s)
+	if err != nil {
+		return nil, &Error{Path: path + ".$repeat", Err: err}
+	}
+	var items []any
+	switch v := value.(type) {
+	case []any:
+		items = v
+	case nil:
+	case float64:
+		// A count repeats the item with the numbers from 0
+		if v < 0 || v > MaxRepeat || v != math.Trunc(v) {
+			return nil, &Error{Path: path + ".$repeat", Err: fmt.Errorf("expected a whole count from 0 to %d, got %v", MaxRepeat, v)}
+		}
+		for i := range int(v) {
+			items = append(items, float64(i))
+		}
+	case missing:
+		return nil, &Error{Path: path + ".$repeat", Err: fmt.Errorf("unknown variable %q", v.name)}
+	default:
+		return nil, &Error{Path: path + ".$repeat", Err: fmt.Errorf("expected a list, got %s", kind(value))}
+	}
+
+	template := make(map[string]any, len(object))
+	for key, item := range object {
+		if key != "$repeat" && key != "$as" {
+			template[key] = item
+		}
+	}
+	if e.repeated += len(items); e.repeated > MaxRepeat {
+		return nil, &Error{Path: path + ".$repeat", Err: fmt.Errorf("repeats more than %d items in total", MaxRepeat)}
+	}
+	var result []any
+	for i, item := range items {
+		loop := map[string]any{
+			"index":  float64(i),
+			"number": float64(i + 1),
+			"first":  i == 0,
+			"last":   i == len(items)-1,
+		}
+		inner := &scope{parent: s, values: map[string]any{name: item, "loop": loop}}
+		expanded, keep, err := e.expand(template, fmt.Sprintf("%s(%s=%d)", path, name, i), inner)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			result = append(result, expanded)
+		}
+	}
+	return result, nil
+}
+
+// interpolate replaces the {{ }} placeholders of a string
+func (e *expander) interpolate(text string, s *scope) (any, error) {
+	start := strings.Index(text, "{{")
+	if start < 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	for start >= 0 {
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed {{ in %q", text)
+		}
+		end += start
+		parsed, err := e.expression(text[start+2 : end])
+		if err != nil {
+			return nil, fmt.Errorf("in %q: %w", text[start:end+2], err)
+		}
+		value, err := parsed.eval(s)
+		if err != nil {
+			return nil, fmt.Errorf("in %q: %w", text[start:end+2], err)
+		}
+		if m, ok := value.(missing); ok {
+			return nil, fmt.Errorf("unknown variable %q", m.name)
+		}
+
+		// A lone placeholder keeps the type of its value
+		escape := e.template.Escape
+		if start == 0 && end+2 == len(text) && escape == nil {
+			return value, nil
+		}
+		b.WriteString(text[:start])
+		if escape != nil {
+			b.WriteString(escape(stringify(value)))
+		} else {
+			b.WriteString(stringify(value))
+		}
+		text = text[end+2:]
+		start = strings.Index(text, "{{")
+	}
+	b.WriteString(text)
+	return b.String(), nil
+}
+
+// stringify formats a value for text
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+func propertyPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+	return fmt.Sprintf("%s[%q]", path, key)
+}
@@ -0,0 +1,183 @@
+package scene
+
+import (
+	"html"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hvuhsg/render/render_objects"
+)
+
+const userCard = `{
+	"type": "Column",
+	"children": [
+		{"type": "Text", "text": "{{user.name | upper}}"},
+		{"type": "Text", "text": "{{user.followers | number}} followers since {{user.joined | date:\"Jan 2006\"}}"},
+		{"type": "Text", "text": "Pro member", "$if": "user.plan == \"pro\""},
+		{"type": "ColoredBox", "width": "{{bar}}", "height": 4, "$repeat": "user.scores", "$as": "bar"},
+		{"type": "Text", "text": "{{loop.number}}. {{tag}}", "$repeat": "user.tags", "$as": "tag", "$if": "false"}
+	]
+}`
+
+func TestTemplateExpand(t *testing.T) {
+	tmpl, err := ParseTemplateJSON([]byte(userCard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]any{"user": map[string]any{
+		"name":      "Ada",
+		"followers": 12345.0,
+		"joined":    "2021-03-04",
+		"plan":      "pro",
+		"scores":    []any{10.0, 20.0},
+		"tags":      []any{"go", "math"},
+	}}
+
+	value, err := tmpl.Expand(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := value.(map[string]any)["children"].([]any)
+	want := []any{
+		map[string]any{"type": "Text", "text": "ADA"},
+		map[string]any{"type": "Text", "text": "12,345 followers since Mar 2021"},
+		map[string]any{"type": "Text", "text": "Pro member"},
+		map[string]any{"type": "ColoredBox", "width": 10.0, "height": 4.0},
+		map[string]any{"type": "ColoredBox", "width": 20.0, "height": 4.0},
+	}
+	if !reflect.DeepEqual(children, want) {
+		t.Errorf("Expected %v, got %v", want, children)
+	}
+
+	// The same template builds a tree per record
+	root, err := tmpl.Build(map[string]any{"user": map[string]any{
+		"name": "Bob", "followers": 3.0, "joined": "2020-01-01T10:00:00Z", "plan": "free", "scores": []any{}, "tags": []any{},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(root.(*render_objects.Column).Children); n != 2 {
+		t.Errorf("Expected the free plan card to have 2 children, got %d", n)
+	}
+}
+
+func TestTemplateLoop(t *testing.T) {
+	tmpl := NewTemplate(map[string]any{"type": "Row", "children": []any{
+		map[string]any{"type": "Text", "text": "{{loop.number}}/{{items | length}} {{item.label}}", "$repeat": "items", "$if": "not item.hidden"},
+	}})
+	value, err := tmpl.Expand(map[string]any{"items": []map[string]any{{"label": "a"}, {"label": "b", "hidden": true}, {"label": "c"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, child := range value.(map[string]any)["children"].([]any) {
+		texts = append(texts, child.(map[string]any)["text"].(string))
+	}
+	if strings.Join(texts, ",") != "1/3 a,3/3 c" {
+		t.Errorf("Expected 1/3 a,3/3 c, got %v", texts)
+	}
+}
+
+func TestTemplateExpressions(t *testing.T) {
+	data := map[string]any{"n": 1234.5, "s": "hello world", "list": []any{"a", "b"}, "empty": "", "zero": 0.0, "date": 0.0}
+	tests := map[string]string{
+		"{{n | number:1}}":                    "1,234.5",
+		"{{n | compact}}":                     "1.2K",
+		"{{999950 | compact}}":                "1M",
+		"{{999999999 | compact}}":             "1B",
+		"{{-999950 | compact}}":               "-1M",
+		"{{999 | compact}}":                   "999",
+		"{{0.256 | percent:1}}":               "25.6%",
+		"{{-1234.5 | currency:\"€\"}}":        "-€1,234.50",
+		"{{s | title}}":                       "Hello World",
+		"{{s | truncate:8}}":                  "hello w…",
+		"{{missing | default:\"none\"}}":      "none",
+		"{{empty | default:\"none\"}}":        "none",
+		"{{zero | default:5}}":                "0",
+		"{{list | join:\" · \"}}":             "a · b",
+		"{{date | date:\"iso\"}}":             "1970-01-01",
+		"{{list[1] | upper}}":                 "B",
+		"{{n > 1000 and s != \"x\"}}":         "true",
+		"{{(zero or empty) | default:\"-\"}}": "-",
+	}
+	for src, want := range tests {
+		value, err := NewTemplate(src).Expand(data)
+		if err != nil {
+			t.Errorf("Expected %s to expand, got %v", src, err)
+			continue
+		}
+		if got := stringify(value); got != want {
+			t.Errorf("Expected %s to give %q, got %q", src, want, got)
+		}
+	}
+}
+
+func TestTemplateEscape(t *testing.T) {
+	template := NewTemplate(`<p title="{{name}}">{{name}} has {{count}}</p>`)
+	template.Escape = html.EscapeString
+	value, err := template.Expand(map[string]any{"name": `Tom & "Jerry" <3`, "count": 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `<p title="Tom &amp; &#34;Jerry&#34; &lt;3">Tom &amp; &#34;Jerry&#34; &lt;3 has 2</p>`; value != expected {
+		t.Errorf("Expected %s, got %v", expected, value)
+	}
+
+	// A lone placeholder gives an escaped string rather than its value
+	template = NewTemplate("{{count}}")
+	template.Escape = html.EscapeString
+	if value, err := template.Expand(map[string]any{"count": 2.0}); err != nil || value != "2" {
+		t.Errorf("Expected the string 2, got %#v (%v)", value, err)
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template any
+		err      string
+	}{
+		{map[string]any{"type": "Text", "text": "Hi {{user.nam}}"}, `$.text: unknown variable "user.nam"`},
+		{map[string]any{"children": []any{map[string]any{"$repeat": "user"}}}, `$.children[0].$repeat: expected a list, got object`},
+		{map[string]any{"child": map[string]any{"$repeat": "list"}}, `$.child.$repeat: $repeat can only be used on list items`},
+		{map[string]any{"children": []any{map[string]any{"$repeat": "10000000000"}}}, `$.children[0].$repeat: expected a whole count from 0 to 10000, got 1e+10`},
+		{map[string]any{"children": []any{map[string]any{"$repeat": "2.5"}}}, `$.children[0].$repeat: expected a whole count from 0 to 10000, got 2.5`},
+		{map[string]any{"children": []any{map[string]any{"$repeat": "-1"}}}, `$.children[0].$repeat: expected a whole count from 0 to 10000, got -1`},
+		{map[string]any{"children": []any{map[string]any{"$repeat": "200", "children": []any{map[string]any{"$repeat": "100"}}}}}, `$.children[0](item=98).children[0].$repeat: repeats more than 10000 items in total`},
+		{map[string]any{"text": "{{user.name | shout}}"}, `$.text: in "{{user.name | shout}}": unknown formatter "shout"`},
+		{map[string]any{"text": "{{user.name | number}}"}, `$.text: in "{{user.name | number}}": number: "Ada" is not a number`},
+		{map[string]any{"text": "{{user.name"}, `$.text: unclosed {{`},
+	}
+	data := map[string]any{"user": map[string]any{"name": "Ada"}}
+	for _, test := range tests {
+		_, err := NewTemplate(test.template).Expand(data)
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("Expected error %q, got %v", test.err, err)
+		}
+	}
+}
+
+func TestTemplateConcurrent(t *testing.T) {
+	tmpl := NewTemplate(map[string]any{"type": "Text", "text": "{{name}} #{{id | number}}"})
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tmpl.Build(map[string]any{"name": "user", "id": float64(i)}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDecodeRecords(t *testing.T) {
+	for _, input := range []string{`[{"a": 1}, {"a": 2}]`, "{\"a\": 1}\n{\"a\": 2}\n"} {
+		records, err := DecodeRecords([]byte(input))
+		if err != nil || len(records) != 2 {
+			t.Errorf("Expected 2 records from %q, got %v, %v", input, records, err)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+package scene
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DecodeYAML decodes the subset of YAML used for scenes into the same generic values as
+// DecodeJSON: block mappings and sequences, flow [lists] and {maps} on a single line, plain,
+// quoted and block (| and >) scalars, and comments. Anchors, aliases, tags and multiple
+// documents aren't supported.
+func DecodeYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		p.lines = append(p.lines, yamlLine{number: i + 1, raw: raw})
+	}
+	if err := p.prepare(); err != nil {
+		return nil, err
+	}
+	if p.next() == nil {
+		return nil, nil
+	}
+	value, err := p.node(0)
+	if err != nil {
+		return nil, err
+	}
+	if line := p.next(); line != nil {
+		return nil, line.errorf("unexpected content")
+	}
+	return value, nil
+}
+
+type yamlLine struct {
+	number int
+	raw    string
+	indent int
+	text   string // Content without indentation and comments, empty for blank lines
+}
+
+func (l *yamlLine) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid YAML at line %d: %s", l.number, fmt.Sprintf(format, args...))
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// prepare measures indentation and strips comments and document markers
+func (p *yamlParser) prepare() error {
+	for i := range p.lines {
+		line := &p.lines[i]
+		content := strings.TrimLeft(line.raw, " ")
+		line.indent = len(line.raw) - len(content)
+		if strings.HasPrefix(content, "\t") {
+			return line.errorf("tabs can't be used for indentation")
+		}
+		line.text = strings.TrimSpace(stripComment(content))
+		if line.indent == 0 && (line.text == "---" || line.text == "...") {
+			line.text = ""
+		}
+	}
+	return nil
+}
+
+// stripComment removes a # comment that starts the text or follows whitespace, outside quotes
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" \t[{,:-", rune(text[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// next skips blank lines and returns the current line, nil at the end
+func (p *yamlParser) next() *yamlLine {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+	if p.pos == len(p.lines) {
+		return nil
+	}
+	return &p.lines[p.pos]
+}
+
+// node parses the value starting at the current line, which is indented by at least indent
+func (p *yamlParser) node(indent int) (any, error) {
+	line := p.next()
+	if line == nil || line.indent < indent {
+		return nil, nil
+	}
+	if isItem(line.text) {
+		return p.sequence(line.indent)
+	}
+	if _, _, ok := splitKey(line.text); ok {
+		return p.mapping(line.indent)
+	}
+	p.pos++
+	return scalar(line.text, line)
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	list := []any{}
+	for line := p.next(); line != nil && line.indent == indent && isItem(line.text); line = p.next() {
+		item := strings.TrimLeft(line.text[1:], " ")
+		if item == "" {
+			p.pos++
+			value, err := p.node(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			continue
+		}
+
+		// Parse the item as if it started its own line, so "- key: value" begins a mapping
+		line.indent += len(line.text) - len(item)
+		line.text = item
+		value, err := p.node(line.indent)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	if line := p.next(); line != nil && line.indent > indent {
+		return nil, line.errorf("unexpected indentation")
+	}
+	return list, nil
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	object := map[string]any{}
+	for line := p.next(); line != nil && line.indent == indent; line = p.next() {
+		key, rest, ok := splitKey(line.text)
+		if !ok {
+			return nil, line.errorf("expected \"key: value\"")
+		}
+		if _, duplicate := object[key]; duplicate {
+			return nil, line.errorf("duplicate key %q", key)
+		}
+		p.pos++
+
+		var value any
+		var err error
+		switch {
+		case rest == "":
+			next := p.next()
+			switch {
+			case next != nil && next.indent > indent:
+				value, err = p.node(next.indent)
+			case next != nil && next.indent == indent && isItem(next.text):
+				// Lists may start at the same indentation as their key
+				value, err = p.sequence(indent)
+			}
+		case rest[0] == '|' || rest[0] == '>':
+			value, err = p.blockScalar(indent, rest, line)
+		default:
+			value, err = scalar(rest, line)
+		}
+		if err != nil {
+			return nil, err
+		}
+		object[key] = value
+	}
+	if line := p.next(); line != nil && line.indent > indent {
+		return nil, line.errorf("unexpected indentation")
+	}
+	return object, nil
+}
+
+// blockScalar reads a literal (|) or folded (>) string from the lines indented below its key
+func (p *yamlParser) blockScalar(indent int, header string, line *yamlLine) (any, error) {
+	chomp := header[1:]
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, line.errorf("unsupported block scalar header %q", header)
+	}
+
+	var lines []string
+	blockIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos].raw
+		content := strings.TrimLeft(raw, " ")
+		if content == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lineIndent := len(raw) - len(content)
+		if lineIndent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = lineIndent
+		}
+		if lineIndent < blockIndent {
+			return nil, p.lines[p.pos].errorf("block text is indented less than its first line")
+		}
+		lines = append(lines, raw[blockIndent:])
+	}
+
+	// Blank lines at the end belong to the chomping, not the text
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	// Let the trailing blank lines be read as blank lines again
+	p.pos -= trailing
+
+	var text string
+	if header[0] == '|' {
+		text = strings.Join(lines, "\n")
+	} else {
+		var b strings.Builder
+		for i, l := range lines {
+			switch {
+			case i == 0:
+			case l == "" || lines[i-1] == "":
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		text = b.String()
+	}
+	switch chomp {
+	case "":
+		text += "\n"
+	case "+":
+		text += strings.Repeat("\n", trailing+1)
+	}
+	return text, nil
+}
+
+// isItem reports whether a line starts a list item
+func isItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" outside quotes and brackets
+func splitKey(text string) (key, rest string, ok bool) {
+	if text == "" || strings.ContainsRune("[{", rune(text[0])) {
+		return "", "", false
+	}
+	end := 0
+	if text[0] == '"' || text[0] == '\'' {
+		// Quoted key
+		value, n, err := quoted(text)
+		if err != nil || n >= len(text) || text[n] != ':' {
+			return "", "", false
+		}
+		if n+1 < len(text) && text[n+1] != ' ' {
+			return "", "", false
+		}
+		return value, strings.TrimSpace(text[n+1:]), true
+	}
+	for end = 0; end < len(text); end++ {
+		if text[end] == ':' && (end+1 == len(text) || text[end+1] == ' ') {
+			return strings.TrimSpace(text[:end]), strings.TrimSpace(text[end+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// scalar parses an inline value: a quoted string, a flow collection or a plain scalar
+func scalar(text string, line *yamlLine) (any, error) {
+	switch text[0] {
+	case '"', '\'':
+		value, n, err := quoted(text)
+		if err != nil {
+			return nil, line.errorf("%v", err)
+		}
+		if strings.TrimSpace(text[n:]) != "" {
+			return nil, line.errorf("unexpected text after quoted string")
+		}
+		return value, nil
+	case '[', '{':
+		f := &flow{text: text, line: line}
+		value, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		if f.skipSpace(); f.pos < len(f.text) {
+			return nil, line.errorf("unexpected text after %c", text[0])
+		}
+		return value, nil
+	case '&', '*', '!':
+		return nil, line.errorf("anchors, aliases and tags are not supported")
+	}
+	return plain(text), nil
+}
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// plain converts an unquoted scalar to null, a boolean, a number or a string
+func plain(text string) any {
+	switch text {
+	case "null", "Null", "NULL", "~":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if yamlNumber.MatchString(text) {
+		if n, err := strconv.ParseFloat(text, 64); err == nil {
+			return n
+		}
+	}
+	return text
+}
+
+// quoted reads a quoted string at the start of text, returning its value and length
+func quoted(text string) (string, int, error) {
+	if text[0] == '\'' {
+		var b strings.Builder
+		for i := 1; i < len(text); i++ {
+			if text[i] == '\'' {
+				if i+1 < len(text) && text[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				return b.String(), i + 1, nil
+			}
+			b.WriteByte(text[i])
+		}
+		return "", 0, fmt.Errorf("unterminated string")
+	}
+
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(text[:i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid escape in %s", text[:i+1])
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// flow parses single line flow collections such as [1, 2] and {a: 1}
+type flow struct {
+	text string
+	pos  int
+	line *yamlLine
+}
+
+func (f *flow) skipSpace() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *flow) value() (any, error) {
+	f.skipSpace()
+	if f.pos == len(f.text) {
+		return nil, f.line.errorf("unexpected end of line")
+	}
+	switch f.text[f.pos] {
+	case '[':
+		f.pos++
+		list := []any{}
+		for {
+			if f.skipSpace(); f.pos < len(f.text) && f.text[f.pos] == ']' {
+				f.pos++
+				return list, nil
+			}
+			item, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		object := map[string]any{}
+		for {
+			if f.skipSpace(); f.pos < len(f.text) && f.text[f.pos] == '}' {
+				f.pos++
+				return object, nil
+			}
+			key, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			if f.skipSpace(); f.pos >= len(f.text) || f.text[f.pos] != ':' {
+				return nil, f.line.errorf("expected ':' after key in {...}")
+			}
+			f.pos++
+			value, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(key)] = value
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '"', '\'':
+		value, n, err := quoted(f.text[f.pos:])
+		if err != nil {
+			return nil, f.line.errorf("%v", err)
+		}
+		f.pos += n
+		return value, nil
+	default:
+		start := f.pos
+		for f.pos < len(f.text) && !strings.ContainsRune(",]}", rune(f.text[f.pos])) &&
+			!(f.text[f.pos] == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ')) {
+			f.pos++
+		}
+		return plain(strings.TrimSpace(f.text[start:f.pos])), nil
+	}
+}
+
+// separator consumes the comma between items, leaving the closing bracket
+func (f *flow) separator(closing byte) error {
+	f.skipSpace()
+	switch {
+	case f.pos < len(f.text) && f.text[f.pos] == ',':
+		f.pos++
+		return nil
+	case f.pos < len(f.text) && f.text[f.pos] == closing:
+		return nil
+	default:
+		return f.line.errorf("expected ',' or '%c'", closing)
+	}
+}
@@ -0,0 +1,97 @@
+package scene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	source := `
+# A card
+type: Column
+mainAxisAlignment: spaceBetween   # comment after a value
+children:
+  - type: Text
+    text: "Hello: \"world\""
+    fontSize: 24
+  - type: Text
+    text: it's plain
+    wrap: true
+  -
+    type: ColoredBox
+    color: '#336699'
+    size: [40, 10.5]
+    extra: {a: 1, b: [x, "y z"], c: null}
+tags:
+- one
+- 2
+note: |
+  first line
+    indented
+  last
+folded: >-
+  joined
+  together
+`
+	value, err := DecodeYAML([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"type":              "Column",
+		"mainAxisAlignment": "spaceBetween",
+		"children": []any{
+			map[string]any{"type": "Text", "text": `Hello: "world"`, "fontSize": 24.0},
+			map[string]any{"type": "Text", "text": "it's plain", "wrap": true},
+			map[string]any{
+				"type":  "ColoredBox",
+				"color": "#336699",
+				"size":  []any{40.0, 10.5},
+				"extra": map[string]any{"a": 1.0, "b": []any{"x", "y z"}, "c": nil},
+			},
+		},
+		"tags":   []any{"one", 2.0},
+		"note":   "first line\n  indented\nlast\n",
+		"folded": "joined together",
+	}
+	if !reflect.DeepEqual(value, want) {
+		t.Errorf("Expected %#v, got %#v", want, value)
+	}
+}
+
+func TestDecodeYAMLErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{"a: 1\n  b: 2\n", "line 2: unexpected indentation"},
+		{"a: 1\na: 2\n", `line 2: duplicate key "a"`},
+		{"a: [1, 2\n", "line 1: expected ',' or ']'"},
+		{"a: \"open\n", "line 1: unterminated string"},
+		{"a: *ref\n", "line 1: anchors, aliases and tags are not supported"},
+		{"\ta: 1\n", "line 1: tabs can't be used for indentation"},
+	}
+	for _, test := range tests {
+		_, err := DecodeYAML([]byte(test.source))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Expected %q for %q, got %v", test.err, test.source, err)
+		}
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	_, err := ParseYAML([]byte("type: ColoredBox\nwidth: 10\nheight: 10\ncolor: #ff0000\n"))
+	if err == nil || !strings.Contains(err.Error(), "$.color: expected a color string, got null") {
+		t.Errorf("Expected a hint about unquoted colors, got %v", err)
+	}
+
+	root, err := ParseYAML([]byte("type: Padding\npadding: {horizontal: 4}\nchild:\n  type: Text\n  text: Hi\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root == nil {
+		t.Error("Expected a render object")
+	}
+}
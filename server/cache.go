@@ -0,0 +1,69 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+)
+
+// result is an encoded image kept in the cache
+type result struct {
+	key         string
+	contentType string
+	data        []byte
+}
+
+// cache keeps the most recently used results up to a total size in bytes
+type cache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List // Most recently used at the front
+	entries  map[string]*list.Element
+}
+
+func newCache(maxBytes int64) *cache {
+	return &cache{maxBytes: maxBytes, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *cache) get(key string) (*result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*result), true
+}
+
+// add stores a result, evicting the least recently used ones to make room.
+// Results larger than the whole cache aren't stored.
+func (c *cache) add(r *result) {
+	size := int64(len(r.data))
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[r.key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[r.key] = c.order.PushFront(r)
+	c.size += size
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		evicted := c.order.Remove(oldest).(*result)
+		delete(c.entries, evicted.key)
+		c.size -= int64(len(evicted.data))
+	}
+}
+
+// len returns the number of cached results
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
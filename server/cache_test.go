@@ -0,0 +1,36 @@
+package server
+
+import "testing"
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(10)
+	c.add(&result{key: "a", data: make([]byte, 4)})
+	c.add(&result{key: "b", data: make([]byte, 4)})
+
+	// Using a makes b the least recently used
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	c.add(&result{key: "c", data: make([]byte, 4)})
+
+	if _, ok := c.get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("Expected a to stay cached")
+	}
+	if c.size != 8 || c.len() != 2 {
+		t.Errorf("Expected 2 results of 8 bytes, got %d of %d bytes", c.len(), c.size)
+	}
+
+	c.add(&result{key: "huge", data: make([]byte, 11)})
+	if _, ok := c.get("huge"); ok {
+		t.Error("Expected results larger than the cache to be skipped")
+	}
+
+	disabled := newCache(0)
+	disabled.add(&result{key: "a", data: []byte{1}})
+	if disabled.len() != 0 {
+		t.Error("Expected an empty cache to store nothing")
+	}
+}
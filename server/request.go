@@ -0,0 +1,175 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/markup"
+	"github.com/hvuhsg/render/pdf"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/scene"
+	"github.com/hvuhsg/render/svg"
+	"github.com/hvuhsg/render/types"
+)
+
+// Request is the JSON body of a render request.
+// Exactly one of Tree, a scene that may contain {{placeholders}}, and HTML is set.
+type Request struct {
+	Tree       any     `json:"tree,omitempty"`       // Scene as described by the scene package
+	HTML       string  `json:"html,omitempty"`       // Markup as described by the markup package
+	Data       any     `json:"data,omitempty"`       // Values for the placeholders
+	Width      int     `json:"width,omitempty"`      // Logical width, the server's default size when zero
+	Height     int     `json:"height,omitempty"`     // Logical height, the server's default size when zero
+	Format     string  `json:"format,omitempty"`     // png, jpeg, gif, bmp, svg or pdf
+	PixelRatio float64 `json:"pixelRatio,omitempty"` // Device pixels per logical pixel, 1 by default
+	Quality    int     `json:"quality,omitempty"`    // JPEG quality from 1 to 100
+}
+
+// Content types of the output formats
+var contentTypes = map[string]string{
+	"png":  "image/png",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+	"svg":  "image/svg+xml",
+	"pdf":  "application/pdf",
+}
+
+// decodeRequest reads a request body, rejecting unknown fields
+func decodeRequest(body io.Reader) (*Request, error) {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	var req Request
+	if err := decoder.Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("invalid request body: unexpected data after the JSON object")
+	}
+	return &req, nil
+}
+
+// normalize fills in defaults and checks the request against the server's limits
+func (req *Request) normalize(options *Options, accept string) error {
+	if (req.Tree == nil) == (req.HTML == "") {
+		return errors.New(`expected either "tree" or "html"`)
+	}
+
+	if req.Width == 0 && req.Height == 0 {
+		req.Width, req.Height = options.DefaultSize.Width, options.DefaultSize.Height
+	}
+	if req.Width <= 0 || req.Height <= 0 {
+		return fmt.Errorf("invalid size %dx%d", req.Width, req.Height)
+	}
+	if req.PixelRatio == 0 {
+		req.PixelRatio = 1
+	}
+	if req.PixelRatio < 0 {
+		return fmt.Errorf("invalid pixel ratio %g", req.PixelRatio)
+	}
+	pixels := float64(req.Width) * float64(req.Height) * req.PixelRatio * req.PixelRatio
+	if pixels > float64(options.MaxPixels) {
+		return fmt.Errorf("%dx%d at pixel ratio %g is larger than the limit of %d pixels", req.Width, req.Height, req.PixelRatio, options.MaxPixels)
+	}
+	if req.Quality < 0 || req.Quality > 100 {
+		return fmt.Errorf("invalid quality %d, expected 1 to 100", req.Quality)
+	}
+
+	if req.Format == "" {
+		req.Format = negotiateFormat(accept)
+	}
+	req.Format = strings.ToLower(req.Format)
+	if req.Format == "jpg" {
+		req.Format = "jpeg"
+	}
+	if _, ok := contentTypes[req.Format]; !ok {
+		return fmt.Errorf("unknown format %q, expected png, jpeg, gif, bmp, svg or pdf", req.Format)
+	}
+	return nil
+}
+
+// negotiateFormat picks the first supported image type of an Accept header, PNG by default
+func negotiateFormat(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		for format, contentType := range contentTypes {
+			if mediaType == contentType {
+				return format
+			}
+		}
+	}
+	return "png"
+}
+
+// key hashes everything the result depends on, serving as cache key and ETag
+func (req *Request) key() string {
+	// Maps are marshaled with sorted keys, so equal requests hash the same
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// build creates the render tree of the request
+func (req *Request) build(registry *scene.Registry) (render_objects.RenderObject, error) {
+	if req.HTML != "" {
+		source := req.HTML
+		if req.Data != nil {
+			// Data is escaped so it stays text instead of adding elements
+			template := scene.NewTemplate(req.HTML)
+			template.Escape = html.EscapeString
+			expanded, err := template.Expand(req.Data)
+			if err != nil {
+				return nil, err
+			}
+			source = expanded.(string)
+		}
+		// Image sources are limited to data URIs, the server's files are off limits
+		return markup.Parse(source, &markup.Options{LoadImage: markup.DecodeDataURI})
+	}
+
+	template := scene.NewTemplate(req.Tree)
+	template.Registry = registry
+	return template.Build(req.Data)
+}
+
+// encode paints a tree and encodes it in the requested format. Offscreen canvases are
+// limited to maxPixels, painting a tree that needs larger ones panics with a *cv.LimitError,
+// and painting past the deadline panics with a *cv.DeadlineError.
+func (req *Request) encode(root render_objects.RenderObject, maxPixels int, deadline time.Time) ([]byte, error) {
+	size := types.Size{Width: req.Width, Height: req.Height}
+	var buf bytes.Buffer
+	var err error
+	switch req.Format {
+	case "svg":
+		doc := svg.New(size)
+		doc.PixelLimit, doc.Deadline = maxPixels, deadline
+		root.Paint(doc.Canvas())
+		_, err = doc.WriteTo(&buf)
+	case "pdf":
+		doc := pdf.New(size, types.EdgeInsets{})
+		doc.PixelLimit, doc.Deadline = maxPixels, deadline
+		root.Paint(doc.AddPage())
+		_, err = doc.WriteTo(&buf)
+	default:
+		format, _ := cv.ParseFormat(req.Format)
+		canvas := cv.NewScaledCanvas(size, req.PixelRatio, false)
+		canvas.SetPixelLimit(maxPixels)
+		canvas.SetDeadline(deadline)
+		root.Paint(canvas)
+		err = canvas.Encode(&buf, format, &cv.EncodeOptions{Quality: req.Quality})
+	}
+	return buf.Bytes(), err
+}
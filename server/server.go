@@ -0,0 +1,212 @@
+// Package server exposes rendering as an HTTP image API.
+//
+// Clients POST a JSON Request to /render holding a scene tree or markup, optional data for
+// its {{placeholders}}, a size and a format, and receive the encoded image:
+//
+//	POST /render
+//	{"tree": {"type": "Text", "text": "Hello {{name}}"}, "data": {"name": "Ada"}, "width": 1200, "height": 630}
+//
+// The format is taken from the request, or else from the Accept header, and defaults to PNG.
+// Results are identified by a hash of the request, sent as ETag, and kept in an LRU cache.
+// Errors are JSON objects with an "error" message. GET /healthz reports the server is up.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/scene"
+	"github.com/hvuhsg/render/types"
+)
+
+// Options configure a Server. Zero values take the defaults.
+type Options struct {
+	Registry      *scene.Registry // Types available to trees, scene.Default by default
+	DefaultSize   types.Size      // Size of requests without one, 1200x630 by default
+	MaxBodyBytes  int64           // Largest request body, 1 MiB by default
+	MaxPixels     int             // Largest image, and largest offscreen layer, in device pixels, 4096x4096 by default
+	Timeout       time.Duration   // Longest a request may wait for and spend on rendering, 10s by default
+	MaxConcurrent int             // Renders running at once, the number of CPUs by default
+	CacheBytes    int64           // Size of the result cache, 64 MiB by default; negative disables it
+	ErrorLog      *log.Logger     // Logs panics during rendering, the standard logger by default
+}
+
+var (
+	errBusy    = errors.New("too many renders in progress, try again later")
+	errTimeout = errors.New("rendering took too long")
+)
+
+// Server renders requests into images. It is an http.Handler.
+type Server struct {
+	options Options
+	slots   chan struct{} // Holds a token for every render in progress
+	cache   *cache
+	mux     *http.ServeMux
+}
+
+// New creates a server. A nil options uses the defaults.
+func New(options *Options) *Server {
+	s := &Server{}
+	if options != nil {
+		s.options = *options
+	}
+	o := &s.options
+	if o.Registry == nil {
+		o.Registry = scene.Default
+	}
+	if o.DefaultSize == (types.Size{}) {
+		o.DefaultSize = types.Size{Width: 1200, Height: 630}
+	}
+	if o.MaxBodyBytes <= 0 {
+		o.MaxBodyBytes = 1 << 20
+	}
+	if o.MaxPixels <= 0 {
+		o.MaxPixels = 4096 * 4096
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxConcurrent <= 0 {
+		o.MaxConcurrent = runtime.NumCPU()
+	}
+	if o.CacheBytes == 0 {
+		o.CacheBytes = 64 << 20
+	}
+	if o.ErrorLog == nil {
+		o.ErrorLog = log.Default()
+	}
+
+	s.slots = make(chan struct{}, o.MaxConcurrent)
+	s.cache = newCache(max(o.CacheBytes, 0))
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /render", s.handleRender)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleRender(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRequest(http.MaxBytesReader(w, r.Body, s.options.MaxBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.normalize(&s.options, r.Header.Get("Accept")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Results depend on nothing but the request, so a matching ETag needs no rendering
+	key := req.key()
+	etag := `"` + key + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	cached, ok := s.cache.get(key)
+	w.Header().Set("X-Cache", "HIT")
+	if !ok {
+		w.Header().Set("X-Cache", "MISS")
+		result, status, err := s.render(r.Context(), req, key)
+		if err != nil {
+			if status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, status, err)
+			return
+		}
+		s.cache.add(result)
+		cached = result
+	}
+
+	w.Header().Set("Content-Type", cached.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(cached.data)))
+	w.Write(cached.data)
+}
+
+// render waits for a free slot and renders the request in it, giving up after the timeout.
+// A render that times out keeps its slot until it stops, so stuck renders can't pile up;
+// painting stops at the next render object past the deadline.
+func (s *Server) render(ctx context.Context, req *Request, key string) (*result, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.options.Timeout)
+	defer cancel()
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, http.StatusServiceUnavailable, errBusy
+	}
+
+	type outcome struct {
+		result *result
+		status int
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() { <-s.slots }()
+		defer func() {
+			if r := recover(); r != nil {
+				// Trees needing offscreen canvases over the limit are the client's mistake
+				if limit, ok := r.(*cv.LimitError); ok {
+					done <- outcome{nil, http.StatusBadRequest, limit}
+					return
+				}
+				if _, ok := r.(*cv.DeadlineError); ok {
+					done <- outcome{nil, http.StatusGatewayTimeout, errTimeout}
+					return
+				}
+				s.options.ErrorLog.Printf("server: panic while rendering %s: %v", key, r)
+				done <- outcome{nil, http.StatusInternalServerError, fmt.Errorf("rendering failed: %v", r)}
+			}
+		}()
+
+		root, err := req.build(s.options.Registry)
+		if err != nil {
+			done <- outcome{nil, http.StatusBadRequest, err}
+			return
+		}
+		deadline, _ := ctx.Deadline()
+		data, err := req.encode(root, s.options.MaxPixels, deadline)
+		if err != nil {
+			done <- outcome{nil, http.StatusInternalServerError, err}
+			return
+		}
+		done <- outcome{&result{key: key, contentType: contentTypes[req.Format], data: data}, http.StatusOK, nil}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.status, o.err
+	case <-ctx.Done():
+		return nil, http.StatusGatewayTimeout, errTimeout
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("ETag")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/scene"
+	"github.com/hvuhsg/render/types"
+)
+
+const tree = `{"tree": {"type": "Container", "decoration": {"color": "#ff0000"}, "child": {"type": "Text", "text": "Hi {{name}}"}}, "data": {"name": "Ada"}, "width": 60, "height": 20}`
+
+func post(handler http.Handler, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/render", strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorMessage(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct{ Error string }
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON error, got %q", rec.Body.String())
+	}
+	return body.Error
+}
+
+func TestRender(t *testing.T) {
+	s := New(nil)
+	rec := post(s, tree, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "image/png" || rec.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected an uncached PNG, got %v", rec.Header())
+	}
+
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 60 || bounds.Dy() != 20 {
+		t.Errorf("Expected a 60x20 image, got %v", bounds)
+	}
+
+	// The same request is served from the cache with the same ETag
+	again := post(s, tree, nil)
+	if again.Header().Get("X-Cache") != "HIT" || again.Header().Get("ETag") != rec.Header().Get("ETag") {
+		t.Errorf("Expected a cache hit with the same ETag, got %v", again.Header())
+	}
+
+	notModified := post(s, tree, map[string]string{"If-None-Match": rec.Header().Get("ETag")})
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Errorf("Expected 304 without a body, got %d", notModified.Code)
+	}
+}
+
+func TestRenderFormats(t *testing.T) {
+	s := New(nil)
+	tests := []struct {
+		body        string
+		accept      string
+		contentType string
+	}{
+		{`{"html": "<p>Hi</p>", "width": 40, "height": 20, "format": "jpg"}`, "", "image/jpeg"},
+		{`{"html": "<p>Hi</p>", "width": 40, "height": 20}`, "text/html, image/svg+xml", "image/svg+xml"},
+		{`{"html": "<p>Hi</p>", "width": 40, "height": 20, "format": "pdf"}`, "image/png", "application/pdf"},
+		{`{"html": "<p>Hi</p>", "width": 40, "height": 20}`, "*/*", "image/png"},
+	}
+	for _, test := range tests {
+		rec := post(s, test.body, map[string]string{"Accept": test.accept})
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != test.contentType {
+			t.Errorf("Expected %s for %s, got %d %s", test.contentType, test.body, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestRenderEscapesHTMLData(t *testing.T) {
+	// Unescaped, the name would be parsed as an unsupported element
+	rec := post(New(nil), `{"html": "<p>Hi {{name}}</p>", "data": {"name": "<table>"}, "width": 60, "height": 20}`, nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected data to be escaped into the markup, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	s := New(&Options{MaxBodyBytes: 200, MaxPixels: 10000})
+	tests := []struct {
+		body    string
+		status  int
+		message string
+	}{
+		{`{"tree": `, http.StatusBadRequest, "invalid request body"},
+		{`{"tree": {"type": "Text", "text": "a"}, "colour": 1}`, http.StatusBadRequest, `unknown field "colour"`},
+		{`{"width": 10, "height": 10}`, http.StatusBadRequest, `expected either "tree" or "html"`},
+		{`{"tree": {"type": "Text", "text": "a"}, "width": 200, "height": 200}`, http.StatusBadRequest, "larger than the limit of 10000 pixels"},
+		{`{"tree": {"type": "Text", "text": "a"}, "width": 10, "height": 10, "format": "tiff"}`, http.StatusBadRequest, `unknown format "tiff"`},
+		{`{"tree": {"type": "Text", "text": "a", "txt": "b"}, "width": 10, "height": 10}`, http.StatusBadRequest, `$.txt: unknown property "txt" for Text`},
+		{`{"html": "<table></table>", "width": 10, "height": 10}`, http.StatusBadRequest, "unsupported element <table>"},
+		{`{"html": "<img src=\"/etc/passwd\">", "width": 10, "height": 10}`, http.StatusBadRequest, "only base64 data URIs"},
+		{`{"html": "` + strings.Repeat("a", 300) + `"}`, http.StatusRequestEntityTooLarge, "larger than 200 bytes"},
+	}
+	for _, test := range tests {
+		rec := post(s, test.body, nil)
+		if rec.Code != test.status {
+			t.Errorf("Expected %d for %s, got %d", test.status, test.body, rec.Code)
+		}
+		if message := errorMessage(t, rec); !strings.Contains(message, test.message) {
+			t.Errorf("Expected %q in the error for %s, got %q", test.message, test.body, message)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/render", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET /render, got %d", rec.Code)
+	}
+}
+
+// sleeper takes a while to paint, or panics
+type sleeper struct {
+	delay time.Duration
+	panic bool
+}
+
+func (s *sleeper) Paint(canvas *cv.Canvas) {
+	if s.panic {
+		panic("broken")
+	}
+	time.Sleep(s.delay)
+}
+
+func (s *sleeper) Size(parentSize types.Size) types.Size {
+	return parentSize
+}
+
+func slowRegistry() *scene.Registry {
+	registry := scene.NewRegistry()
+	registry.Register("Sleep", func(n *scene.Node) (render_objects.RenderObject, error) {
+		return &sleeper{delay: time.Duration(n.Int("ms", 0)) * time.Millisecond, panic: n.Bool("panic", false)}, n.Err()
+	})
+	registry.Register("Sleeps", func(n *scene.Node) (render_objects.RenderObject, error) {
+		column := &render_objects.Column{}
+		for range n.Int("count", 0) {
+			column.Children = append(column.Children, &sleeper{delay: time.Duration(n.Int("ms", 0)) * time.Millisecond})
+		}
+		return column, n.Err()
+	})
+	return registry
+}
+
+func TestRenderLimits(t *testing.T) {
+	s := New(&Options{Registry: slowRegistry(), Timeout: 50 * time.Millisecond, MaxConcurrent: 1})
+
+	rec := post(s, `{"tree": {"type": "Sleep", "ms": 200}, "width": 10, "height": 10}`, nil)
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected a timeout, got %d", rec.Code)
+	}
+
+	// The timed out render still holds the only slot
+	rec = post(s, `{"tree": {"type": "Sleep", "ms": 1}, "width": 10, "height": 10}`, nil)
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected the server to be busy, got %d", rec.Code)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	rec = post(s, `{"tree": {"type": "Sleep", "ms": 1}, "width": 10, "height": 10}`, nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the slot to be free again, got %d", rec.Code)
+	}
+}
+
+func TestRenderStopsAtTimeout(t *testing.T) {
+	var logs bytes.Buffer
+	s := New(&Options{Registry: slowRegistry(), Timeout: 50 * time.Millisecond, MaxConcurrent: 1, ErrorLog: log.New(&logs, "", 0)})
+
+	// A tree of a hundred slow objects stops painting at the timeout instead of after a second
+	for _, format := range []string{"png", "svg", "pdf"} {
+		rec := post(s, `{"tree": {"type": "Sleeps", "count": 100, "ms": 10}, "format": "`+format+`", "width": 10, "height": 10}`, nil)
+		if rec.Code != http.StatusGatewayTimeout {
+			t.Errorf("Expected a timeout as %s, got %d", format, rec.Code)
+		}
+		time.Sleep(100 * time.Millisecond)
+		rec = post(s, `{"tree": {"type": "Sleep", "ms": 1}, "format": "`+format+`", "width": 10, "height": 10}`, nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected the stopped %s render to free its slot, got %d", format, rec.Code)
+		}
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected stopped renders not to be logged as panics, got %q", logs.String())
+	}
+}
+
+func TestRenderOffscreenLimit(t *testing.T) {
+	var logs bytes.Buffer
+	s := New(&Options{ErrorLog: log.New(&logs, "", 0)})
+
+	// A small image whose fitted child would need a 60000x60000 offscreen canvas
+	body := `{"tree":{"type":"FittedBox","child":{"type":"ColoredBox","width":60000,"height":60000,"color":"red"}},"width":100,"height":100}`
+	rec := post(s, body, nil)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(errorMessage(t, rec), "larger than the limit") {
+		t.Errorf("Expected the tree to be rejected, got %d %s", rec.Code, rec.Body.String())
+	}
+	for _, format := range []string{"svg", "pdf"} {
+		rec := post(s, strings.Replace(body, `"width":100`, `"format":"`+format+`","width":100`, 1), nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected the tree to be rejected as %s, got %d %s", format, rec.Code, rec.Body.String())
+		}
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected rejected trees not to be logged as panics, got %q", logs.String())
+	}
+}
+
+func TestRenderPanic(t *testing.T) {
+	var logs bytes.Buffer
+	s := New(&Options{Registry: slowRegistry(), ErrorLog: log.New(&logs, "", 0)})
+
+	rec := post(s, `{"tree": {"type": "Sleep", "panic": true}, "width": 10, "height": 10}`, nil)
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(errorMessage(t, rec), "broken") {
+		t.Errorf("Expected the panic as a server error, got %d %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(logs.String(), "broken") {
+		t.Errorf("Expected the panic to be logged, got %q", logs.String())
+	}
+
+	// The server keeps working
+	if rec := post(s, tree, nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected later requests to succeed, got %d", rec.Code)
+	}
+}
+
+func TestRenderConcurrent(t *testing.T) {
+	s := New(&Options{MaxConcurrent: 2})
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := strings.Replace(tree, "Ada", strings.Repeat("a", i+1), 1)
+			if rec := post(s, body, nil); rec.Code != http.StatusOK {
+				t.Errorf("Expected 200, got %d", rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+	if s.cache.len() != 8 {
+		t.Errorf("Expected 8 cached results, got %d", s.cache.len())
+	}
+}
+
+func TestHealth(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,365 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/freetype/truetype"
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/types"
+)
+
+// Document collects the drawing operations of a vector canvas as SVG elements.
+// Shapes, text, gradients and transforms stay vectors; effects that only exist
+// as pixels (blurs, shadows, image shaders) are embedded as PNG images. Drawing
+// that overflows the canvas it was made on is clipped to it, like on a raster canvas.
+type Document struct {
+	PixelLimit int       // Largest embedded or offscreen image in pixels, unlimited when 0, see canvas.Canvas.SetPixelLimit
+	Deadline   time.Time // Time painting must be done by, none when zero, see canvas.Canvas.SetDeadline
+
+	size   types.Size
+	defs   strings.Builder
+	body   strings.Builder
+	depth  int
+	nextID int
+	clip   image.Rectangle   // Bounds of the open clip group, empty when there is none
+	clips  []image.Rectangle // Clips of the groups enclosing the current one
+}
+
+// New creates an empty document of the given size
+func New(size types.Size) *Document {
+	return &Document{size: size, depth: 1}
+}
+
+// Canvas returns a canvas that draws into the document
+func (d *Document) Canvas() *cv.Canvas {
+	c := cv.NewVectorCanvas(d.size, d)
+	c.SetPixelLimit(d.PixelLimit)
+	c.SetDeadline(d.Deadline)
+	return c
+}
+
+// WriteTo writes the document as a standalone SVG file
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, d.String())
+	return int64(n), err
+}
+
+func (d *Document) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		d.size.Width, d.size.Height, d.size.Width, d.size.Height)
+	if d.defs.Len() > 0 {
+		sb.WriteString("  <defs>\n")
+		sb.WriteString(d.defs.String())
+		sb.WriteString("  </defs>\n")
+	}
+	sb.WriteString(d.body.String())
+	// Close groups that were left open
+	for range d.depth - 1 {
+		sb.WriteString("</g>\n")
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// element writes one line of markup at the current group depth
+func (d *Document) element(format string, args ...any) {
+	d.body.WriteString(strings.Repeat("  ", d.depth))
+	fmt.Fprintf(&d.body, format, args...)
+	d.body.WriteString("\n")
+}
+
+func (d *Document) id(prefix string) string {
+	d.nextID++
+	return prefix + strconv.Itoa(d.nextID)
+}
+
+func (d *Document) Rectangle(x, y, w, h int, color color.RGBA, fill bool) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if fill {
+		d.element(`<rect x="%d" y="%d" width="%d" height="%d" %s/>`, x, y, w, h, paint("fill", color))
+		return
+	}
+	// Outlines are one pixel wide, centered on the outermost pixels
+	d.element(`<rect x="%s" y="%s" width="%d" height="%d" fill="none" %s/>`,
+		num(float64(x)+0.5), num(float64(y)+0.5), w-1, h-1, paint("stroke", color))
+}
+
+func (d *Document) Circle(x, y, r int, color color.RGBA, fill bool) {
+	if fill {
+		d.element(`<circle cx="%s" cy="%s" r="%s" %s/>`, num(float64(x)+0.5), num(float64(y)+0.5), num(float64(r)+0.5), paint("fill", color))
+		return
+	}
+	d.element(`<circle cx="%s" cy="%s" r="%d" fill="none" %s/>`, num(float64(x)+0.5), num(float64(y)+0.5), r, paint("stroke", color))
+}
+
+func (d *Document) Line(x1, y1, x2, y2 int, color color.RGBA, width int) {
+	d.element(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke-width="%d" stroke-linecap="round" %s/>`,
+		num(float64(x1)+0.5), num(float64(y1)+0.5), num(float64(x2)+0.5), num(float64(y2)+0.5), max(width, 1), paint("stroke", color))
+}
+
+func (d *Document) Polygon(points [][2]int, color color.RGBA, fill bool) {
+	// Outlines run through pixel centers, fills cover whole pixels
+	shift := 0.5
+	style := paint("fill", color)
+	if !fill {
+		shift = 0
+		style = `fill="none" ` + paint("stroke", color)
+	}
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = num(float64(p[0])+shift) + "," + num(float64(p[1])+shift)
+	}
+	d.element(`<polygon points="%s" %s/>`, strings.Join(coords, " "), style)
+}
+
+func (d *Document) Text(text string, x, y int, painter *cv.TextPainter) {
+	family := "sans-serif"
+	if painter.Font != nil {
+		if name := painter.Font.Name(truetype.NameIDFontFamily); name != "" {
+			family = name + ", sans-serif"
+		}
+	}
+	col := color.RGBAModel.Convert(painter.TextColor).(color.RGBA)
+	// DrawText places the baseline one font size below y
+	d.element(`<text x="%d" y="%s" font-family="%s" font-size="%s" xml:space="preserve" %s>%s</text>`,
+		x, num(float64(y)+painter.FontSize), html.EscapeString(family), num(painter.FontSize), paint("fill", col), html.EscapeString(text))
+}
+
+func (d *Document) RoundedRect(x, y, w, h int, radii cv.Radii, shader cv.Shader) {
+	var fill string
+	switch s := shader.(type) {
+	case cv.SolidColor:
+		fill = paint("fill", s.Color)
+	case cv.LinearGradient:
+		id := d.id("gradient")
+		fmt.Fprintf(&d.defs, `    <linearGradient id="%s" x1="%s" y1="%s" x2="%s" y2="%s">`+"\n",
+			id, num(s.Start[0]), num(s.Start[1]), num(s.End[0]), num(s.End[1]))
+		d.stops(cv.SRGBStops(s.Stops, s.Interpolation))
+		d.defs.WriteString("    </linearGradient>\n")
+		fill = fmt.Sprintf(`fill="url(#%s)"`, id)
+	case cv.RadialGradient:
+		id := d.id("gradient")
+		fmt.Fprintf(&d.defs, `    <radialGradient id="%s" cx="%s" cy="%s" r="%s">`+"\n",
+			id, num(s.Center[0]), num(s.Center[1]), num(s.Radius))
+		d.stops(cv.SRGBStops(s.Stops, s.Interpolation))
+		d.defs.WriteString("    </radialGradient>\n")
+		fill = fmt.Sprintf(`fill="url(#%s)"`, id)
+	default:
+		// Other shaders can only be sampled, so embed them as pixels
+		d.rasterize(x, y, w, h, 0, func(c *cv.Canvas, x, y int) {
+			c.FillRoundedRect(x, y, w, h, radii, shader)
+		})
+		return
+	}
+	d.element("%s %s/>", shape(float64(x), float64(y), float64(w), float64(h), radii), fill)
+}
+
+// stops writes the stops of a gradient into the defs
+func (d *Document) stops(stops []cv.GradientStop) {
+	for _, stop := range stops {
+		fmt.Fprintf(&d.defs, `      <stop offset="%s" %s/>`+"\n", num(stop.Offset), paint("stop-color", stop.Color))
+	}
+}
+
+func (d *Document) RoundedBorder(x, y, w, h int, radii cv.Radii, sides cv.BorderSides) {
+	side := sides.Top
+	if sides != cv.UniformBorder(side) || side.Style == cv.BorderStyleNone || side.Width <= 0 || !radiiUniform(radii) {
+		// Mixed sides and corners are drawn pixel by pixel
+		d.rasterize(x, y, w, h, 0, func(c *cv.Canvas, x, y int) {
+			c.RoundedBorder(x, y, w, h, radii, sides)
+		})
+		return
+	}
+
+	// A uniform border is a stroke centered half its width inside the box
+	half := float64(side.Width) / 2
+	radius := max(float64(radii.TopLeft)-half, 0)
+	dash := ""
+	if on, off := side.Style.Dashes(side.Width); on > 0 {
+		dash = fmt.Sprintf(` stroke-dasharray="%d %d"`, on, off)
+	}
+	d.element(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s" fill="none" stroke-width="%d"%s %s/>`,
+		num(float64(x)+half), num(float64(y)+half), num(float64(w)-2*half), num(float64(h)-2*half), num(radius),
+		side.Width, dash, paint("stroke", side.Color))
+}
+
+func (d *Document) BoxShadow(x, y, w, h int, radii cv.Radii, shadow cv.BoxShadow) {
+	// Shadows are blurred pixels; leave room for the blur, spread and offset around the box
+	margin := 0
+	if !shadow.Inset {
+		margin = 2*max(shadow.Blur, 0) + max(shadow.Spread, 0) + max(abs(shadow.OffsetX), abs(shadow.OffsetY)) + 2
+	}
+	d.rasterize(x, y, w, h, margin, func(c *cv.Canvas, x, y int) {
+		c.DrawBoxShadow(x, y, w, h, radii, shadow)
+	})
+}
+
+func (d *Document) Image(x, y int, size types.Size, img image.Image) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return
+	}
+	d.element(`<image x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="none" xlink:href="data:image/png;base64,%s"/>`,
+		x, y, size.Width, size.Height, base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// Clip groups the elements that follow under a clip path of bounds, so drawing that
+// overflows its canvas is cut off like on raster canvases
+func (d *Document) Clip(bounds image.Rectangle) {
+	if bounds == d.clip {
+		return
+	}
+	if !d.clip.Empty() {
+		d.depth--
+		d.element("</g>")
+	}
+	d.clip = bounds
+	if bounds.Empty() {
+		return
+	}
+
+	id := d.id("clip")
+	fmt.Fprintf(&d.defs, `    <clipPath id="%s"><rect x="%d" y="%d" width="%d" height="%d"/></clipPath>`+"\n",
+		id, bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy())
+	d.element(`<g clip-path="url(#%s)">`, id)
+	d.depth++
+}
+
+func (d *Document) BeginGroup(transform cv.Matrix, opacity float64, mode cv.BlendMode) {
+	var attrs strings.Builder
+	if !transform.IsIdentity() {
+		m := transform
+		fmt.Fprintf(&attrs, ` transform="matrix(%s %s %s %s %s %s)"`, num(m.A), num(m.B), num(m.C), num(m.D), num(m.E), num(m.F))
+	}
+	if opacity < 1 {
+		fmt.Fprintf(&attrs, ` opacity="%s"`, num(max(opacity, 0)))
+	}
+	if name := blendModes[mode]; name != "" {
+		fmt.Fprintf(&attrs, ` style="mix-blend-mode:%s"`, name)
+	}
+	d.element("<g%s>", attrs.String())
+	d.depth++
+	d.clips = append(d.clips, d.clip)
+	d.clip = image.Rectangle{}
+}
+
+func (d *Document) EndGroup() {
+	if len(d.clips) == 0 {
+		return
+	}
+	d.Clip(image.Rectangle{})
+	d.depth--
+	d.element("</g>")
+	d.clip = d.clips[len(d.clips)-1]
+	d.clips = d.clips[:len(d.clips)-1]
+}
+
+// rasterize paints an area of the document into pixels and embeds them as an image.
+// paint receives the position of the area inside the temporary canvas.
+func (d *Document) rasterize(x, y, w, h, margin int, paint func(c *cv.Canvas, x, y int)) {
+	size := types.Size{Width: w + 2*margin, Height: h + 2*margin}
+	if limit := d.PixelLimit; limit > 0 && float64(size.Width)*float64(size.Height) > float64(limit) {
+		panic(&cv.LimitError{Size: size, PixelRatio: 1, Limit: limit})
+	}
+	c := cv.NewCanvas(size, true)
+	c.SetPixelLimit(d.PixelLimit)
+	c.SetDeadline(d.Deadline)
+	paint(c, margin, margin)
+	d.Image(x-margin, y-margin, size, c.Img)
+}
+
+var blendModes = map[cv.BlendMode]string{
+	cv.BlendMultiply:   "multiply",
+	cv.BlendScreen:     "screen",
+	cv.BlendOverlay:    "overlay",
+	cv.BlendDarken:     "darken",
+	cv.BlendLighten:    "lighten",
+	cv.BlendDifference: "difference",
+	cv.BlendAdd:        "plus-lighter",
+}
+
+// paint returns the attributes painting a property, such as fill or stroke, with a premultiplied color
+func paint(property string, c color.RGBA) string {
+	if c.A == 0 {
+		return property + `="none"`
+	}
+	unpremultiply := func(v uint8) int {
+		return int(math.Round(float64(v) * 255 / float64(c.A)))
+	}
+	attr := fmt.Sprintf(`%s="#%02x%02x%02x"`, property, unpremultiply(c.R), unpremultiply(c.G), unpremultiply(c.B))
+	if c.A < 255 {
+		opacity := property + "-opacity"
+		if property == "stop-color" {
+			opacity = "stop-opacity"
+		}
+		attr += fmt.Sprintf(` %s="%s"`, opacity, num(float64(c.A)/255))
+	}
+	return attr
+}
+
+// shape returns the opening of an element outlining a rounded rectangle, without its paint
+func shape(x, y, w, h float64, radii cv.Radii) string {
+	if radiiUniform(radii) {
+		rx := ""
+		if radii.TopLeft > 0 {
+			rx = fmt.Sprintf(` rx="%s"`, num(min(float64(radii.TopLeft), w/2, h/2)))
+		}
+		return fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s"%s`, num(x), num(y), num(w), num(h), rx)
+	}
+
+	// Corners that don't fit are scaled down together, like the raster renderer does
+	tl, tr, br, bl := float64(radii.TopLeft), float64(radii.TopRight), float64(radii.BottomRight), float64(radii.BottomLeft)
+	scale := 1.0
+	for _, pair := range [][3]float64{{tl, tr, w}, {bl, br, w}, {tl, bl, h}, {tr, br, h}} {
+		if sum := pair[0] + pair[1]; sum > pair[2] {
+			scale = min(scale, pair[2]/sum)
+		}
+	}
+	tl, tr, br, bl = tl*scale, tr*scale, br*scale, bl*scale
+
+	corner := func(r, x, y float64) string {
+		if r <= 0 {
+			return ""
+		}
+		return fmt.Sprintf(" A%s,%s 0 0 1 %s,%s", num(r), num(r), num(x), num(y))
+	}
+	path := fmt.Sprintf("M%s,%s", num(x+tl), num(y)) +
+		fmt.Sprintf(" H%s", num(x+w-tr)) + corner(tr, x+w, y+tr) +
+		fmt.Sprintf(" V%s", num(y+h-br)) + corner(br, x+w-br, y+h) +
+		fmt.Sprintf(" H%s", num(x+bl)) + corner(bl, x, y+h-bl) +
+		fmt.Sprintf(" V%s", num(y+tl)) + corner(tl, x+tl, y) + " Z"
+	return fmt.Sprintf(`<path d="%s"`, path)
+}
+
+func radiiUniform(r cv.Radii) bool {
+	return r.TopLeft == r.TopRight && r.TopLeft == r.BottomRight && r.TopLeft == r.BottomLeft
+}
+
+// num formats a coordinate with at most three decimals
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,136 @@
+package svg
+
+import (
+	"encoding/xml"
+	"image/color"
+	"strings"
+	"testing"
+
+	cv "github.com/hvuhsg/render/canvas"
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+func TestDocumentShapes(t *testing.T) {
+	doc := New(types.Size{Width: 100, Height: 50})
+	canvas := doc.Canvas()
+	red := color.RGBA{255, 0, 0, 255}
+
+	canvas.Rectangle(10, 10, 20, 20, red, true)
+	sub := canvas.SubCanvas(40, 5, types.Size{Width: 40, Height: 40}, nil)
+	sub.Circle(10, 10, 5, red, true)
+	sub.Line(0, 0, 10, 10, red, 2)
+	sub.Polygon([][2]int{{0, 0}, {10, 0}, {5, 5}}, color.RGBA{0, 0, 128, 128}, true)
+	sub.DrawText("a < b", 0, 20, nil)
+
+	out := doc.String()
+	for _, expected := range []string{
+		`<rect x="10" y="10" width="20" height="20" fill="#ff0000"/>`,
+		`<circle cx="50.5" cy="15.5"`,
+		`<line x1="40.5" y1="5.5" x2="50.5" y2="15.5" stroke-width="2"`,
+		`fill="#0000ff" fill-opacity="0.502"`,
+		`y="37" font-family="Go, sans-serif" font-size="12"`,
+		`>a &lt; b</text>`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	if err := xml.Unmarshal([]byte(out), new(any)); err != nil {
+		t.Errorf("Expected well formed XML, got %v", err)
+	}
+}
+
+func TestDocumentGradientsAndGroups(t *testing.T) {
+	doc := New(types.Size{Width: 100, Height: 100})
+	canvas := doc.Canvas()
+
+	gradient := cv.LinearGradient{
+		End:   [2]float64{1, 0},
+		Stops: []cv.GradientStop{{Offset: 0, Color: color.RGBA{255, 0, 0, 255}}, {Offset: 1, Color: color.RGBA{0, 0, 255, 255}}},
+	}
+	canvas.PaintTransformed(types.Size{Width: 20, Height: 20}, cv.Identity().Translate(10, 0), func(c *cv.Canvas) {
+		c.FillRoundedRect(0, 0, 20, 20, cv.RadiiAll(4), gradient)
+	})
+
+	out := doc.String()
+	for _, expected := range []string{
+		`<linearGradient id="gradient1" x1="0" y1="0" x2="1" y2="0">`,
+		`<stop offset="1" stop-color="#0000ff"/>`,
+		`<g transform="matrix(1 0 0 1 10 0)">`,
+		`rx="4" fill="url(#gradient1)"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestDocumentClipping(t *testing.T) {
+	doc := New(types.Size{Width: 100, Height: 100})
+	red := color.RGBA{255, 0, 0, 255}
+	sub := doc.Canvas().SubCanvas(40, 5, types.Size{Width: 40, Height: 40}, nil)
+
+	sub.Rectangle(0, 0, 10, 10, red, true)
+	sub.Rectangle(30, 30, 20, 20, red, true)
+	sub.Rectangle(35, 35, 20, 20, red, true)
+	sub.PaintLayer(0.5, cv.BlendNormal, func(c *cv.Canvas) {
+		c.Rectangle(-5, 0, 10, 10, red, true)
+	})
+
+	// Only the rectangles overflowing the sub canvas are clipped, together, like on a raster canvas
+	out := doc.String()
+	expected := `    <clipPath id="clip1"><rect x="40" y="5" width="40" height="40"/></clipPath>
+    <clipPath id="clip2"><rect x="40" y="5" width="40" height="40"/></clipPath>
+  </defs>
+  <rect x="40" y="5" width="10" height="10" fill="#ff0000"/>
+  <g clip-path="url(#clip1)">
+    <rect x="70" y="35" width="20" height="20" fill="#ff0000"/>
+    <rect x="75" y="40" width="20" height="20" fill="#ff0000"/>
+  </g>
+  <g opacity="0.5">
+    <g clip-path="url(#clip2)">
+      <rect x="35" y="5" width="10" height="10" fill="#ff0000"/>
+    </g>
+  </g>
+`
+	if !strings.Contains(out, expected) {
+		t.Errorf("Expected output to contain\n%s\ngot:\n%s", expected, out)
+	}
+	if err := xml.Unmarshal([]byte(out), new(any)); err != nil {
+		t.Errorf("Expected well formed XML, got %v", err)
+	}
+}
+
+func TestRenderTree(t *testing.T) {
+	tree := &render_objects.Opacity{
+		Opacity: 0.5,
+		Child: &render_objects.Container{
+			Decoration: render_objects.BoxDecoration{
+				Background: cv.SolidColor{Color: color.RGBA{0, 128, 0, 255}},
+				Shadows:    []cv.BoxShadow{{Color: color.RGBA{0, 0, 0, 128}, Blur: 4}},
+			},
+			Child: render_objects.NewText("Hello", color.Black, 16, "default"),
+		},
+	}
+
+	var sb strings.Builder
+	if err := Render(&sb, tree, types.Size{Width: 200, Height: 100}); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+
+	if !strings.Contains(out, `<g opacity="0.5">`) {
+		t.Error("Expected the opacity to become a group")
+	}
+	if !strings.Contains(out, "data:image/png;base64,") {
+		t.Error("Expected the shadow to be embedded as an image")
+	}
+	if !strings.Contains(out, ">Hello</text>") {
+		t.Error("Expected the text to stay text")
+	}
+	if err := xml.Unmarshal([]byte(out), new(any)); err != nil {
+		t.Errorf("Expected well formed XML, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package svg
+
+import (
+	"io"
+
+	"github.com/hvuhsg/render/render_objects"
+	"github.com/hvuhsg/render/types"
+)
+
+// Render paints root onto an SVG document of the given size and writes it to w.
+// The tree is laid out and painted exactly as it would be on a raster canvas.
+func Render(w io.Writer, root render_objects.RenderObject, size types.Size) error {
+	doc := New(size)
+	root.Paint(doc.Canvas())
+	_, err := doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,103 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+const (
+	upperHalf = "▀"
+	lowerHalf = "▄"
+	reset     = "\x1b[0m"
+)
+
+// writeBlocks prints two rows of pixels per line of text: the upper half block character
+// takes the top pixel as its foreground and the bottom pixel as its background
+func writeBlocks(out *bufio.Writer, img *image.NRGBA, mode Mode, background color.Color) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		// Colors carry over between cells, so only write them when they change
+		current := reset
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top, topVisible := flatten(img.NRGBAAt(x, y), background)
+			bottom, bottomVisible := color.NRGBA{}, false
+			if y+1 < bounds.Max.Y {
+				bottom, bottomVisible = flatten(img.NRGBAAt(x, y+1), background)
+			}
+
+			style, char := reset, " "
+			switch {
+			case topVisible && bottomVisible:
+				style, char = sgr(38, top, mode)+sgr(48, bottom, mode), upperHalf
+			case topVisible:
+				style, char = reset+sgr(38, top, mode), upperHalf
+			case bottomVisible:
+				style, char = reset+sgr(38, bottom, mode), lowerHalf
+			}
+			if style != current {
+				if current == reset {
+					out.WriteString(strings.TrimPrefix(style, reset))
+				} else {
+					out.WriteString(style)
+				}
+				current = style
+			}
+			out.WriteString(char)
+		}
+		if current != reset {
+			out.WriteString(reset)
+		}
+		out.WriteString("\n")
+	}
+}
+
+// sgr returns the escape sequence setting the foreground (38) or background (48) color
+func sgr(target int, c color.NRGBA, mode Mode) string {
+	if mode == Mode256 {
+		return fmt.Sprintf("\x1b[%d;5;%dm", target, xterm256(c))
+	}
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", target, c.R, c.G, c.B)
+}
+
+// Channel levels of the xterm 6x6x6 color cube
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// xterm256 returns the closest color of the xterm palette, from its color cube or gray ramp
+func xterm256(c color.NRGBA) int {
+	nearestLevel := func(v uint8) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(int(v)-level) < abs(int(v)-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	r, g, b := nearestLevel(c.R), nearestLevel(c.G), nearestLevel(c.B)
+	cube := 16 + 36*r + 6*g + b
+	cubeDistance := distance(c, cubeLevels[r], cubeLevels[g], cubeLevels[b])
+
+	// The gray ramp runs from 8 to 238 in steps of 10
+	average := (int(c.R) + int(c.G) + int(c.B)) / 3
+	step := min(max((average-8+5)/10, 0), 23)
+	grayLevel := 8 + 10*step
+	if distance(c, grayLevel, grayLevel, grayLevel) < cubeDistance {
+		return 232 + step
+	}
+	return cube
+}
+
+func distance(c color.NRGBA, r, g, b int) int {
+	dr, dg, db := int(c.R)-r, int(c.G)-g, int(c.B)-b
+	return dr*dr + dg*dg + db*db
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,93 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	cv "github.com/hvuhsg/render/canvas"
+)
+
+// writeSixel prints the image as sixel graphics with a palette of up to 256 colors.
+// Transparent pixels are left unpainted.
+func writeSixel(out *bufio.Writer, img *image.NRGBA, background color.Color) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Flatten translucency first, sixel pixels are either painted or not
+	flat := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c, _ := flatten(img.NRGBAAt(x, y), background)
+			flat.SetNRGBA(x, y, c)
+		}
+	}
+	palette := cv.Quantize(flat, 256)
+	paletted := image.NewPaletted(bounds, palette)
+	draw.FloydSteinberg.Draw(paletted, bounds, flat, bounds.Min)
+
+	// Enter sixel mode with transparent background pixels, then the raster size and the palette in percent
+	fmt.Fprintf(out, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for i, c := range palette {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(out, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	// Each band covers six rows; every color used in a band is drawn as one pass over it
+	transparent := func(i uint8) bool {
+		_, _, _, a := palette[i].RGBA()
+		return a == 0
+	}
+	for band := 0; band < h; band += 6 {
+		used := map[uint8]bool{}
+		var order []uint8
+		for y := band; y < min(band+6, h); y++ {
+			for x := range w {
+				i := paletted.ColorIndexAt(x, y)
+				if !used[i] && !transparent(i) {
+					used[i] = true
+					order = append(order, i)
+				}
+			}
+		}
+
+		for n, index := range order {
+			if n > 0 {
+				out.WriteByte('$') // Back to the start of the band
+			}
+			fmt.Fprintf(out, "#%d", index)
+			run, last := 0, byte(0)
+			for x := range w {
+				bits := byte(0)
+				for bit := range 6 {
+					if y := band + bit; y < h && paletted.ColorIndexAt(x, y) == index {
+						bits |= 1 << bit
+					}
+				}
+				char := '?' + bits
+				if run > 0 && char != last {
+					writeRun(out, last, run)
+					run = 0
+				}
+				last = char
+				run++
+			}
+			writeRun(out, last, run)
+		}
+		out.WriteByte('-') // Next band
+	}
+	out.WriteString("\x1b\\")
+}
+
+// writeRun writes a sixel character repeated count times
+func writeRun(out *bufio.Writer, char byte, count int) {
+	if count > 3 {
+		fmt.Fprintf(out, "!%d%c", count, char)
+		return
+	}
+	for range count {
+		out.WriteByte(char)
+	}
+}
@@ -0,0 +1,128 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Mode selects how pixels are written to the terminal
+type Mode int
+
+const (
+	ModeTrueColor Mode = iota // 24-bit ANSI colors, two pixels per character cell
+	Mode256                   // xterm 256 color palette, two pixels per character cell
+	ModeSixel                 // Sixel graphics, one terminal pixel per image pixel
+)
+
+// Pixel width assumed for a character cell when sizing sixel images
+const sixelCellWidth = 10
+
+// Options controls how an image is printed
+type Options struct {
+	Mode Mode
+	// Width available in character cells. Zero uses the COLUMNS environment variable, or 80.
+	// Larger images are scaled down to fit; smaller ones are printed as is.
+	Width int
+	// Color translucent pixels are blended onto. Fully transparent pixels keep the
+	// terminal's own background in the half block modes.
+	Background color.Color
+}
+
+// ParseMode returns the mode with the given name: truecolor, 256 or sixel.
+// An empty name or "auto" detects the mode from the environment.
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return DetectMode(), nil
+	case "truecolor", "24bit":
+		return ModeTrueColor, nil
+	case "256":
+		return Mode256, nil
+	case "sixel":
+		return ModeSixel, nil
+	}
+	return 0, fmt.Errorf("unknown terminal mode %q", name)
+}
+
+// DetectMode guesses the best mode the terminal supports from its environment.
+// Sixel support can't be detected this way and has to be asked for.
+func DetectMode() Mode {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ModeTrueColor
+	}
+	return Mode256
+}
+
+// Width returns the width of the terminal in character cells from the COLUMNS environment variable
+func Width() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 80
+}
+
+// Print writes img to w as terminal output. A nil options uses true color at the terminal width.
+func Print(w io.Writer, img image.Image, options *Options) error {
+	if options == nil {
+		options = &Options{}
+	}
+	columns := options.Width
+	if columns <= 0 {
+		columns = Width()
+	}
+
+	out := bufio.NewWriter(w)
+	switch options.Mode {
+	case ModeSixel:
+		writeSixel(out, fit(img, columns*sixelCellWidth), options.Background)
+	default:
+		writeBlocks(out, fit(img, columns), options.Mode, options.Background)
+	}
+	return out.Flush()
+}
+
+// fit scales img down to at most width pixels wide, keeping its aspect ratio
+func fit(img image.Image, width int) *image.NRGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > width && width > 0 {
+		h = max(h*width/w, 1)
+		w = width
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if w == bounds.Dx() && h == bounds.Dy() {
+		draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+	} else {
+		draw.BiLinear.Scale(out, out.Bounds(), img, bounds, draw.Src, nil)
+	}
+	return out
+}
+
+// flatten blends a translucent color onto the background; transparent pixels report false
+func flatten(c color.NRGBA, background color.Color) (color.NRGBA, bool) {
+	if c.A == 0 {
+		return c, false
+	}
+	if c.A == 255 {
+		return c, true
+	}
+	if background == nil {
+		background = color.Black
+	}
+	br, bg, bb, _ := background.RGBA()
+	a := float64(c.A) / 255
+	mix := func(v uint8, b uint32) uint8 {
+		return uint8(float64(v)*a + float64(b>>8)*(1-a) + 0.5)
+	}
+	return color.NRGBA{R: mix(c.R, br), G: mix(c.G, bg), B: mix(c.B, bb), A: 255}, true
+}
@@ -0,0 +1,89 @@
+package terminal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func twoPixels(top, bottom color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 2))
+	img.SetNRGBA(0, 0, top)
+	img.SetNRGBA(0, 1, bottom)
+	return img
+}
+
+func TestPrintTrueColor(t *testing.T) {
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+
+	var buf bytes.Buffer
+	if err := Print(&buf, twoPixels(red, blue), &Options{Width: 10}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	// Transparent halves keep the terminal background
+	buf.Reset()
+	Print(&buf, twoPixels(color.NRGBA{}, blue), &Options{Width: 10})
+	if !strings.Contains(buf.String(), "\x1b[38;2;0;0;255m▄") {
+		t.Errorf("Expected a lower half block for a transparent top pixel, got %q", buf.String())
+	}
+}
+
+func TestPrint256(t *testing.T) {
+	var buf bytes.Buffer
+	Print(&buf, twoPixels(color.NRGBA{255, 0, 0, 255}, color.NRGBA{128, 128, 128, 255}), &Options{Mode: Mode256, Width: 10})
+	if !strings.Contains(buf.String(), "\x1b[38;5;196m") || !strings.Contains(buf.String(), "\x1b[48;5;244m") {
+		t.Errorf("Expected red and gray from the xterm palette, got %q", buf.String())
+	}
+}
+
+func TestPrintScalesToWidth(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	var buf bytes.Buffer
+	Print(&buf, img, &Options{Width: 20})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Errorf("Expected 40x20 scaled to 20x10, printed as 5 lines, got %d", len(lines))
+	}
+	if cells := strings.Count(lines[0], " "); cells != 20 {
+		t.Errorf("Expected 20 cells per line, got %d", cells)
+	}
+}
+
+func TestPrintSixel(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 7))
+	for y := range 7 {
+		for x := range 8 {
+			img.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	Print(&buf, img, &Options{Mode: ModeSixel, Width: 10})
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1bP0;1;0q\"1;1;8;7") || !strings.HasSuffix(out, "\x1b\\") {
+		t.Fatalf("Expected a sixel sequence with the raster size, got %q", out)
+	}
+	// All six rows of the first band, then the single row left in the second
+	if !strings.Contains(out, "#0;2;100;0;0") || !strings.Contains(out, "#0!8~-#0!8@-") {
+		t.Errorf("Expected two bands of red sixels, got %q", out)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for name, expected := range map[string]Mode{"truecolor": ModeTrueColor, "256": Mode256, "Sixel": ModeSixel} {
+		if mode, err := ParseMode(name); err != nil || mode != expected {
+			t.Errorf("Expected %q to be mode %d, got %d (%v)", name, expected, mode, err)
+		}
+	}
+	if _, err := ParseMode("vga"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
@@ -1,5 +1,7 @@
 package types
 
+import "fmt"
+
 type MainAxisAlignment int
 type MainAxisSize int
 
@@ -16,3 +18,31 @@ const (
 	MainAxisSizeMin MainAxisSize = iota // Takes minimum size needed for children
 	MainAxisSizeMax                     // Takes maximum size (parent size)
 )
+
+func (a MainAxisAlignment) String() string {
+	switch a {
+	case MainAxisAlignmentStart:
+		return "start"
+	case MainAxisAlignmentCenter:
+		return "center"
+	case MainAxisAlignmentEnd:
+		return "end"
+	case MainAxisAlignmentSpaceBetween:
+		return "spaceBetween"
+	case MainAxisAlignmentSpaceAround:
+		return "spaceAround"
+	case MainAxisAlignmentSpaceEvenly:
+		return "spaceEvenly"
+	}
+	return fmt.Sprintf("MainAxisAlignment(%d)", int(a))
+}
+
+func (s MainAxisSize) String() string {
+	switch s {
+	case MainAxisSizeMin:
+		return "min"
+	case MainAxisSizeMax:
+		return "max"
+	}
+	return fmt.Sprintf("MainAxisSize(%d)", int(s))
+}
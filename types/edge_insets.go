@@ -0,0 +1,43 @@
+package types
+
+import "fmt"
+
+// EdgeInsets describes an offset on each of the four sides of a box
+type EdgeInsets struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
+// EdgeInsetsAll returns insets with the same value on every side
+func EdgeInsetsAll(value int) EdgeInsets {
+	return EdgeInsets{Top: value, Right: value, Bottom: value, Left: value}
+}
+
+// EdgeInsetsSymmetric returns insets with equal vertical and equal horizontal values
+func EdgeInsetsSymmetric(vertical, horizontal int) EdgeInsets {
+	return EdgeInsets{Top: vertical, Right: horizontal, Bottom: vertical, Left: horizontal}
+}
+
+// Horizontal returns the sum of the left and right insets
+func (e EdgeInsets) Horizontal() int {
+	return e.Left + e.Right
+}
+
+// Vertical returns the sum of the top and bottom insets
+func (e EdgeInsets) Vertical() int {
+	return e.Top + e.Bottom
+}
+
+// String lists the insets like CSS: one value when all sides are equal, vertical and
+// horizontal when those are, and top, right, bottom and left otherwise
+func (e EdgeInsets) String() string {
+	switch {
+	case e.Top == e.Bottom && e.Left == e.Right && e.Top == e.Left:
+		return fmt.Sprintf("%d", e.Top)
+	case e.Top == e.Bottom && e.Left == e.Right:
+		return fmt.Sprintf("%d %d", e.Top, e.Left)
+	}
+	return fmt.Sprintf("%d %d %d %d", e.Top, e.Right, e.Bottom, e.Left)
+}